@@ -2,12 +2,214 @@ package mkdoc
 
 import (
 	"github.com/thewinds/mkdoc/schema"
+	"strings"
 )
 
-// API def
+// API def,
+// the arguments may be in the languages other than api,eg. `@in proto:user.v1.User`
+// the refs of argument are looked up by the language of argument
 type API struct {
 	schema.API
 	InArgument  *Object `json:"in_argument"`
+	InLanguage  string  `json:"in_language"`
 	OutArgument *Object `json:"out_argument"`
+	OutLanguage string  `json:"out_language"`
 	Mime        *MimeType
+	Responses   []*Response  `json:"responses"`
+	Errors      []*ErrorCode `json:"errors"`
+}
+
+// Response is a response of api,Argument is the object of response body in Language,
+// Example is the json body declared by `@example out`
+type Response struct {
+	Status   string  `json:"status"`
+	Desc     string  `json:"desc"`
+	Mime     string  `json:"mime"`
+	Argument *Object `json:"argument"`
+	Language string  `json:"language"`
+	Example  string  `json:"example"`
+}
+
+// SuccessResponse returns the first 2xx response,returns nil if not found
+func (api *API) SuccessResponse() *Response {
+	for _, r := range api.Responses {
+		if strings.HasPrefix(r.Status, "2") {
+			return r
+		}
+	}
+	return nil
+}
+
+// AllResponses returns the responses of api,
+// the out argument is returned as the 200 response if there is no response
+func (api *API) AllResponses() []*Response {
+	if len(api.Responses) > 0 {
+		return api.Responses
+	}
+	mime := ""
+	if api.Mime != nil {
+		mime = api.Mime.Out
+	}
+	return []*Response{{Status: "200", Mime: mime, Argument: api.OutArgument, Language: api.OutLanguage}}
+}
+
+// Title returns the status and description of response,eg. 404 用户不存在
+func (r *Response) Title() string {
+	return strings.TrimSpace(r.Status + " " + r.Desc)
+}
+
+// ReplacePathParams replace the parameter segments of router style path by fn
+// and returns the parameter names,the segments are like :uid,*path,{uid} and {uid:[0-9]+}
+//
+//	/user/:uid/files/*path => /user/{uid}/files/{path} if fn returns {name}
+func ReplacePathParams(path string, fn func(name string) string) (string, []string) {
+	var params []string
+	segments := strings.Split(path, "/")
+	for i, seg := range segments {
+		if len(seg) < 2 {
+			continue
+		}
+		var name string
+		switch {
+		case seg[0] == ':' || seg[0] == '*':
+			name = seg[1:]
+		case seg[0] == '{' && seg[len(seg)-1] == '}':
+			// the variable of postman is like {{uid}}
+			name = strings.Trim(seg, "{}")
+			// the regexp of gorilla/mux,eg. {uid:[0-9]+}
+			if j := strings.Index(name, ":"); j != -1 {
+				name = name[:j]
+			}
+		default:
+			continue
+		}
+		if name == "" {
+			continue
+		}
+		params = append(params, name)
+		segments[i] = fn(name)
+	}
+	return strings.Join(segments, "/"), params
+}
+
+// pathParams returns the parameters of path in order and the names of the dropped parameters,
+// the parameters not declared are derived from path as string,
+// the declared parameters not in path are dropped
+func pathParams(path string, declared schema.Params) (schema.Params, []string) {
+	_, names := ReplacePathParams(path, func(name string) string { return name })
+	var params schema.Params
+	for _, name := range names {
+		if params.Get(name) == nil {
+			params = append(params, &schema.Param{Name: name})
+		}
+	}
+	var inPath schema.Params
+	var dropped []string
+	for _, p := range declared {
+		if params.Get(p.Name) == nil {
+			dropped = append(dropped, p.Name)
+			continue
+		}
+		inPath = append(inPath, p)
+	}
+	params = mergeParams(params, inPath)
+	for _, name := range names {
+		params.Get(name).Required = true
+	}
+	return params, dropped
+}
+
+// mergeParams returns a copy of params merged with the declared params,
+// the declared params replace the params with the same name,
+// the type is string if it is omitted
+func mergeParams(params, declared schema.Params) schema.Params {
+	merged := make(schema.Params, 0, len(params)+len(declared))
+	for _, p := range params {
+		v := *p
+		merged = append(merged, &v)
+	}
+	for _, p := range declared {
+		v := *p
+		if exist := merged.Get(p.Name); exist != nil {
+			*exist = v
+			continue
+		}
+		merged = append(merged, &v)
+	}
+	for _, p := range merged {
+		if p.Type == "" {
+			p.Type = "string"
+		}
+	}
+	return merged
+}
+
+// structParams returns the parameters of struct fields,
+// the name of parameter is the form or query tag of field,
+// the fields of object and map types are ignored
+func structParams(obj *Object, ref func(id string) *Object) schema.Params {
+	var params schema.Params
+	for _, field := range obj.Fields {
+		name := field.Name
+		var tag *ObjectFieldTag
+		if e := FindExtension[*ExtensionGoTag](field.Extensions); e != nil {
+			tag = e.Tag
+		}
+		for _, tagName := range []string{"form", "query"} {
+			if v := tag.GetFirstValue(tagName, ","); v != "" {
+				name = v
+				break
+			}
+		}
+		if name == "-" {
+			continue
+		}
+		param := &schema.Param{Name: name, Desc: field.Desc}
+		exts := append([]Extension{}, field.Extensions...)
+		typ, dims := field.Type, ""
+		for typ.Name == "object" && typ.Ref != "" && typ.MapKey == "" {
+			o := ref(typ.Ref)
+			if o == nil {
+				break
+			}
+			if o.Type.IsRepeated {
+				dims += "[]"
+			}
+			exts = append(exts, o.Extensions...)
+			typ = o.Type
+		}
+		if typ.Name == "object" || typ.MapKey != "" {
+			continue
+		}
+		param.Type = dims + typ.Name
+		for _, ext := range exts {
+			switch e := ext.(type) {
+			case *ExtensionConstraints:
+				param.Required = e.Required
+			case *ExtensionRules:
+				if len(e.Enum) > 0 {
+					param.Enum = e.Enum
+				}
+			case *ExtensionEnum:
+				if len(param.Enum) == 0 {
+					for _, v := range e.Values {
+						param.Enum = append(param.Enum, v.Value)
+					}
+				}
+			}
+		}
+		param.Default = tagDefault(tag)
+		params = append(params, param)
+	}
+	return params
+}
+
+// tagDefault returns the default value declared in the form tag of gin,eg. form:"page,default=1"
+func tagDefault(tag *ObjectFieldTag) string {
+	for _, opt := range strings.Split(tag.GetValue("form"), ",")[1:] {
+		if strings.HasPrefix(opt, "default=") {
+			return strings.TrimPrefix(opt, "default=")
+		}
+	}
+	return ""
 }
@@ -0,0 +1,76 @@
+package mkdoc
+
+import (
+	"github.com/thewinds/mkdoc/schema"
+	"reflect"
+	"testing"
+)
+
+func TestPathParams(t *testing.T) {
+	declared := []*schema.Param{
+		{Name: "uid", Type: "int", Desc: "用户id"},
+		{Name: "extra", Desc: "not in path"},
+	}
+	params, dropped := pathParams("/user/:uid/files/{name:[a-z]+}/*path", declared)
+	if !reflect.DeepEqual(dropped, []string{"extra"}) {
+		t.Errorf("dropped got %v, want [extra]", dropped)
+	}
+	want := []schema.Param{
+		{Name: "uid", Type: "int", Desc: "用户id", Required: true},
+		{Name: "name", Type: "string", Required: true},
+		{Name: "path", Type: "string", Required: true},
+	}
+	if len(params) != len(want) {
+		t.Fatalf("params got %d, want %d", len(params), len(want))
+	}
+	for i, p := range params {
+		if !reflect.DeepEqual(*p, want[i]) {
+			t.Errorf("param %d got %+v, want %+v", i, *p, want[i])
+		}
+	}
+	if declared[1].Type != "" {
+		t.Error("declared params are modified")
+	}
+
+	path, _ := ReplacePathParams("/user/{{uid}}/profile", func(name string) string { return ":" + name })
+	if path != "/user/:uid/profile" {
+		t.Errorf("path got %s, want /user/:uid/profile", path)
+	}
+}
+
+func TestStructParams(t *testing.T) {
+	tag := func(raw string) []Extension {
+		return []Extension{&ExtensionGoTag{Tag: mustObjectFieldTag(raw)}}
+	}
+	refs := map[string]*Object{
+		"[]string":     {ID: "[]string", Type: &ObjectType{Name: "object", Ref: "string", IsRepeated: true}},
+		"string":       {ID: "string", Type: &ObjectType{Name: "string"}},
+		"a.Status":     {ID: "a.Status", Type: &ObjectType{Name: "int"}, Extensions: []Extension{&ExtensionEnum{Values: []*EnumValue{{Value: "1"}, {Value: "2"}}}}},
+		"a.Pagination": {ID: "a.Pagination", Type: &ObjectType{Name: "object"}},
+	}
+	obj := &Object{
+		ID:   "a.ListReq",
+		Type: &ObjectType{Name: "object"},
+		Fields: []*ObjectField{
+			{Name: "Page", Desc: "页码", Type: &ObjectType{Name: "int"}, Extensions: append(tag(`form:"page,default=1"`), &ExtensionConstraints{Required: true})},
+			{Name: "tags", Type: &ObjectType{Name: "object", Ref: "[]string"}, Extensions: tag(`query:"tag"`)},
+			{Name: "status", Type: &ObjectType{Name: "object", Ref: "a.Status"}},
+			{Name: "pagination", Type: &ObjectType{Name: "object", Ref: "a.Pagination"}},
+			{Name: "Ignored", Type: &ObjectType{Name: "string"}, Extensions: tag(`form:"-"`)},
+		},
+	}
+	params := structParams(obj, func(id string) *Object { return refs[id] })
+	want := []schema.Param{
+		{Name: "page", Type: "int", Desc: "页码", Required: true, Default: "1"},
+		{Name: "tag", Type: "[]string"},
+		{Name: "status", Type: "int", Enum: []string{"1", "2"}},
+	}
+	if len(params) != len(want) {
+		t.Fatalf("params got %d, want %d", len(params), len(want))
+	}
+	for i, p := range params {
+		if !reflect.DeepEqual(*p, want[i]) {
+			t.Errorf("param %d got %+v, want %+v", i, *p, want[i])
+		}
+	}
+}
@@ -113,6 +113,7 @@ func makeDoc(ctx *kingpin.ParseContext) error {
 		APIs:   apis,
 		Config: *config,
 		RefObj: project.Objects(),
+		Errors: project.ErrorCodes(),
 	}
 
 	err = gen(project, genCtx)
@@ -111,6 +111,7 @@ func makeDoc() error {
 		APIs:   apis,
 		Config: *config,
 		RefObj: project.Objects(),
+		Errors: project.ErrorCodes(),
 	}
 
 	err = gen(project, genCtx)
@@ -2,9 +2,21 @@ package main
 
 import (
 	_ "github.com/thewinds/mkdoc/generator/docsify"
+	_ "github.com/thewinds/mkdoc/generator/graphql"
 	_ "github.com/thewinds/mkdoc/generator/insomnia"
 	_ "github.com/thewinds/mkdoc/generator/markdown"
+	_ "github.com/thewinds/mkdoc/generator/openapi"
+	_ "github.com/thewinds/mkdoc/generator/postman"
+	_ "github.com/thewinds/mkdoc/generator/swagger2"
 	_ "github.com/thewinds/mkdoc/objloader/goloader"
+	_ "github.com/thewinds/mkdoc/objloader/graphqlloader"
+	_ "github.com/thewinds/mkdoc/objloader/jsonloader"
+	_ "github.com/thewinds/mkdoc/objloader/openapiloader"
+	_ "github.com/thewinds/mkdoc/objloader/protoloader"
 	_ "github.com/thewinds/mkdoc/scanner/docdef"
 	_ "github.com/thewinds/mkdoc/scanner/gofunc"
+	_ "github.com/thewinds/mkdoc/scanner/graphql"
+	_ "github.com/thewinds/mkdoc/scanner/openapi"
+	_ "github.com/thewinds/mkdoc/scanner/postman"
+	_ "github.com/thewinds/mkdoc/scanner/protoservice"
 )
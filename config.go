@@ -24,6 +24,13 @@ type MimeType struct {
 	Out string `yaml:"out"`
 }
 
+// ErrorType is a type declares the error codes as constants,
+// Type is looked up in the package of File like `@errors`,the language is go if it's omitted
+type ErrorType struct {
+	File string `yaml:"file"` // errcode/code.go
+	Type string `yaml:"type"` // ErrCode
+}
+
 type Config struct {
 	Name          string            `yaml:"name"`
 	Description   string            `yaml:"desc"`
@@ -33,8 +40,11 @@ type Config struct {
 	Generator     []string          `yaml:"generator"`
 	Mime          *MimeType         `yaml:"mime"` // MimeType
 	Args          map[string]string `yaml:"args"`
+	TypeMapping   map[string]string `yaml:"type_mapping"` // time.Time: string(date-time)
+	Errors        []*ErrorType      `yaml:"errors"`       // the error types of catalog
 	scannerArgs   map[string]map[string]string
 	generatorArgs map[string]map[string]string
+	dir           string // the directory of config file
 }
 
 func (c *Config) GetScannerArgs(name string) map[string]string {
@@ -55,7 +65,7 @@ func loadConfig(fileName string) (*Config, error) {
 	if err != nil {
 		return nil, err
 	}
-	config := new(Config)
+	config := &Config{dir: path}
 	err = yaml.Unmarshal(b, config)
 	return mergeDefault(config), err
 }
@@ -18,6 +18,7 @@ type DocGenContext struct {
 	Config Config
 	RefObj map[LangObjectId]*Object
 	Args   map[string]string
+	Errors []*ErrorCode
 }
 
 var generators map[string]DocGenerator
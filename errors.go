@@ -0,0 +1,121 @@
+package mkdoc
+
+import (
+	"fmt"
+	"github.com/thewinds/mkdoc/schema"
+	"path/filepath"
+)
+
+// ErrorCode is a business error code in the error code catalog,
+// Type is the id of the type declares the code
+type ErrorCode struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+	Desc string `json:"desc"`
+	Type string `json:"type"`
+}
+
+// ErrorCodes returns the error code catalog of project in the order of declaration
+func (project *Project) ErrorCodes() []*ErrorCode {
+	return project.errorCodes
+}
+
+// errorTypeScope is the language and type scope of an error type of config
+type errorTypeScope struct {
+	lang string
+	ts   TypeScope
+}
+
+// errorTypeScopes returns the language and type scopes of the error types of config,
+// the files are relative to the directory of config file like the paths of scanners
+func (c *Config) errorTypeScopes() ([]*errorTypeScope, error) {
+	scopes := make([]*errorTypeScope, 0, len(c.Errors))
+	for _, e := range c.Errors {
+		fileName := e.File
+		if !filepath.IsAbs(fileName) {
+			if c.dir != "" {
+				fileName = filepath.Join(c.dir, fileName)
+			} else if abs, err := filepath.Abs(fileName); err == nil {
+				fileName = abs
+			} else {
+				return nil, err
+			}
+		}
+		lang, typ := TypeLang("go", e.Type)
+		scopes = append(scopes, &errorTypeScope{lang: lang, ts: TypeScope{FileName: fileName, TypeName: typ}})
+	}
+	return scopes, nil
+}
+
+// loadErrorCodes add the constants of the error types of config and apis to the error code catalog,
+// the error types of config come first and each type is added only once
+func (project *Project) loadErrorCodes(configErrors []*errorTypeScope, apis []*schema.API) error {
+	for _, e := range configErrors {
+		if err := project.loadErrorType(e.lang, e.ts); err != nil {
+			return fmt.Errorf("config errors: %v", err)
+		}
+	}
+	for _, api := range apis {
+		if len(api.ErrorType) == 0 {
+			continue
+		}
+		lang, typ := TypeLang(api.Language, api.ErrorType)
+		if err := project.loadErrorType(lang, TypeScope{api.SourceFileName, typ}); err != nil {
+			return fmt.Errorf("api %s: %v", api.Name, err)
+		}
+	}
+	return nil
+}
+
+// loadErrorType add the constants of the error type to the error code catalog
+func (project *Project) loadErrorType(lang string, ts TypeScope) error {
+	objId, err := project.getObjectId(lang, ts)
+	if err != nil {
+		return err
+	}
+	id := LangObjectId{Lang: lang, Id: objId}
+	if project.errorTypes[id] {
+		return nil
+	}
+	obj := project.GetLangObject(id)
+	var enum *ExtensionEnum
+	if obj != nil {
+		enum = FindExtension[*ExtensionEnum](obj.Extensions)
+	}
+	if enum == nil {
+		return fmt.Errorf("no error code is declared as the constant of %s", ts.TypeName)
+	}
+	project.errorTypes[id] = true
+	for _, v := range enum.Values {
+		project.errorCodes = append(project.errorCodes, &ErrorCode{Code: v.Value, Name: v.Name, Desc: v.Desc, Type: objId})
+	}
+	return nil
+}
+
+// apiErrorCodes returns the error codes referenced by api,
+// the codes of the error type of api take precedence over the others
+func (project *Project) apiErrorCodes(api *schema.API) ([]*ErrorCode, error) {
+	var errType string
+	if len(api.ErrorType) > 0 && len(api.Errors) > 0 {
+		lang, typ := TypeLang(api.Language, api.ErrorType)
+		objId, err := project.getObjectId(lang, TypeScope{api.SourceFileName, typ})
+		if err != nil {
+			return nil, err
+		}
+		errType = objId
+	}
+	var codes []*ErrorCode
+	for _, code := range api.Errors {
+		var found *ErrorCode
+		for _, e := range project.errorCodes {
+			if e.Code == code && (found == nil || e.Type == errType) {
+				found = e
+			}
+		}
+		if found == nil {
+			return nil, fmt.Errorf("api %s: error code %s is not declared", api.Name, code)
+		}
+		codes = append(codes, found)
+	}
+	return codes, nil
+}
@@ -0,0 +1,33 @@
+package mkdoc
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestConfig_errorTypeScopes(t *testing.T) {
+	dir := filepath.Join("/", "work", "project")
+	conf := &Config{
+		Errors: []*ErrorType{
+			{File: "src/errcode/code.go", Type: "ErrCode"},
+			{File: "/abs/errcode/code.go", Type: "Code"},
+		},
+		dir: dir,
+	}
+	scopes, err := conf.errorTypeScopes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []errorTypeScope{
+		{lang: "go", ts: TypeScope{FileName: filepath.Join(dir, "src/errcode/code.go"), TypeName: "ErrCode"}},
+		{lang: "go", ts: TypeScope{FileName: "/abs/errcode/code.go", TypeName: "Code"}},
+	}
+	if len(scopes) != len(want) {
+		t.Fatalf("got %d scopes, want %d", len(scopes), len(want))
+	}
+	for i, s := range scopes {
+		if *s != want[i] {
+			t.Errorf("scope %d got %+v, want %+v", i, *s, want[i])
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"github.com/thewinds/mkdoc"
+	"github.com/thewinds/mkdoc/generator/objfield"
 	"github.com/thewinds/mkdoc/generator/objmock"
 	"sort"
 	"strings"
@@ -31,9 +32,25 @@ func (g *Generator) Gen(ctx *mkdoc.DocGenContext) (output *mkdoc.GeneratedOutput
 		}
 		output.Files = append(output.Files, md)
 	}
+	if len(ctx.Errors) > 0 {
+		output.Files = append(output.Files, g.makeErrors(ctx))
+	}
 	return
 }
 
+// makeErrors make the page of error code catalog,the codes are grouped by type
+func (g *Generator) makeErrors(ctx *mkdoc.DocGenContext) *mkdoc.GeneratedFile {
+	buf := bytes.NewBuffer(nil)
+	buf.WriteString("# Error Codes\n")
+	for i, e := range ctx.Errors {
+		if i == 0 || e.Type != ctx.Errors[i-1].Type {
+			buf.WriteString(fmt.Sprintf("\n## %s\n|错误码|名称|说明|\n|---|---|---|\n", e.Type))
+		}
+		buf.WriteString(fmt.Sprintf("|`%s`|%s|%s|\n", e.Code, e.Name, objfield.TableCell(e.Desc)))
+	}
+	return &mkdoc.GeneratedFile{Name: "errors.md", Data: buf.Bytes()}
+}
+
 func (g *Generator) makeSidebar(ctx *mkdoc.DocGenContext) *mkdoc.GeneratedFile {
 	buf := bytes.NewBuffer(nil)
 	writeLine := func(s string) {
@@ -47,6 +64,10 @@ func (g *Generator) makeSidebar(ctx *mkdoc.DocGenContext) *mkdoc.GeneratedFile {
 	for _, tag := range g.tags {
 		writeLine(fmt.Sprintf("  - [%s](%s.md)", tag, tag))
 	}
+	if len(ctx.Errors) > 0 {
+		writeLine("")
+		writeLine("- [Error Codes](errors.md)")
+	}
 	return &mkdoc.GeneratedFile{Name: "_sidebar.md", Data: buf.Bytes()}
 }
 
@@ -126,65 +147,75 @@ func (g *Generator) makeTagMD(tag string) (*mkdoc.GeneratedFile, error) {
 		writef("[path] %s\n", api.Path)
 		writef("```\n")
 
-		if len(api.Header) > 0 {
-			writef("- Header\n")
-			writef("|名称|说明|\n|---|---|\n")
-			keys := make([]string, 0, len(api.Header))
-			for k := range api.Header {
-				keys = append(keys, k)
-			}
-			sort.Strings(keys)
-			for _, key := range keys {
-				writef("|`%s`|%s|\n", key, api.Header[key])
-			}
-			writef("\n")
-		}
+		objfield.WriteParams(&markdownBuilder, "- Path\n", api.PathParams)
+		objfield.WriteParams(&markdownBuilder, "- Header\n", api.Header)
+		objfield.WriteParams(&markdownBuilder, "- Query\n", api.Query)
 
-		if len(api.Query) > 0 {
-			writef("- Query\n")
-			writef("|名称|说明|\n|---|---|\n")
-			keys := make([]string, 0, len(api.Query))
-			for k := range api.Query {
-				keys = append(keys, k)
-			}
-			sort.Strings(keys)
-			for _, key := range keys {
-				writef("|`%s`|%s|\n", key, api.Query[key])
-			}
-			writef("\n")
+		if err := objfield.WriteFields(&markdownBuilder, "- Request Fields\n", api.InArgument, g.refObj, api.InLanguage); err != nil {
+			return nil, err
 		}
 
 		writef("- Request Example\n")
 		writef("```")
-		switch api.Mime.In {
+		mimeIn := api.Mime.In
+		if api.Type == "graphql" {
+			// graphql operations are sent as query document
+			mimeIn = "graphql"
+		}
+		switch mimeIn {
+		case "graphql":
+			writef("graphql\n")
+			o, err := objmock.NewGraphQLMocker().Mock(api, g.refObj)
+			if err != nil {
+				return nil, err
+			}
+			writef("%s", o)
 		default:
 			writef("json\n")
-			o, err := objmock.NewJSONMocker().SetLanguage(api.Language).MockPrettyComment(api.InArgument, g.refObj)
+			o, err := objmock.NewJSONMocker().SetLanguage(api.InLanguage).WithExample(api.InExample).MockPrettyComment(api.InArgument, g.refObj)
 			if err != nil {
 				return nil, err
 			}
 			if len(strings.TrimSpace(o)) == 0 {
 				o = "{}"
 			}
-			writef(o)
+			writef("%s", o)
 		}
 		writef("\n```\n\n")
 
-		writef("- Response Example\n")
-		writef("```")
-		switch api.Mime.Out {
-		default:
-			writef("json\n")
-			o, err := objmock.NewJSONMocker().SetLanguage(api.Language).MockPrettyComment(api.OutArgument, g.refObj)
-			if err != nil {
+		responses := api.AllResponses()
+		for i, r := range responses {
+			var suffix string
+			if len(responses) > 1 || r.Title() != "200" {
+				suffix = " (" + r.Title() + ")"
+			}
+			if err := objfield.WriteFields(&markdownBuilder, "- Response Fields"+suffix+"\n", r.Argument, g.refObj, r.Language); err != nil {
 				return nil, err
 			}
-			if len(strings.TrimSpace(o)) == 0 {
-				o = "{}"
+
+			writef("- Response Example%s\n", suffix)
+			writef("```")
+			switch r.Mime {
+			default:
+				writef("json\n")
+				o, err := objmock.NewJSONMocker().SetLanguage(r.Language).WithExample(r.Example).MockPrettyComment(r.Argument, g.refObj)
+				if err != nil {
+					return nil, err
+				}
+				if len(strings.TrimSpace(o)) == 0 {
+					o = "{}"
+				}
+				writef("%s", o)
 			}
-			writef(o)
+			writef("\n```\n")
+			if i < len(responses)-1 {
+				writef("\n")
+			}
+		}
+		if len(api.Errors) > 0 {
+			writef("\n")
+			objfield.WriteErrors(&markdownBuilder, "- Errors\n", api.Errors)
 		}
-		writef("\n```\n")
 	}
 	return &mkdoc.GeneratedFile{
 		Name: tag + ".md",
@@ -0,0 +1,406 @@
+package graphql
+
+import (
+	"fmt"
+	"github.com/thewinds/mkdoc"
+	"github.com/thewinds/mkdoc/generator/objmock"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+type Generator struct{}
+
+func init() {
+	mkdoc.RegisterGenerator(&Generator{})
+}
+
+var rootTypes = []struct {
+	op   string
+	name string
+}{
+	{"query", "Query"},
+	{"mutation", "Mutation"},
+	{"subscription", "Subscription"},
+}
+
+// Gen render the GraphQL apis to SDL,apis of other types are ignored
+func (g *Generator) Gen(ctx *mkdoc.DocGenContext) (output *mkdoc.GeneratedOutput, err error) {
+	b := &sdlBuilder{
+		refs:  ctx.RefObj,
+		names: make(map[declKey]string),
+		ids:   make(map[string]declKey),
+	}
+	// types scanned from SDL keep the original name,reserve them first
+	for _, id := range sortedIds(ctx.RefObj) {
+		obj := ctx.RefObj[id]
+		if ext := mkdoc.FindExtension[*mkdoc.ExtensionGraphQL](obj.Extensions); ext != nil && ext.Kind != "" {
+			if _, ok := b.ids[obj.ID]; !ok {
+				b.ids[obj.ID] = declKey{id: id, input: ext.Kind == "input"}
+			}
+		}
+	}
+	rootFields := make(map[string][]string)
+	for _, api := range ctx.APIs {
+		if api.Type != "graphql" {
+			continue
+		}
+		op := strings.ToLower(api.Method)
+		if op != "mutation" && op != "subscription" {
+			op = "query"
+		}
+		rootFields[op] = append(rootFields[op], b.operation(api))
+	}
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	sb := strings.Builder{}
+	for _, root := range rootTypes {
+		if len(rootFields[root.op]) == 0 {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("type %s {\n", root.name))
+		for _, field := range rootFields[root.op] {
+			sb.WriteString(field)
+		}
+		sb.WriteString("}\n\n")
+	}
+	// declarations may queue more declarations
+	for i := 0; i < len(b.queue); i++ {
+		sb.WriteString(b.declare(b.queue[i]))
+		sb.WriteString("\n")
+	}
+	if b.err != nil {
+		return nil, b.err
+	}
+	if b.jsonScalar {
+		sb.WriteString("scalar JSON\n")
+	}
+
+	outName := "schema"
+	if ctx.Tag != "" {
+		outName = ctx.Tag
+	}
+	output = &mkdoc.GeneratedOutput{}
+	output.Files = append(output.Files, &mkdoc.GeneratedFile{
+		Name: outName + ".graphql",
+		Data: []byte(strings.TrimSpace(sb.String()) + "\n"),
+	})
+	return output, nil
+}
+
+func (g *Generator) Name() string {
+	return "graphql"
+}
+
+// sortedIds returns the ids of refs in a stable order,
+// the objects of graphql come first if an id exists in several languages
+func sortedIds(refs map[mkdoc.LangObjectId]*mkdoc.Object) []mkdoc.LangObjectId {
+	ids := make([]mkdoc.LangObjectId, 0, len(refs))
+	for id := range refs {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, b := ids[i], ids[j]
+		if (a.Lang == "graphql") != (b.Lang == "graphql") {
+			return a.Lang == "graphql"
+		}
+		if a.Lang != b.Lang {
+			return a.Lang < b.Lang
+		}
+		return a.Id < b.Id
+	})
+	return ids
+}
+
+// declKey is a named type to declare,
+// an object is declared twice if it's used as input and output
+type declKey struct {
+	id    mkdoc.LangObjectId
+	input bool
+}
+
+type sdlBuilder struct {
+	refs       map[mkdoc.LangObjectId]*mkdoc.Object
+	lang       string
+	queue      []declKey
+	names      map[declKey]string
+	ids        map[string]declKey
+	jsonScalar bool
+	err        error
+}
+
+// operation returns the root field of api
+func (b *sdlBuilder) operation(api *mkdoc.API) string {
+	sb := strings.Builder{}
+	sb.WriteString(description("  ", api.Desc))
+	name := objmock.GraphQLName(api.Name)
+	sb.WriteString("  " + name)
+	if api.InArgument != nil && len(api.InArgument.Fields) > 0 {
+		b.lang = api.InLanguage
+		var args []string
+		multiLine := false
+		for _, field := range api.InArgument.Fields {
+			argName := fieldName(field)
+			if argName == "" {
+				continue
+			}
+			arg := fmt.Sprintf("%s: %s", argName, b.fieldType(field, true, upperFirst(argName)))
+			if ext := mkdoc.FindExtension[*mkdoc.ExtensionGraphQL](field.Extensions); ext != nil && ext.Default != "" {
+				arg += " = " + ext.Default
+			}
+			if field.Desc != "" {
+				multiLine = true
+				arg = description("    ", field.Desc) + "    " + arg
+			}
+			args = append(args, arg)
+		}
+		if multiLine {
+			sb.WriteString("(\n" + strings.Join(args, "\n") + "\n  )")
+		} else if len(args) > 0 {
+			sb.WriteString("(" + strings.Join(args, ", ") + ")")
+		}
+	}
+	outType := "Boolean"
+	if out := api.OutArgument; out != nil {
+		b.lang = api.OutLanguage
+		if ext := mkdoc.FindExtension[*mkdoc.ExtensionGraphQL](out.Extensions); ext != nil && ext.Type != "" {
+			b.collect(out.Type, false)
+			outType = ext.Type
+		} else {
+			outType = b.objectType(out, false, upperFirst(name)+"Result")
+		}
+	}
+	sb.WriteString(": " + outType + "\n")
+	return sb.String()
+}
+
+// fieldType returns the SDL type of field,hint is the name of anonymous object
+func (b *sdlBuilder) fieldType(field *mkdoc.ObjectField, input bool, hint string) string {
+	if ext := mkdoc.FindExtension[*mkdoc.ExtensionGraphQL](field.Extensions); ext != nil && ext.Type != "" {
+		b.collect(field.Type, input)
+		return ext.Type
+	}
+	if field.Type.Ref == "" {
+		return b.list(b.scalar(field.Type.Name), field.Type.IsRepeated)
+	}
+	obj := b.ref(field.Type.Ref)
+	if obj == nil {
+		return "JSON"
+	}
+	return b.list(b.objectType(obj, input, hint), field.Type.IsRepeated)
+}
+
+// objectType returns the SDL type of object and queue the named types
+func (b *sdlBuilder) objectType(obj *mkdoc.Object, input bool, hint string) string {
+	if ext := mkdoc.FindExtension[*mkdoc.ExtensionGraphQL](obj.Extensions); ext != nil && ext.Kind != "" {
+		return b.named(obj, input, hint)
+	}
+	// GraphQL has no map type
+	if obj.Type.MapKey != "" {
+		b.jsonScalar = true
+		return b.list("JSON", obj.Type.IsRepeated)
+	}
+	if obj.Type.Name != "object" {
+		return b.list(b.scalar(obj.Type.Name), obj.Type.IsRepeated)
+	}
+	if obj.Type.Ref != "" {
+		ref := b.ref(obj.Type.Ref)
+		if ref == nil {
+			return "JSON"
+		}
+		return b.list(b.objectType(ref, input, hint), obj.Type.IsRepeated)
+	}
+	return b.named(obj, input, hint)
+}
+
+// collect queue the named types referenced by type
+func (b *sdlBuilder) collect(typ *mkdoc.ObjectType, input bool) {
+	if typ.Ref == "" {
+		return
+	}
+	if obj := b.ref(typ.Ref); obj != nil {
+		b.objectType(obj, input, "")
+	}
+}
+
+// named returns the name of a named type,the type is queued if not declared
+func (b *sdlBuilder) named(obj *mkdoc.Object, input bool, hint string) string {
+	ext := mkdoc.FindExtension[*mkdoc.ExtensionGraphQL](obj.Extensions)
+	if ext != nil && ext.Kind != "" {
+		// types scanned from SDL keep the original name
+		input = ext.Kind == "input"
+	}
+	key := declKey{id: mkdoc.LangObjectId{Lang: b.lang, Id: obj.ID}, input: input}
+	if name, ok := b.names[key]; ok {
+		return name
+	}
+	var name string
+	switch {
+	case ext != nil && ext.Kind != "":
+		name = obj.ID
+	case strings.HasPrefix(obj.ID, "@") && hint != "":
+		name = hint
+	default:
+		name = typeName(obj.ID)
+	}
+	if input && (ext == nil || ext.Kind == "") && !strings.HasSuffix(name, "Input") {
+		name += "Input"
+	}
+	if exist, ok := b.ids[name]; ok && exist != key {
+		base := typeName(strings.Replace(obj.ID, ".", "_", -1))
+		if input {
+			base += "Input"
+		}
+		name = base
+		for i := 2; b.ids[name] != (declKey{}); i++ {
+			name = fmt.Sprintf("%s%d", base, i)
+		}
+	}
+	b.names[key] = name
+	b.ids[name] = key
+	b.queue = append(b.queue, key)
+	return name
+}
+
+// declare returns the type declaration
+func (b *sdlBuilder) declare(key declKey) string {
+	obj := b.refs[key.id]
+	name := b.names[key]
+	b.lang = key.id.Lang
+	ext := mkdoc.FindExtension[*mkdoc.ExtensionGraphQL](obj.Extensions)
+	kind := "type"
+	if key.input {
+		kind = "input"
+	}
+	if ext != nil && ext.Kind != "" {
+		kind = ext.Kind
+	}
+	switch kind {
+	case "enum":
+		return fmt.Sprintf("enum %s {\n  %s\n}\n", name, strings.Join(ext.Values, "\n  "))
+	case "union":
+		var members []string
+		for _, member := range ext.Values {
+			if obj := b.ref(member); obj != nil {
+				members = append(members, b.named(obj, false, ""))
+			}
+		}
+		return fmt.Sprintf("union %s = %s\n", name, strings.Join(members, " | "))
+	case "scalar":
+		return fmt.Sprintf("scalar %s\n", name)
+	}
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("%s %s {\n", kind, name))
+	written := false
+	for _, field := range obj.Fields {
+		fn := fieldName(field)
+		if fn == "" {
+			continue
+		}
+		sb.WriteString(description("  ", field.Desc))
+		sb.WriteString(fmt.Sprintf("  %s: %s", fn, b.fieldType(field, key.input, name+upperFirst(fn))))
+		if ext := mkdoc.FindExtension[*mkdoc.ExtensionGraphQL](field.Extensions); ext != nil && ext.Default != "" && key.input {
+			sb.WriteString(" = " + ext.Default)
+		}
+		sb.WriteString("\n")
+		written = true
+	}
+	if !written {
+		// object types must define one or more fields
+		b.jsonScalar = true
+		sb.WriteString("  _: JSON\n")
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+func (b *sdlBuilder) ref(id string) *mkdoc.Object {
+	obj := b.refs[mkdoc.LangObjectId{Lang: b.lang, Id: id}]
+	if obj == nil && b.err == nil {
+		b.err = fmt.Errorf("graphql: type %s not exist", id)
+	}
+	return obj
+}
+
+func (b *sdlBuilder) scalar(typ string) string {
+	switch typ {
+	case "string":
+		return "String"
+	case "bool":
+		return "Boolean"
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "byte":
+		return "Int"
+	case "float", "float32", "float64":
+		return "Float"
+	}
+	b.jsonScalar = true
+	return "JSON"
+}
+
+func (b *sdlBuilder) list(typ string, repeated bool) string {
+	if repeated {
+		return "[" + typ + "]"
+	}
+	return typ
+}
+
+var (
+	rePkgName = regexp.MustCompile(`\w+\.`)
+	reNonWord = regexp.MustCompile(`\W+`)
+)
+
+// typeName returns a valid type name of object id
+//
+//	github.com/x/model.User => User
+//	github.com/x/api.Resp[github.com/x/model.User] => RespUser
+func typeName(id string) string {
+	if strings.Contains(id, "[") {
+		name := rePkgName.ReplaceAllString(mkdoc.ShortObjectID(id), "")
+		var sb strings.Builder
+		for _, part := range reNonWord.Split(name, -1) {
+			sb.WriteString(upperFirst(part))
+		}
+		return upperFirst(objmock.GraphQLName(sb.String()))
+	}
+	if i := strings.LastIndexAny(id, "/."); i != -1 {
+		id = id[i+1:]
+	}
+	return upperFirst(objmock.GraphQLName(id))
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+func description(indent, desc string) string {
+	desc = strings.TrimSpace(desc)
+	if desc == "" {
+		return ""
+	}
+	if strings.Contains(desc, "\n") || strings.Contains(desc, `"`) {
+		desc = strings.Replace(desc, `"""`, `\"""`, -1)
+		desc = strings.Replace(desc, "\n", "\n"+indent, -1)
+		return fmt.Sprintf("%s\"\"\"\n%s%s\n%s\"\"\"\n", indent, indent, desc, indent)
+	}
+	return fmt.Sprintf("%s\"%s\"\n", indent, desc)
+}
+
+func fieldName(field *mkdoc.ObjectField) string {
+	goTagExt := mkdoc.FindExtension[*mkdoc.ExtensionGoTag](field.Extensions)
+	if goTagExt == nil {
+		return field.Name
+	}
+	name := goTagExt.Tag.GetFirstValue("json", ",")
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
@@ -0,0 +1,65 @@
+package graphql
+
+import (
+	"github.com/thewinds/mkdoc"
+	"github.com/thewinds/mkdoc/schema"
+	"testing"
+)
+
+func TestGenerator_Gen(t *testing.T) {
+	refs := map[mkdoc.LangObjectId]*mkdoc.Object{
+		{Lang: "graphql", Id: "User"}: {
+			ID:         "User",
+			Type:       &mkdoc.ObjectType{Name: "object"},
+			Fields:     []*mkdoc.ObjectField{{Name: "id", Type: &mkdoc.ObjectType{Name: "string"}}},
+			Extensions: []mkdoc.Extension{&mkdoc.ExtensionGraphQL{Kind: "type"}},
+			Loaded:     true,
+		},
+		// the same id in other language
+		{Lang: "go", Id: "User"}: {
+			ID:         "User",
+			Type:       &mkdoc.ObjectType{Name: "object"},
+			Fields:     []*mkdoc.ObjectField{{Name: "name", Type: &mkdoc.ObjectType{Name: "string"}}},
+			Extensions: []mkdoc.Extension{&mkdoc.ExtensionGraphQL{Kind: "type"}},
+			Loaded:     true,
+		},
+		{Lang: "go", Id: "@obj_in_#1"}: {
+			ID:     "@obj_in_#1",
+			Type:   &mkdoc.ObjectType{Name: "object"},
+			Fields: []*mkdoc.ObjectField{{Name: "id", Desc: "用户 ID", Type: &mkdoc.ObjectType{Name: "int"}}},
+			Loaded: true,
+		},
+	}
+	ctx := &mkdoc.DocGenContext{
+		APIs: []*mkdoc.API{{
+			API:         schema.API{Name: "user", Desc: "获取用户", Method: "query", Type: "graphql", Language: "go"},
+			InArgument:  refs[mkdoc.LangObjectId{Lang: "go", Id: "@obj_in_#1"}],
+			InLanguage:  "go",
+			OutArgument: refs[mkdoc.LangObjectId{Lang: "graphql", Id: "User"}],
+			OutLanguage: "graphql",
+		}},
+		RefObj: refs,
+	}
+	want := `type Query {
+  "获取用户"
+  user(
+    "用户 ID"
+    id: Int
+  ): User
+}
+
+type User {
+  id: String
+}
+`
+	// the names of types must not depend on the iteration of refs
+	for i := 0; i < 20; i++ {
+		output, err := new(Generator).Gen(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := string(output.Files[0].Data); got != want {
+			t.Fatalf("got:\n%s\nwant:\n%s", got, want)
+		}
+	}
+}
@@ -6,8 +6,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/thewinds/mkdoc"
+	"github.com/thewinds/mkdoc/generator/objfield"
 	"github.com/thewinds/mkdoc/generator/objmock"
-	"math/rand"
 	"strings"
 	"time"
 )
@@ -22,28 +22,22 @@ func (g *Generator) Gen(ctx *mkdoc.DocGenContext) (output *mkdoc.GeneratedOutput
 	data := &insomniaExport{
 		Type:   "export",
 		Format: 4,
-		Date:   time.Now().Format(time.RFC3339),
 		Source: "mkdoc",
 	}
 
 	wrk := &workspace{
-		ID:          genResID("wrk"),
-		Created:     time.Now().Unix(),
+		ID:          genResID("wrk", ctx.Tag, ctx.Config.Name),
 		Description: ctx.Config.Name,
-		Modified:    time.Now().Unix(),
 		Name:        fmt.Sprintf("%s-%s", ctx.Tag, ctx.Config.Name),
 		Type:        "workspace",
 	}
 
 	envs := &environment{
-		ID:          genResID("env"),
-		Created:     time.Now().Unix(),
-		Data:        map[string]string{"base_url": ctx.Config.APIBaseURL},
-		MetaSortKey: time.Now().Unix(),
-		Modified:    time.Now().Unix(),
-		Name:        "env",
-		ParentID:    wrk.ID,
-		Type:        "environment",
+		ID:       genResID("env", wrk.ID),
+		Data:     map[string]string{"base_url": ctx.Config.APIBaseURL},
+		Name:     "env",
+		ParentID: wrk.ID,
+		Type:     "environment",
 	}
 
 	data.Resources = append(data.Resources, wrk, envs)
@@ -55,7 +49,7 @@ func (g *Generator) Gen(ctx *mkdoc.DocGenContext) (output *mkdoc.GeneratedOutput
 		switch e.Scope {
 		case "header":
 			commonHeaders = append(commonHeaders, &requestHeader{
-				ID:    genResID("pair"),
+				ID:    genResID("pair", wrk.ID, e.Scope, e.Name),
 				Name:  e.Name,
 				Value: e.Default,
 			})
@@ -64,25 +58,31 @@ func (g *Generator) Gen(ctx *mkdoc.DocGenContext) (output *mkdoc.GeneratedOutput
 		case "form_param":
 			commonFormParam = append(commonFormParam, &reqParam{
 				Description: e.Desc,
-				ID:          genResID("pair"),
+				ID:          genResID("pair", wrk.ID, e.Scope, e.Name),
 				Name:        e.Name,
 				Value:       e.Default,
 			})
 		}
 	}
 
-	for _, api := range ctx.APIs {
-		now := time.Now().Unix()
+	for i, api := range ctx.APIs {
+		// the path parameters are filled by the environment variables
+		path, _ := mkdoc.ReplacePathParams(api.Path, func(name string) string {
+			return fmt.Sprintf("{{%s}}", name)
+		})
+		for _, p := range api.PathParams {
+			if _, ok := envs.Data[p.Name]; !ok {
+				envs.Data[p.Name] = ""
+			}
+		}
 		req := &request{
-			ID:                              genResID("req"),
+			ID:                              genResID("req", wrk.ID, api.Method, api.Path, api.Name),
 			Authentication:                  reqAuth{},
 			Body:                            nil,
-			Created:                         now,
 			Description:                     api.Desc,
 			Headers:                         make([]*requestHeader, 0, len(commonHeaders)),
-			MetaSortKey:                     -now,
+			MetaSortKey:                     int64(i),
 			Method:                          strings.ToUpper(api.Method),
-			Modified:                        now,
 			Name:                            api.Name,
 			Parameters:                      []interface{}{},
 			ParentID:                        wrk.ID,
@@ -92,16 +92,33 @@ func (g *Generator) Gen(ctx *mkdoc.DocGenContext) (output *mkdoc.GeneratedOutput
 			SettingRebuildPath:              true,
 			SettingSendCookies:              true,
 			SettingStoreCookies:             true,
-			URL:                             fmt.Sprintf("{{base_url}}%s", api.Path),
+			URL:                             fmt.Sprintf("{{base_url}}%s", path),
 			Type:                            "request",
 		}
 
+		// insomnia can't save the example response,so it is appended to the description
+		if r := api.SuccessResponse(); r != nil && (r.Argument != nil || r.Example != "") {
+			example, err := objmock.NewJSONMocker().SetLanguage(r.Language).WithExample(r.Example).MockPretty(r.Argument, ctx.RefObj)
+			if err != nil {
+				return nil, err
+			}
+			req.Description = strings.TrimSpace(fmt.Sprintf("%s\n\nResponse Example (%s)\n```json\n%s\n```", api.Desc, r.Title(), example))
+		}
+
 		req.Headers = append(req.Headers, commonHeaders...)
-		for k := range api.Header {
+		for _, p := range api.Header {
 			req.Headers = append(req.Headers, &requestHeader{
-				ID:    genResID("pair"),
-				Name:  k,
-				Value: "",
+				ID:    genResID("pair", req.ID, "header", p.Name),
+				Name:  p.Name,
+				Value: p.Default,
+			})
+		}
+		for _, p := range api.Query {
+			req.Parameters = append(req.Parameters, &reqParam{
+				Description: p.Desc,
+				ID:          genResID("pair", req.ID, "query", p.Name),
+				Name:        p.Name,
+				Value:       p.Default,
 			})
 		}
 
@@ -110,12 +127,12 @@ func (g *Generator) Gen(ctx *mkdoc.DocGenContext) (output *mkdoc.GeneratedOutput
 			body := &textReqBody{
 				MimeType: "application/json",
 			}
-			body.Text, err = objmock.NewJSONMocker().MockPretty(api.InArgument, ctx.RefObj)
+			body.Text, err = objmock.NewJSONMocker().SetLanguage(api.InLanguage).WithExample(api.InExample).MockPretty(api.InArgument, ctx.RefObj)
 			if err != nil {
 				return nil, err
 			}
 			req.Headers = append(req.Headers, &requestHeader{
-				ID:    genResID("pair"),
+				ID:    genResID("pair", req.ID, "header", "Content-Type"),
 				Name:  "Content-Type",
 				Value: "application/json",
 			})
@@ -128,13 +145,13 @@ func (g *Generator) Gen(ctx *mkdoc.DocGenContext) (output *mkdoc.GeneratedOutput
 
 			if api.InArgument != nil {
 				for _, field := range api.InArgument.Fields {
-					paramName := formFieldName(field)
+					paramName := objfield.FormFieldName(field)
 					if paramName == "" {
 						continue
 					}
 					param := &reqParam{
 						Description: field.Desc,
-						ID:          genResID("pair"),
+						ID:          genResID("pair", req.ID, "form_param", paramName),
 						Name:        paramName,
 						Value:       "",
 					}
@@ -148,7 +165,7 @@ func (g *Generator) Gen(ctx *mkdoc.DocGenContext) (output *mkdoc.GeneratedOutput
 			}
 
 			req.Headers = append(req.Headers, &requestHeader{
-				ID:    genResID("pair"),
+				ID:    genResID("pair", req.ID, "header", "Content-Type"),
 				Name:  "Content-Type",
 				Value: "multipart/form-data",
 			})
@@ -180,30 +197,12 @@ func (g *Generator) Name() string {
 	return "insomnia"
 }
 
-func formFieldName(field *mkdoc.ObjectField) string {
-	goTagExt := getGoTag(field.Extensions)
-	if goTagExt == nil {
-		return field.Name
-	}
-	tags := []string{"form", "json", "xml"}
-	for _, tag := range tags {
-		tv := goTagExt.Tag.GetValue(tag)
-
-		if tv == "-" {
-			return ""
-		}
-		if tv == "" {
-			continue
-		}
-		return goTagExt.Tag.GetFirstValue(tag, ",")
-	}
-	return field.Name
-}
-
+// the timestamps are omitted so that the exports are stable between runs,
+// insomnia fills them on import
 type insomniaExport struct {
 	Type      string        `json:"_type"`
 	Format    int           `json:"__export_format"`
-	Date      string        `json:"__export_date"`
+	Date      string        `json:"__export_date,omitempty"`
 	Source    string        `json:"__export_source"`
 	Resources []interface{} `json:"resources"`
 }
@@ -212,13 +211,13 @@ type request struct {
 	ID                              string           `json:"_id"`
 	Authentication                  reqAuth          `json:"authentication"`
 	Body                            interface{}      `json:"body"`
-	Created                         int64            `json:"created"`
+	Created                         int64            `json:"created,omitempty"`
 	Description                     string           `json:"description"`
 	Headers                         []*requestHeader `json:"headers"`
 	IsPrivate                       bool             `json:"isPrivate"`
 	MetaSortKey                     int64            `json:"metaSortKey"`
 	Method                          string           `json:"method"`
-	Modified                        int64            `json:"modified"`
+	Modified                        int64            `json:"modified,omitempty"`
 	Name                            string           `json:"name"`
 	Parameters                      []interface{}    `json:"parameters"`
 	ParentID                        interface{}      `json:"parentId"`
@@ -258,9 +257,9 @@ type requestHeader struct {
 
 type workspace struct {
 	ID          string      `json:"_id"`
-	Created     int64       `json:"created"`
+	Created     int64       `json:"created,omitempty"`
 	Description string      `json:"description"`
-	Modified    int64       `json:"modified"`
+	Modified    int64       `json:"modified,omitempty"`
 	Name        string      `json:"name"`
 	ParentID    interface{} `json:"parentId"`
 	Type        string      `json:"_type"`
@@ -269,12 +268,12 @@ type workspace struct {
 type environment struct {
 	ID                string            `json:"_id"`
 	Color             interface{}       `json:"color"`
-	Created           int64             `json:"created"`
+	Created           int64             `json:"created,omitempty"`
 	Data              map[string]string `json:"data"`
 	DataPropertyOrder interface{}       `json:"dataPropertyOrder"`
 	IsPrivate         bool              `json:"isPrivate"`
 	MetaSortKey       int64             `json:"metaSortKey"`
-	Modified          int64             `json:"modified"`
+	Modified          int64             `json:"modified,omitempty"`
 	Name              string            `json:"name"`
 	ParentID          interface{}       `json:"parentId"`
 	Type              string            `json:"_type"`
@@ -283,28 +282,16 @@ type environment struct {
 type cookieJar struct {
 	ID       string        `json:"_id"`
 	Cookies  []interface{} `json:"cookies"`
-	Created  int64         `json:"created"`
-	Modified int64         `json:"modified"`
+	Created  int64         `json:"created,omitempty"`
+	Modified int64         `json:"modified,omitempty"`
 	Name     string        `json:"name"`
 	ParentID interface{}   `json:"parentId"`
 	Type     string        `json:"_type"`
 }
 
-func init() {
-	rand.Seed(time.Now().Unix())
-}
-
-func genResID(typ string) string {
-	k := fmt.Sprintf("%s%d%d", typ, time.Now().UnixNano(), rand.Int31())
-	sum := md5.Sum([]byte(k))
+// genResID returns the id of resource derived from the type and keys,
+// the ids are stable between runs so that the exports can be diffed
+func genResID(typ string, keys ...string) string {
+	sum := md5.Sum([]byte(typ + "\x00" + strings.Join(keys, "\x00")))
 	return fmt.Sprintf("typ_%s", hex.EncodeToString(sum[:]))
 }
-
-func getGoTag(exts []mkdoc.Extension) *mkdoc.ExtensionGoTag {
-	for _, ext := range exts {
-		if e, ok := ext.(*mkdoc.ExtensionGoTag); ok {
-			return e
-		}
-	}
-	return nil
-}
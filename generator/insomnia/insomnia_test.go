@@ -0,0 +1,31 @@
+package insomnia
+
+import (
+	"bytes"
+	"github.com/thewinds/mkdoc"
+	"github.com/thewinds/mkdoc/schema"
+	"testing"
+)
+
+func TestGenerator_Gen_Stable(t *testing.T) {
+	ctx := &mkdoc.DocGenContext{
+		APIs: []*mkdoc.API{
+			{API: schema.API{Name: "get user", Method: "get", Path: "/user/:id", Language: "go"}, Mime: &mkdoc.MimeType{In: "json", Out: "json"}},
+			{API: schema.API{Name: "list users", Method: "get", Path: "/users", Language: "go"}, Mime: &mkdoc.MimeType{In: "json", Out: "json"}},
+		},
+		Config: mkdoc.Config{Name: "example", APIBaseURL: "http://localhost"},
+		RefObj: map[mkdoc.LangObjectId]*mkdoc.Object{},
+		Tag:    "user",
+	}
+	first, err := new(Generator).Gen(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := new(Generator).Gen(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(first.Files[0].Data, second.Files[0].Data) {
+		t.Errorf("exports differ between runs:\n%s\n%s", first.Files[0].Data, second.Files[0].Data)
+	}
+}
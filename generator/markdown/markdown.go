@@ -3,8 +3,8 @@ package markdown
 import (
 	"fmt"
 	"github.com/thewinds/mkdoc"
+	"github.com/thewinds/mkdoc/generator/objfield"
 	"github.com/thewinds/mkdoc/generator/objmock"
-	"sort"
 	"strings"
 	"time"
 )
@@ -60,59 +60,79 @@ func (g *Generator) Gen(ctx *mkdoc.DocGenContext) (output *mkdoc.GeneratedOutput
 		writef("[path] %s\n", api.Path)
 		writef("```\n")
 
-		if len(api.Header) > 0 {
-			writef("- Header\n")
-			writef("|名称|说明|\n|---|---|\n")
-			keys := make([]string, 0, len(api.Header))
-			for k := range api.Header {
-				keys = append(keys, k)
-			}
-			sort.Strings(keys)
-			for _, key := range keys {
-				writef("|`%s`|%s|\n", key, api.Header[key])
-			}
-			writef("\n")
-		}
+		objfield.WriteParams(&markdownBuilder, "- Path\n", api.PathParams)
+		objfield.WriteParams(&markdownBuilder, "- Header\n", api.Header)
+		objfield.WriteParams(&markdownBuilder, "- Query\n", api.Query)
 
-		if len(api.Query) > 0 {
-			writef("- Query\n")
-			writef("|名称|说明|\n|---|---|\n")
-			keys := make([]string, 0, len(api.Query))
-			for k := range api.Query {
-				keys = append(keys, k)
-			}
-			sort.Strings(keys)
-			for _, key := range keys {
-				writef("|`%s`|%s|\n", key, api.Query[key])
-			}
-			writef("\n")
+		if err := objfield.WriteFields(&markdownBuilder, "- Request Fields\n", api.InArgument, ctx.RefObj, api.InLanguage); err != nil {
+			return nil, err
 		}
 
 		writef("- Request Example\n")
 		writef("```")
-		switch api.Mime.In {
+		mimeIn := api.Mime.In
+		if api.Type == "graphql" {
+			// graphql operations are sent as query document
+			mimeIn = "graphql"
+		}
+		switch mimeIn {
+		case "graphql":
+			writef("graphql\n")
+			o, err := objmock.NewGraphQLMocker().Mock(api, ctx.RefObj)
+			if err != nil {
+				return nil, err
+			}
+			writef("%s", o)
 		default:
 			writef("json\n")
-			o, err := objmock.NewJSONMocker().SetLanguage(api.Language).MockPrettyComment(api.InArgument, ctx.RefObj)
+			o, err := objmock.NewJSONMocker().SetLanguage(api.InLanguage).WithExample(api.InExample).MockPrettyComment(api.InArgument, ctx.RefObj)
 			if err != nil {
 				return nil, err
 			}
-			writef(o)
+			writef("%s", o)
 		}
 		writef("\n```\n")
 
-		writef("- Response Example\n")
-		writef("```")
-		switch api.Mime.Out {
-		default:
-			writef("json\n")
-			o, err := objmock.NewJSONMocker().SetLanguage(api.Language).MockPrettyComment(api.OutArgument, ctx.RefObj)
-			if err != nil {
+		responses := api.AllResponses()
+		for i, r := range responses {
+			var suffix string
+			if len(responses) > 1 || r.Title() != "200" {
+				suffix = " (" + r.Title() + ")"
+			}
+			if err := objfield.WriteFields(&markdownBuilder, "- Response Fields"+suffix+"\n", r.Argument, ctx.RefObj, r.Language); err != nil {
 				return nil, err
 			}
-			writef(o)
+
+			writef("- Response Example%s\n", suffix)
+			writef("```")
+			switch r.Mime {
+			default:
+				writef("json\n")
+				o, err := objmock.NewJSONMocker().SetLanguage(r.Language).WithExample(r.Example).MockPrettyComment(r.Argument, ctx.RefObj)
+				if err != nil {
+					return nil, err
+				}
+				writef("%s", o)
+			}
+			writef("\n```\n")
+			if i < len(responses)-1 {
+				writef("\n")
+			}
+		}
+		if len(api.Errors) > 0 {
+			writef("\n")
+			objfield.WriteErrors(&markdownBuilder, "- Errors\n", api.Errors)
+		}
+	}
+
+	if len(ctx.Errors) > 0 {
+		writef("\n# Error Codes\n")
+		for i, e := range ctx.Errors {
+			if i == 0 || e.Type != ctx.Errors[i-1].Type {
+				writef("\n### %s\n|错误码|名称|说明|\n|---|---|---|\n", e.Type)
+			}
+			writef("|`%s`|%s|%s|\n", e.Code, e.Name, objfield.TableCell(e.Desc))
 		}
-		writef("\n```\n")
 	}
 
 	var outName string
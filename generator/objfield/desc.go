@@ -0,0 +1,82 @@
+package objfield
+
+import (
+	"fmt"
+	"github.com/thewinds/mkdoc"
+	"strconv"
+	"strings"
+)
+
+// EnumOf returns the enum extension of the type,
+// the element type is used if that is an array
+func EnumOf(typ *mkdoc.ObjectType, refs map[mkdoc.LangObjectId]*mkdoc.Object, lang string) *mkdoc.ExtensionEnum {
+	// limit the depth of reference
+	for i := 0; i < 16 && typ.Ref != ""; i++ {
+		obj := refs[mkdoc.LangObjectId{Lang: lang, Id: typ.Ref}]
+		if obj == nil || obj.Type.MapKey != "" {
+			return nil
+		}
+		if enum := mkdoc.FindExtension[*mkdoc.ExtensionEnum](obj.Extensions); enum != nil {
+			return enum
+		}
+		typ = obj.Type
+	}
+	return nil
+}
+
+// EnumDesc returns the description of enum values,eg. enum: 1(normal), 2(vip)
+func EnumDesc(enum *mkdoc.ExtensionEnum) string {
+	if enum == nil || len(enum.Values) == 0 {
+		return ""
+	}
+	values := make([]string, 0, len(enum.Values))
+	for _, v := range enum.Values {
+		if v.Desc != "" {
+			values = append(values, fmt.Sprintf("%s(%s)", v.Value, strings.Replace(v.Desc, "\n", " ", -1)))
+			continue
+		}
+		values = append(values, v.Value)
+	}
+	return "enum: " + strings.Join(values, ", ")
+}
+
+// RulesDesc returns the description of rules,eg. length: 1~64; format: email
+func RulesDesc(rules *mkdoc.ExtensionRules) string {
+	if rules == nil {
+		return ""
+	}
+	var items []string
+	if rules.MinLength != nil || rules.MaxLength != nil {
+		var min, max string
+		if rules.MinLength != nil {
+			min = strconv.FormatInt(*rules.MinLength, 10)
+		}
+		if rules.MaxLength != nil {
+			max = strconv.FormatInt(*rules.MaxLength, 10)
+		}
+		items = append(items, fmt.Sprintf("length: %s~%s", min, max))
+	}
+	if rules.Minimum != nil || rules.Maximum != nil {
+		left, min, max, right := "[", "-∞", "+∞", "]"
+		if rules.Minimum != nil {
+			min = strconv.FormatFloat(*rules.Minimum, 'g', -1, 64)
+		}
+		if rules.Minimum == nil || rules.ExclusiveMinimum {
+			left = "("
+		}
+		if rules.Maximum != nil {
+			max = strconv.FormatFloat(*rules.Maximum, 'g', -1, 64)
+		}
+		if rules.Maximum == nil || rules.ExclusiveMaximum {
+			right = ")"
+		}
+		items = append(items, fmt.Sprintf("range: %s%s, %s%s", left, min, max, right))
+	}
+	if len(rules.Enum) > 0 {
+		items = append(items, "oneof: "+strings.Join(rules.Enum, ", "))
+	}
+	if rules.Format != "" {
+		items = append(items, "format: "+rules.Format)
+	}
+	return strings.Join(items, "; ")
+}
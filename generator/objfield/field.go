@@ -0,0 +1,218 @@
+package objfield
+
+import (
+	"fmt"
+	"github.com/thewinds/mkdoc"
+	"strings"
+)
+
+// Field is a row of the field table,the allowed values of enum and the validation rules are appended to Desc
+//
+// Name is the json path of field,eg. profile.age,friends[].id,projects.*.name
+// Type is the display type of field,eg. int64,[]model.User,map<string, int>
+// Required is declared by the binding or validate tag of field
+type Field struct {
+	Name     string
+	Type     string
+	Desc     string
+	Required bool
+}
+
+// Lister list the fields of object,the fields of referenced objects are
+// listed under the path of field,circle references are listed once
+type Lister struct {
+	refs    map[mkdoc.LangObjectId]*mkdoc.Object
+	curLang string
+	refPath []string
+	fields  []*Field
+	err     error
+}
+
+func NewLister() *Lister {
+	return &Lister{}
+}
+
+func (l *Lister) SetLanguage(lang string) *Lister {
+	l.curLang = lang
+	return l
+}
+
+func (l *Lister) List(object *mkdoc.Object, refs map[mkdoc.LangObjectId]*mkdoc.Object) ([]*Field, error) {
+	if object == nil {
+		return nil, nil
+	}
+	l.refs = refs
+	l.fields = nil
+	l.refPath = nil
+	l.list(object, "")
+	if l.err != nil {
+		return nil, l.err
+	}
+	return l.fields, nil
+}
+
+func (l *Lister) list(obj *mkdoc.Object, prefix string) {
+	// unwrap arrays and maps
+	for l.err == nil && obj.Type.Name == "object" && obj.Type.Ref != "" {
+		if obj.Type.IsRepeated {
+			prefix += "[]"
+		}
+		if obj.Type.MapKey != "" {
+			prefix = join(prefix, "*")
+		}
+		obj = l.ref(obj.Type.Ref)
+		if obj == nil {
+			return
+		}
+	}
+	if obj.Type.Name != "object" || !l.pushRefPath(obj.ID) {
+		return
+	}
+	defer l.popRefPath()
+	for _, field := range obj.Fields {
+		name := fieldName(field)
+		if name == "" {
+			continue
+		}
+		path := join(prefix, name)
+		desc := field.Desc
+		if enum := EnumDesc(EnumOf(field.Type, l.refs, l.curLang)); enum != "" {
+			desc = strings.TrimSpace(desc + "\n" + enum)
+		}
+		if rules := RulesDesc(mkdoc.FindExtension[*mkdoc.ExtensionRules](field.Extensions)); rules != "" {
+			desc = strings.TrimSpace(desc + "\n" + rules)
+		}
+		typ := l.typeName(field.Type)
+		c := mkdoc.FindExtension[*mkdoc.ExtensionConstraints](field.Extensions)
+		if c != nil && c.String && !field.Type.IsRepeated && isScalar(typ) {
+			typ = "string(" + typ + ")"
+		}
+		l.fields = append(l.fields, &Field{Name: path, Type: typ, Desc: desc, Required: c != nil && c.Required})
+		if field.Type.Ref == "" {
+			continue
+		}
+		ref := l.ref(field.Type.Ref)
+		if ref == nil {
+			return
+		}
+		if field.Type.IsRepeated {
+			path += "[]"
+		}
+		l.list(ref, path)
+	}
+}
+
+func (l *Lister) typeName(typ *mkdoc.ObjectType) string {
+	var name string
+	if typ.Ref == "" {
+		name = typ.Name
+	} else if obj := l.ref(typ.Ref); obj != nil {
+		name = l.objectTypeName(obj)
+	}
+	if typ.IsRepeated {
+		name = "[]" + name
+	}
+	return name
+}
+
+// objectTypeName returns the display type of object,
+// named objects are displayed by the last element of package path,eg. model.User
+func (l *Lister) objectTypeName(obj *mkdoc.Object) string {
+	var name string
+	switch {
+	case obj.Type.MapKey != "":
+		value := l.typeName(&mkdoc.ObjectType{Name: obj.Type.Name, Ref: obj.Type.Ref})
+		name = fmt.Sprintf("map<%s, %s>", obj.Type.MapKey, value)
+	case mkdoc.FindExtension[*mkdoc.ExtensionOpaque](obj.Extensions) != nil:
+		name = "opaque"
+	case obj.Type.Name != "object":
+		name = obj.Type.Name
+		if format := mkdoc.FindExtension[*mkdoc.ExtensionFormat](obj.Extensions); format != nil && format.Format != "" {
+			name += "(" + format.Format + ")"
+		}
+	case obj.Type.Ref != "":
+		name = l.typeName(&mkdoc.ObjectType{Name: "object", Ref: obj.Type.Ref})
+	case strings.HasPrefix(obj.ID, "@"):
+		name = "object"
+	default:
+		name = mkdoc.ShortObjectID(obj.ID)
+	}
+	if obj.Type.IsRepeated {
+		name = "[]" + name
+	}
+	return name
+}
+
+func (l *Lister) ref(id string) *mkdoc.Object {
+	obj := l.refs[mkdoc.LangObjectId{Lang: l.curLang, Id: id}]
+	if obj == nil && l.err == nil {
+		l.err = fmt.Errorf("field: type %s not exist", id)
+	}
+	return obj
+}
+
+func (l *Lister) pushRefPath(id string) bool {
+	for _, v := range l.refPath {
+		if v == id {
+			return false
+		}
+	}
+	l.refPath = append(l.refPath, id)
+	return true
+}
+
+func (l *Lister) popRefPath() {
+	if len(l.refPath) == 0 {
+		return
+	}
+	l.refPath = l.refPath[:len(l.refPath)-1]
+}
+
+func join(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+func fieldName(field *mkdoc.ObjectField) string {
+	if e := mkdoc.FindExtension[*mkdoc.ExtensionGoTag](field.Extensions); e != nil {
+		name := e.Tag.GetFirstValue("json", ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// FormFieldName returns the form parameter name of field,
+// the name is looked up from go tag form,json and xml in order
+// empty string is returned if the field is ignored by `-`
+func FormFieldName(field *mkdoc.ObjectField) string {
+	if e := mkdoc.FindExtension[*mkdoc.ExtensionGoTag](field.Extensions); e != nil {
+		for _, tag := range []string{"form", "json", "xml"} {
+			tv := e.Tag.GetValue(tag)
+			if tv == "-" {
+				return ""
+			}
+			if tv == "" {
+				continue
+			}
+			return e.Tag.GetFirstValue(tag, ",")
+		}
+	}
+	return field.Name
+}
+
+// isScalar report whether the type is encoded as string by `json:",string"`
+func isScalar(typ string) bool {
+	switch typ {
+	case "bool", "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64",
+		"float", "float32", "float64", "byte":
+		return true
+	}
+	return false
+}
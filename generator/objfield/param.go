@@ -0,0 +1,18 @@
+package objfield
+
+import (
+	"github.com/thewinds/mkdoc/schema"
+	"strings"
+)
+
+// ParamDesc returns the description of parameter with the default value and the allowed values
+func ParamDesc(p *schema.Param) string {
+	desc := p.Desc
+	if p.Default != "" {
+		desc += "\ndefault: " + p.Default
+	}
+	if len(p.Enum) > 0 {
+		desc += "\nenum: " + strings.Join(p.Enum, ", ")
+	}
+	return strings.TrimSpace(desc)
+}
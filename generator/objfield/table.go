@@ -0,0 +1,67 @@
+package objfield
+
+import (
+	"fmt"
+	"github.com/thewinds/mkdoc"
+	"github.com/thewinds/mkdoc/schema"
+	"io"
+	"strings"
+)
+
+// WriteFields write the markdown table of the fields of object,
+// nothing is written if the object has no fields
+func WriteFields(w io.Writer, title string, object *mkdoc.Object, refs map[mkdoc.LangObjectId]*mkdoc.Object, lang string) error {
+	fields, err := NewLister().SetLanguage(lang).List(object, refs)
+	if err != nil {
+		return err
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	fmt.Fprintf(w, "%s", title)
+	fmt.Fprintf(w, "|名称|类型|必填|说明|\n|---|---|---|---|\n")
+	for _, field := range fields {
+		fmt.Fprintf(w, "|`%s`|`%s`|%s|%s|\n", field.Name, field.Type, requiredText(field.Required), TableCell(field.Desc))
+	}
+	fmt.Fprintf(w, "\n")
+	return nil
+}
+
+// WriteParams write the markdown table of the path,header or query parameters
+func WriteParams(w io.Writer, title string, params schema.Params) {
+	if len(params) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "%s", title)
+	fmt.Fprintf(w, "|名称|类型|必填|说明|\n|---|---|---|---|\n")
+	for _, p := range params {
+		fmt.Fprintf(w, "|`%s`|`%s`|%s|%s|\n", p.Name, p.Type, requiredText(p.Required), TableCell(ParamDesc(p)))
+	}
+	fmt.Fprintf(w, "\n")
+}
+
+// WriteErrors write the markdown table of the error codes
+func WriteErrors(w io.Writer, title string, codes []*mkdoc.ErrorCode) {
+	if len(codes) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "%s", title)
+	fmt.Fprintf(w, "|错误码|名称|说明|\n|---|---|---|\n")
+	for _, e := range codes {
+		fmt.Fprintf(w, "|`%s`|%s|%s|\n", e.Code, e.Name, TableCell(e.Desc))
+	}
+	fmt.Fprintf(w, "\n")
+}
+
+// TableCell escape the text to keep it in one table cell
+func TableCell(s string) string {
+	s = strings.Replace(strings.TrimSpace(s), "|", "\\|", -1)
+	return strings.Replace(s, "\n", "<br>", -1)
+}
+
+func requiredText(required bool) string {
+	if required {
+		return "是"
+	}
+	return "否"
+}
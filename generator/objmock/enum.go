@@ -0,0 +1,19 @@
+package objmock
+
+import (
+	"encoding/json"
+	"github.com/thewinds/mkdoc"
+)
+
+// enumExample returns the first enum value as a json literal of type
+func enumExample(enum *mkdoc.ExtensionEnum, typ string) (string, bool) {
+	if enum == nil || len(enum.Values) == 0 {
+		return "", false
+	}
+	v := enum.Values[0].Value
+	if typ == "string" {
+		b, _ := json.Marshal(v)
+		return string(b), true
+	}
+	return v, true
+}
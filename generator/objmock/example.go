@@ -0,0 +1,35 @@
+package objmock
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/thewinds/mkdoc"
+	"strings"
+)
+
+// ExampleValue returns the declared example as a compact json literal,
+// the example of string is quoted if it is not a json string,
+// the example of other types is ignored if it is not a json literal
+func ExampleValue(example *mkdoc.ExtensionExample, typ string) (string, bool) {
+	if example == nil {
+		return "", false
+	}
+	v := strings.TrimSpace(example.Value)
+	dst := bytes.NewBuffer([]byte{})
+	valid := json.Compact(dst, []byte(v)) == nil
+	if valid {
+		v = dst.String()
+	}
+	switch {
+	case typ == "string":
+		if valid && strings.HasPrefix(v, "\"") {
+			return v, true
+		}
+	case valid:
+		return v, true
+	case typ != "interface{}":
+		return "", false
+	}
+	b, _ := json.Marshal(example.Value)
+	return string(b), true
+}
@@ -0,0 +1,40 @@
+package objmock
+
+import (
+	"encoding/json"
+	"github.com/thewinds/mkdoc"
+)
+
+// formatExamples are the realistic examples of string formats
+var formatExamples = map[string]string{
+	"date-time": "2006-01-02T15:04:05+08:00",
+	"date":      "2006-01-02",
+	"time":      "15:04:05",
+	"duration":  "1h30m",
+	"uuid":      "3fa85f64-5717-4562-b3fc-2c963f66afa6",
+	"decimal":   "10.20",
+	"email":     "user@example.com",
+	"uri":       "https://example.com",
+	"url":       "https://example.com",
+	"hostname":  "example.com",
+	"ipv4":      "192.168.1.1",
+	"ipv6":      "2001:db8::1",
+	"byte":      "c3Ry",
+	"binary":    "str",
+	"password":  "******",
+	"phone":     "+8613800138000",
+}
+
+// formatExample returns the example of format as a json literal,
+// only the formats of string are supported
+func formatExample(format *mkdoc.ExtensionFormat, typ string) (string, bool) {
+	if format == nil || typ != "string" {
+		return "", false
+	}
+	v, ok := formatExamples[format.Format]
+	if !ok {
+		return "", false
+	}
+	b, _ := json.Marshal(v)
+	return string(b), true
+}
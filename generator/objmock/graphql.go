@@ -0,0 +1,274 @@
+package objmock
+
+import (
+	"fmt"
+	"github.com/thewinds/mkdoc"
+	"strings"
+)
+
+// GraphQLMocker mock the query document of a GraphQL operation,
+// the arguments are mocked from the in object and
+// the selection set contains all the fields of the out object
+type GraphQLMocker struct {
+	refs    map[mkdoc.LangObjectId]*mkdoc.Object
+	err     error
+	data    strings.Builder
+	refPath []string
+	curLang string
+}
+
+func NewGraphQLMocker() *GraphQLMocker {
+	return &GraphQLMocker{}
+}
+
+// Mock returns the query document of api,
+// the refs of arguments are looked up by the language of arguments,eg.
+//
+//	query {
+//	  user(id: "str") {
+//	    id
+//	    name
+//	  }
+//	}
+func (g *GraphQLMocker) Mock(api *mkdoc.API, refs map[mkdoc.LangObjectId]*mkdoc.Object) (string, error) {
+	g.refs = refs
+	op := strings.ToLower(api.Method)
+	if op != "mutation" && op != "subscription" {
+		op = "query"
+	}
+	g.write("%s {\n  %s", op, GraphQLName(api.Name))
+	if api.InArgument != nil && len(api.InArgument.Fields) > 0 {
+		g.curLang = api.InLanguage
+		var args []string
+		for _, field := range api.InArgument.Fields {
+			name := fieldName(field)
+			if name == "" {
+				continue
+			}
+			args = append(args, fmt.Sprintf("%s: %s", name, g.value(field.Type)))
+		}
+		g.write("(%s)", strings.Join(args, ", "))
+	}
+	if api.OutArgument != nil {
+		g.curLang = api.OutLanguage
+		g.data.WriteString(g.selection(api.OutArgument, 1))
+	}
+	g.write("\n}")
+	if g.err != nil {
+		return "", g.err
+	}
+	return g.data.String(), nil
+}
+
+// value returns a mock value literal of type
+func (g *GraphQLMocker) value(typ *mkdoc.ObjectType) string {
+	if typ.Ref == "" {
+		return g.builtinValue(typ.Name, typ.IsRepeated)
+	}
+	obj := g.ref(typ.Ref)
+	if obj == nil || !g.pushRefPath(obj.ID) {
+		return "null"
+	}
+	defer g.popRefPath()
+	var v string
+	switch {
+	case obj.Type.MapKey != "":
+		// maps are JSON scalars,the key is always a string
+		v = "{key: " + g.value(&mkdoc.ObjectType{Name: obj.Type.Name, Ref: obj.Type.Ref}) + "}"
+	case obj.Type.Name != "object":
+		v = g.builtinValue(obj.Type.Name, false)
+		if ext := mkdoc.FindExtension[*mkdoc.ExtensionGraphQL](obj.Extensions); ext != nil && ext.Kind == "enum" && len(ext.Values) > 0 {
+			v = ext.Values[0]
+		} else if example, ok := enumExample(mkdoc.FindExtension[*mkdoc.ExtensionEnum](obj.Extensions), obj.Type.Name); ok {
+			v = example
+		} else if example, ok := formatExample(mkdoc.FindExtension[*mkdoc.ExtensionFormat](obj.Extensions), obj.Type.Name); ok {
+			v = example
+		}
+	case obj.Type.Ref != "":
+		v = g.value(&mkdoc.ObjectType{Name: "object", Ref: obj.Type.Ref})
+	default:
+		var fields []string
+		for _, field := range obj.Fields {
+			name := fieldName(field)
+			if name == "" {
+				continue
+			}
+			fields = append(fields, fmt.Sprintf("%s: %s", name, g.value(field.Type)))
+		}
+		v = "{" + strings.Join(fields, ", ") + "}"
+	}
+	if obj.Type.IsRepeated {
+		v = "[" + v + "]"
+	}
+	if typ.IsRepeated {
+		v = "[" + v + "]"
+	}
+	return v
+}
+
+func (g *GraphQLMocker) builtinValue(typ string, repeated bool) string {
+	v := "null"
+	switch typ {
+	case "string":
+		v = `"str"`
+	case "bool":
+		v = "true"
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "byte":
+		v = "10"
+	case "float", "float32", "float64":
+		v = "10.2"
+	}
+	if repeated {
+		v = "[" + v + "]"
+	}
+	return v
+}
+
+// selection returns the selection set of object,
+// empty if the object is a scalar or a circle reference
+func (g *GraphQLMocker) selection(obj *mkdoc.Object, dep int) string {
+	if isUnion(obj) {
+		return fmt.Sprintf(" {\n%s__typename\n%s}", strings.Repeat("  ", dep+1), strings.Repeat("  ", dep))
+	}
+	if obj.Type.Name != "object" || obj.Type.MapKey != "" {
+		return ""
+	}
+	if obj.Type.Ref != "" {
+		ref := g.ref(obj.Type.Ref)
+		if ref == nil || !g.pushRefPath(ref.ID) {
+			return ""
+		}
+		defer g.popRefPath()
+		return g.selection(ref, dep)
+	}
+	indent := strings.Repeat("  ", dep+1)
+	sb := strings.Builder{}
+	for _, field := range obj.Fields {
+		name := fieldName(field)
+		if name == "" {
+			continue
+		}
+		if field.Type.Ref == "" {
+			sb.WriteString(indent + name + "\n")
+			continue
+		}
+		ref := g.ref(field.Type.Ref)
+		if ref == nil {
+			continue
+		}
+		if g.isLeaf(ref) {
+			sb.WriteString(indent + name + "\n")
+			continue
+		}
+		if !g.pushRefPath(ref.ID) {
+			continue
+		}
+		// skip the field if there is nothing to select
+		if sub := g.selection(ref, dep+1); sub != "" {
+			sb.WriteString(indent + name + sub + "\n")
+		}
+		g.popRefPath()
+	}
+	if sb.Len() == 0 {
+		sb.WriteString(indent + "__typename\n")
+	}
+	return " {\n" + sb.String() + strings.Repeat("  ", dep) + "}"
+}
+
+// isLeaf report whether the object is a scalar,a map or a list of them
+func (g *GraphQLMocker) isLeaf(obj *mkdoc.Object) bool {
+	for obj != nil {
+		if isUnion(obj) {
+			return false
+		}
+		if obj.Type.Name != "object" || obj.Type.MapKey != "" {
+			return true
+		}
+		if obj.Type.Ref == "" {
+			return false
+		}
+		obj = g.ref(obj.Type.Ref)
+	}
+	return true
+}
+
+func (g *GraphQLMocker) ref(id string) *mkdoc.Object {
+	obj := g.refs[mkdoc.LangObjectId{Lang: g.curLang, Id: id}]
+	if obj == nil && g.err == nil {
+		g.err = fmt.Errorf("mock: type %s not exist", id)
+	}
+	return obj
+}
+
+func (g *GraphQLMocker) write(format string, i ...interface{}) {
+	fmt.Fprintf(&g.data, format, i...)
+}
+
+func (g *GraphQLMocker) pushRefPath(id string) bool {
+	for _, v := range g.refPath {
+		if v == id {
+			return false
+		}
+	}
+	g.refPath = append(g.refPath, id)
+	return true
+}
+
+func (g *GraphQLMocker) popRefPath() {
+	if len(g.refPath) > 0 {
+		g.refPath = g.refPath[:len(g.refPath)-1]
+	}
+}
+
+// GraphQLName convert name to a valid GraphQL name,
+// invalid characters are removed and the first letter is lower case
+//
+//	get user => getUser
+func GraphQLName(name string) string {
+	var sb strings.Builder
+	upper := false
+	for _, c := range name {
+		valid := c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+		if !valid {
+			upper = sb.Len() > 0
+			continue
+		}
+		if upper && c >= 'a' && c <= 'z' {
+			c -= 'a' - 'A'
+		}
+		upper = false
+		sb.WriteRune(c)
+	}
+	s := sb.String()
+	if s == "" {
+		return "_"
+	}
+	if s[0] >= '0' && s[0] <= '9' {
+		s = "_" + s
+	}
+	if s[0] >= 'A' && s[0] <= 'Z' {
+		s = strings.ToLower(s[:1]) + s[1:]
+	}
+	return s
+}
+
+// fieldName returns the json name of field,empty if the field is ignored
+func fieldName(field *mkdoc.ObjectField) string {
+	goTagExt := mkdoc.FindExtension[*mkdoc.ExtensionGoTag](field.Extensions)
+	if goTagExt == nil {
+		return field.Name
+	}
+	name := goTagExt.Tag.GetFirstValue("json", ",")
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+func isUnion(obj *mkdoc.Object) bool {
+	ext := mkdoc.FindExtension[*mkdoc.ExtensionGraphQL](obj.Extensions)
+	return ext != nil && ext.Kind == "union"
+}
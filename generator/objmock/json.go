@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/thewinds/mkdoc"
+	"github.com/thewinds/mkdoc/generator/objfield"
 	"strings"
 )
 
@@ -18,6 +19,7 @@ type JSONMocker struct {
 	fieldNo   int
 	refPath   []string
 	curLang   string
+	example   string
 }
 
 func NewJSONMocker() *JSONMocker {
@@ -29,7 +31,17 @@ func (j *JSONMocker) SetLanguage(lang string) *JSONMocker {
 	return j
 }
 
+// WithExample set the json example declared by `@example`,
+// the example takes precedence over the mock values of object
+func (j *JSONMocker) WithExample(example string) *JSONMocker {
+	j.example = example
+	return j
+}
+
 func (j *JSONMocker) Mock(object *mkdoc.Object, refs map[mkdoc.LangObjectId]*mkdoc.Object) (string, error) {
+	if j.example != "" {
+		return j.example, nil
+	}
 	if object == nil {
 		return "\n", nil
 	}
@@ -46,7 +58,7 @@ func (j *JSONMocker) Mock(object *mkdoc.Object, refs map[mkdoc.LangObjectId]*mkd
 }
 
 func (j *JSONMocker) MockPretty(object *mkdoc.Object, refs map[mkdoc.LangObjectId]*mkdoc.Object) (string, error) {
-	if object == nil {
+	if object == nil && j.example == "" {
 		return "\n", nil
 	}
 	r, err := j.Mock(object, refs)
@@ -62,7 +74,7 @@ func (j *JSONMocker) MockPretty(object *mkdoc.Object, refs map[mkdoc.LangObjectI
 }
 
 func (j *JSONMocker) MockPrettyComment(object *mkdoc.Object, refs map[mkdoc.LangObjectId]*mkdoc.Object) (string, error) {
-	if object == nil {
+	if object == nil && j.example == "" {
 		return "\n", nil
 	}
 	r, err := j.MockPretty(object, refs)
@@ -81,9 +93,28 @@ func (j *JSONMocker) mock(obj *mkdoc.Object) {
 		j.write("[")
 		defer func() { j.write("]") }()
 	}
+	if objType.MapKey != "" {
+		j.write("{%s:", j.mapKeyValue(objType.MapKey))
+		// the key line is counted as a field without comment
+		j.fieldNo++
+		defer func() { j.write("}") }()
+	}
 
 	// builtin type
 	if objType.Name != "object" {
+		// the json shape of opaque object is unknown
+		if mkdoc.FindExtension[*mkdoc.ExtensionOpaque](obj.Extensions) != nil {
+			j.write("null")
+			return
+		}
+		if v, ok := enumExample(mkdoc.FindExtension[*mkdoc.ExtensionEnum](obj.Extensions), objType.Name); ok {
+			j.write(v)
+			return
+		}
+		if v, ok := formatExample(mkdoc.FindExtension[*mkdoc.ExtensionFormat](obj.Extensions), objType.Name); ok {
+			j.write(v)
+			return
+		}
 		j.writeValue(objType.Name)
 		return
 	}
@@ -96,7 +127,7 @@ func (j *JSONMocker) mock(obj *mkdoc.Object) {
 	defer func() { j.write("}") }()
 	var firstField bool
 	for _, field := range obj.Fields {
-		goTagExt := getGoTag(field.Extensions)
+		goTagExt := mkdoc.FindExtension[*mkdoc.ExtensionGoTag](field.Extensions)
 		var jsonTag string
 		if goTagExt != nil {
 			jsonTag = goTagExt.Tag.GetFirstValue("json", ",")
@@ -117,12 +148,77 @@ func (j *JSONMocker) mock(obj *mkdoc.Object) {
 		fieldTyp := field.Type
 		j.markFieldComment(obj, field)
 		j.fieldNo++
-		if fieldTyp.Ref != "" {
+		// the numbers and booleans of `json:",string"` are encoded as string
+		quoted := false
+		if c := mkdoc.FindExtension[*mkdoc.ExtensionConstraints](field.Extensions); c != nil && c.String {
+			switch j.scalarType(fieldTyp) {
+			case "", "string", "interface{}":
+			default:
+				quoted = true
+			}
+		}
+		// the declared example takes precedence over the value satisfies the validation rules,
+		// the example of quoted field may be already a json string
+		example, hasExample := ExampleValue(mkdoc.FindExtension[*mkdoc.ExtensionExample](field.Extensions), j.scalarType(fieldTyp))
+		if hasExample && strings.HasPrefix(example, "\"") {
+			quoted = false
+		}
+		if quoted {
+			j.write("\"")
+		}
+		if hasExample {
+			j.write("%s", example)
+			j.fieldNo += exampleKeyLines(example)
+		} else if v, ok := rulesExample(mkdoc.FindExtension[*mkdoc.ExtensionRules](field.Extensions), j.scalarType(fieldTyp)); ok {
+			j.write(v)
+		} else if fieldTyp.Ref != "" {
 			j.mockRef(fieldTyp.Ref)
 		} else {
 			j.writeValue(fieldTyp.Name)
 		}
+		if quoted {
+			j.write("\"")
+		}
+	}
+}
+
+// scalarType returns the builtin type of the field type,
+// "" if that is an object,array or map
+func (j *JSONMocker) scalarType(typ *mkdoc.ObjectType) string {
+	// limit the depth of reference
+	for i := 0; i < 16; i++ {
+		if typ.IsRepeated || typ.MapKey != "" {
+			return ""
+		}
+		if typ.Ref == "" && typ.Name == "object" {
+			return ""
+		}
+		if typ.Ref == "" {
+			return typ.Name
+		}
+		obj := j.refs[mkdoc.LangObjectId{Lang: j.curLang, Id: typ.Ref}]
+		if obj == nil || mkdoc.FindExtension[*mkdoc.ExtensionOpaque](obj.Extensions) != nil {
+			return ""
+		}
+		typ = obj.Type
 	}
+	return ""
+}
+
+// exampleKeyLines returns the number of lines which look like a field in the indented example,
+// the first line is joined to the line of field
+func exampleKeyLines(example string) int {
+	dst := bytes.NewBuffer([]byte{})
+	if err := json.Indent(dst, []byte(example), "", "    "); err != nil {
+		return 0
+	}
+	var n int
+	for _, line := range strings.Split(dst.String(), "\n")[1:] {
+		if strings.Contains(line, "\":") {
+			n++
+		}
+	}
+	return n
 }
 
 func (j *JSONMocker) mockRef(refID string) {
@@ -199,12 +295,32 @@ func (j *JSONMocker) writeValue(typ string) {
 	j.write(val)
 }
 
+// mapKeyValue returns a sample key of map,json object keys are always string
+func (j *JSONMocker) mapKeyValue(typ string) string {
+	switch typ {
+	case "bool":
+		return "\"true\""
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64":
+		return "\"10\""
+	case "float", "float32", "float64":
+		return "\"10.2\""
+	}
+	return "\"key\""
+}
+
 func (j *JSONMocker) markFieldComment(obj *mkdoc.Object, field *mkdoc.ObjectField) {
 	var key string
 	key = fmt.Sprintf("%s.%s", obj.ID, field.Name)
 	if !j.commented[key] {
 		j.commented[key] = true
-		j.comment[j.fieldNo] = field.Desc
+		comment := field.Desc
+		if desc := objfield.EnumDesc(objfield.EnumOf(field.Type, j.refs, j.curLang)); desc != "" {
+			comment = strings.TrimSpace(comment + "\n" + desc)
+		}
+		if desc := objfield.RulesDesc(mkdoc.FindExtension[*mkdoc.ExtensionRules](field.Extensions)); desc != "" {
+			comment = strings.TrimSpace(comment + "\n" + desc)
+		}
+		j.comment[j.fieldNo] = comment
 	}
 }
 
@@ -232,12 +348,3 @@ func (j *JSONMocker) popRefPath() {
 	}
 	j.refPath = j.refPath[:len(j.refPath)-1]
 }
-
-func getGoTag(exts []mkdoc.Extension) *mkdoc.ExtensionGoTag {
-	for _, ext := range exts {
-		if e, ok := ext.(*mkdoc.ExtensionGoTag); ok {
-			return e
-		}
-	}
-	return nil
-}
@@ -3,18 +3,19 @@ package objmock
 import (
 	"fmt"
 	"github.com/thewinds/mkdoc"
+	"strings"
 	"testing"
 )
 
 func TestJSONMocker_Mock(t *testing.T) {
-	refs := make(map[string]*mkdoc.Object)
-	for _, obj := range mkdoc.BuiltinObjects() {
-		refs[obj.ID] = obj
+	objs := make(map[string]*mkdoc.Object)
+	for _, typ := range []string{"string", "int", "int64", "bool"} {
+		objs[typ] = &mkdoc.Object{ID: typ, Type: &mkdoc.ObjectType{Name: typ}, Loaded: true}
 	}
 
-	parseTag := func(raw string) *mkdoc.ObjectFieldTag {
+	goTag := func(raw string) []mkdoc.Extension {
 		tag, _ := mkdoc.NewObjectFieldTag(raw)
-		return tag
+		return []mkdoc.Extension{&mkdoc.ExtensionGoTag{Tag: tag}}
 	}
 
 	type want struct {
@@ -24,23 +25,23 @@ func TestJSONMocker_Mock(t *testing.T) {
 
 	var wants []want
 
-	refs["test_string"] = &mkdoc.Object{
+	objs["test_string"] = &mkdoc.Object{
 		ID:   "test_string",
 		Type: &mkdoc.ObjectType{Name: "string"},
 	}
 	wants = append(wants, want{"test_string", `"str"`})
 
-	refs["test_string_arr"] = &mkdoc.Object{
+	objs["test_string_arr"] = &mkdoc.Object{
 		ID:   "test_string",
 		Type: &mkdoc.ObjectType{Name: "string", IsRepeated: true},
 	}
 	wants = append(wants, want{"test_string_arr", `["str"]`})
 
-	refs["test_int_arr_dep0"] = &mkdoc.Object{
+	objs["test_int_arr_dep0"] = &mkdoc.Object{
 		ID:   "test_int_arr_dep0",
 		Type: &mkdoc.ObjectType{Name: "object", IsRepeated: true, Ref: "test_int_arr_dep1"},
 	}
-	refs["test_int_arr_dep1"] = &mkdoc.Object{
+	objs["test_int_arr_dep1"] = &mkdoc.Object{
 		ID:   "test_int_arr_dep1",
 		Type: &mkdoc.ObjectType{Name: "object", IsRepeated: true, Ref: "int"},
 	}
@@ -53,21 +54,21 @@ func TestJSONMocker_Mock(t *testing.T) {
 		},
 		Fields: []*mkdoc.ObjectField{
 			{
-				Name: "NickName",
-				Desc: "name",
-				Type: &mkdoc.ObjectType{Name: "string"},
-				Tag:  parseTag(`json:"nickname"`),
+				Name:       "NickName",
+				Desc:       "name",
+				Type:       &mkdoc.ObjectType{Name: "string"},
+				Extensions: goTag(`json:"nickname"`),
 			},
 			{
-				Name: "age",
-				Desc: "age",
-				Type: &mkdoc.ObjectType{Name: "int"},
-				Tag:  parseTag(`json:"age"`),
+				Name:       "age",
+				Desc:       "age",
+				Type:       &mkdoc.ObjectType{Name: "int"},
+				Extensions: goTag(`json:"age"`),
 			},
 		},
 		Loaded: false,
 	}
-	refs[profile.ID] = profile
+	objs[profile.ID] = profile
 
 	user := &mkdoc.Object{
 		ID: "abc.user",
@@ -76,41 +77,180 @@ func TestJSONMocker_Mock(t *testing.T) {
 		},
 		Fields: []*mkdoc.ObjectField{
 			{
-				Name: "id",
-				Desc: "id",
-				Type: &mkdoc.ObjectType{Name: "int64"},
-				Tag:  parseTag(`json:"uid"`),
+				Name:       "id",
+				Desc:       "id",
+				Type:       &mkdoc.ObjectType{Name: "int64"},
+				Extensions: goTag(`json:"uid"`),
 			},
 			{
-				Name: "onLine",
-				Desc: "user name",
-				Type: &mkdoc.ObjectType{Name: "bool"},
-				Tag:  parseTag(`json:"online"`),
+				Name:       "onLine",
+				Desc:       "user name",
+				Type:       &mkdoc.ObjectType{Name: "bool"},
+				Extensions: goTag(`json:"online"`),
 			},
 			{
-				Name: "profile",
-				Desc: "user profile",
-				Type: &mkdoc.ObjectType{Name: "object", Ref: "abc.profile"},
-				Tag:  parseTag(`json:"profile"`),
+				Name:       "profile",
+				Desc:       "user profile",
+				Type:       &mkdoc.ObjectType{Name: "object", Ref: "abc.profile"},
+				Extensions: goTag(`json:"profile"`),
 			},
 			{
-				Name: "friends",
-				Desc: "user friends",
-				Type: &mkdoc.ObjectType{Name: "object", Ref: "abc.user", IsRepeated: true},
-				Tag:  parseTag(`json:"friends"`),
+				Name:       "friends",
+				Desc:       "user friends",
+				Type:       &mkdoc.ObjectType{Name: "object", Ref: "abc.user", IsRepeated: true},
+				Extensions: goTag(`json:"friends"`),
 			},
 		},
 		Loaded: false,
 	}
-	refs[user.ID] = user
+	objs[user.ID] = user
 	wants = append(wants, want{user.ID, `{"uid":10,"online":true,"profile":{"nickname":"str","age":10},"friends":{"uid":10,"online":true,"profile":{"nickname":"str","age":10},"friends":null}}`})
 
+	objs["test_int_map"] = &mkdoc.Object{
+		ID:   "test_int_map",
+		Type: &mkdoc.ObjectType{Name: "bool", MapKey: "int64"},
+	}
+	objs["test_profile_map_arr"] = &mkdoc.Object{
+		ID:   "test_profile_map_arr",
+		Type: &mkdoc.ObjectType{Name: "object", Ref: "abc.profile", MapKey: "string", IsRepeated: true},
+	}
+	wants = append(wants, want{"test_int_map", `{"10":true}`})
+	wants = append(wants, want{"test_profile_map_arr", `[{"key":{"nickname":"str","age":10}}]`})
+
+	objs["test_enum"] = &mkdoc.Object{
+		ID:   "test_enum",
+		Type: &mkdoc.ObjectType{Name: "string"},
+		Extensions: []mkdoc.Extension{&mkdoc.ExtensionEnum{Values: []*mkdoc.EnumValue{
+			{Value: "on", Desc: "switch on"}, {Value: "off"},
+		}}},
+	}
+	objs["test_enum_field"] = &mkdoc.Object{
+		ID:   "test_enum_field",
+		Type: &mkdoc.ObjectType{Name: "object"},
+		Fields: []*mkdoc.ObjectField{
+			{Name: "state", Desc: "state", Type: &mkdoc.ObjectType{Name: "object", Ref: "test_enum"}},
+		},
+	}
+	wants = append(wants, want{"test_enum", `"on"`})
+	wants = append(wants, want{"test_enum_field", `{"state":"on"}`})
+
+	objs["test_time"] = &mkdoc.Object{
+		ID:         "test_time",
+		Type:       &mkdoc.ObjectType{Name: "string"},
+		Extensions: []mkdoc.Extension{&mkdoc.ExtensionFormat{Format: "date-time"}},
+	}
+	objs["test_time_arr"] = &mkdoc.Object{
+		ID:   "test_time_arr",
+		Type: &mkdoc.ObjectType{Name: "object", Ref: "test_time", IsRepeated: true},
+	}
+	objs["test_format_field"] = &mkdoc.Object{
+		ID:   "test_format_field",
+		Type: &mkdoc.ObjectType{Name: "object"},
+		Fields: []*mkdoc.ObjectField{
+			{Name: "created_at", Type: &mkdoc.ObjectType{Name: "object", Ref: "test_time"}},
+			{Name: "remind", Type: &mkdoc.ObjectType{Name: "object", Ref: "test_time_arr"}},
+		},
+	}
+	wants = append(wants, want{"test_format_field", `{"created_at":"2006-01-02T15:04:05+08:00","remind":["2006-01-02T15:04:05+08:00"]}`})
+
+	objs["test_opaque"] = &mkdoc.Object{
+		ID:         "test_opaque",
+		Type:       &mkdoc.ObjectType{Name: "interface{}"},
+		Extensions: []mkdoc.Extension{&mkdoc.ExtensionOpaque{Reason: "implements json.Marshaler"}},
+	}
+	objs["test_opaque_field"] = &mkdoc.Object{
+		ID:   "test_opaque_field",
+		Type: &mkdoc.ObjectType{Name: "object"},
+		Fields: []*mkdoc.ObjectField{
+			{Name: "point", Type: &mkdoc.ObjectType{Name: "object", Ref: "test_opaque"}},
+		},
+	}
+	wants = append(wants, want{"test_opaque_field", `{"point":null}`})
+
+	objs["test_string_opt"] = &mkdoc.Object{
+		ID:   "test_string_opt",
+		Type: &mkdoc.ObjectType{Name: "object"},
+		Fields: []*mkdoc.ObjectField{
+			{Name: "ID", Type: &mkdoc.ObjectType{Name: "int64"}, Extensions: append(goTag(`json:"id,string"`), &mkdoc.ExtensionConstraints{String: true})},
+			{Name: "State", Type: &mkdoc.ObjectType{Name: "object", Ref: "test_enum"}, Extensions: append(goTag(`json:"state,string"`), &mkdoc.ExtensionConstraints{String: true})},
+			{Name: "Profile", Type: &mkdoc.ObjectType{Name: "object", Ref: "abc.profile"}, Extensions: append(goTag(`json:"profile,string"`), &mkdoc.ExtensionConstraints{String: true})},
+		},
+	}
+	wants = append(wants, want{"test_string_opt", `{"id":"10","state":"on","profile":{"nickname":"str","age":10}}`})
+
+	minLen, maxAge := int64(5), 3.0
+	objs["test_rules"] = &mkdoc.Object{
+		ID:   "test_rules",
+		Type: &mkdoc.ObjectType{Name: "object"},
+		Fields: []*mkdoc.ObjectField{
+			{Name: "name", Type: &mkdoc.ObjectType{Name: "string"}, Extensions: []mkdoc.Extension{
+				&mkdoc.ExtensionRules{MinLength: &minLen},
+			}},
+			{Name: "age", Type: &mkdoc.ObjectType{Name: "int"}, Extensions: []mkdoc.Extension{
+				&mkdoc.ExtensionRules{Maximum: &maxAge, ExclusiveMaximum: true},
+			}},
+			{Name: "color", Type: &mkdoc.ObjectType{Name: "string"}, Extensions: []mkdoc.Extension{
+				&mkdoc.ExtensionRules{Enum: []string{"red", "green"}},
+			}},
+			{Name: "email", Type: &mkdoc.ObjectType{Name: "string"}, Extensions: []mkdoc.Extension{
+				&mkdoc.ExtensionRules{Format: "email"},
+			}},
+			{Name: "level", Type: &mkdoc.ObjectType{Name: "int"}, Extensions: []mkdoc.Extension{
+				&mkdoc.ExtensionRules{Enum: []string{"2", "3"}},
+			}},
+			{Name: "sizes", Type: &mkdoc.ObjectType{Name: "object", Ref: "test_string_arr"}, Extensions: []mkdoc.Extension{
+				&mkdoc.ExtensionRules{Enum: []string{"s", "m"}},
+			}},
+			{Name: "extra", Type: &mkdoc.ObjectType{Name: "interface{}"}, Extensions: []mkdoc.Extension{
+				&mkdoc.ExtensionRules{Enum: []string{"a", "b"}},
+			}},
+		},
+	}
+	wants = append(wants, want{"test_rules", `{"name":"sssss","age":2,"color":"red","email":"user@example.com","level":2,"sizes":["str"],"extra":{}}`})
+
+	objs["test_example"] = &mkdoc.Object{
+		ID:   "test_example",
+		Type: &mkdoc.ObjectType{Name: "object"},
+		Fields: []*mkdoc.ObjectField{
+			{Name: "email", Type: &mkdoc.ObjectType{Name: "string"}, Extensions: []mkdoc.Extension{
+				&mkdoc.ExtensionRules{Format: "email"}, &mkdoc.ExtensionExample{Value: "alice@example.com"},
+			}},
+			{Name: "code", Type: &mkdoc.ObjectType{Name: "string"}, Extensions: []mkdoc.Extension{
+				&mkdoc.ExtensionExample{Value: "10023"},
+			}},
+			{Name: "age", Type: &mkdoc.ObjectType{Name: "int"}, Extensions: []mkdoc.Extension{
+				&mkdoc.ExtensionExample{Value: "18"},
+			}},
+			{Name: "tags", Type: &mkdoc.ObjectType{Name: "string", IsRepeated: true}, Extensions: []mkdoc.Extension{
+				&mkdoc.ExtensionExample{Value: `["go","doc"]`},
+			}},
+			{Name: "score", Type: &mkdoc.ObjectType{Name: "int"}, Extensions: []mkdoc.Extension{
+				&mkdoc.ExtensionExample{Value: "high"},
+			}},
+			{Name: "ID", Type: &mkdoc.ObjectType{Name: "int64"}, Extensions: append(goTag(`json:"id,string"`),
+				&mkdoc.ExtensionConstraints{String: true}, &mkdoc.ExtensionExample{Value: `"5"`},
+			)},
+			{Name: "Count", Type: &mkdoc.ObjectType{Name: "int64"}, Extensions: append(goTag(`json:"count,string"`),
+				&mkdoc.ExtensionConstraints{String: true}, &mkdoc.ExtensionExample{Value: "6"},
+			)},
+			{Name: "extra", Type: &mkdoc.ObjectType{Name: "interface{}"}, Extensions: []mkdoc.Extension{
+				&mkdoc.ExtensionExample{Value: "{\"a\": 1,\n \"b\": [2]}"},
+			}},
+		},
+	}
+	wants = append(wants, want{"test_example", `{"email":"alice@example.com","code":"10023","age":18,"tags":["go","doc"],"score":10,"id":"5","count":"6","extra":{"a":1,"b":[2]}}`})
+
+	refs := make(map[mkdoc.LangObjectId]*mkdoc.Object)
+	for id, obj := range objs {
+		refs[mkdoc.LangObjectId{Id: id}] = obj
+	}
+
 	for _, w := range wants {
 		fmt.Println("\nTest", w.objectID)
 		mocker := new(JSONMocker)
-		o, err := mocker.Mock(refs[w.objectID], refs)
+		o, err := mocker.Mock(objs[w.objectID], refs)
 		if err != nil {
-			fmt.Println(err)
+			t.Error(err)
 			return
 		}
 		//fmt.Println(o)
@@ -118,7 +258,7 @@ func TestJSONMocker_Mock(t *testing.T) {
 			t.Errorf("\n got= %s\n want=%s\n", o, w.wantJSON)
 			return
 		}
-		o1, err := new(JSONMocker).MockPrettyComment(refs[w.objectID], refs)
+		o1, err := new(JSONMocker).MockPrettyComment(objs[w.objectID], refs)
 		if err != nil {
 			t.Error(err)
 			return
@@ -127,3 +267,35 @@ func TestJSONMocker_Mock(t *testing.T) {
 		fmt.Println("Pass")
 	}
 }
+
+func TestJSONMocker_MockPrettyComment(t *testing.T) {
+	obj := &mkdoc.Object{
+		ID:   "test_example_comment",
+		Type: &mkdoc.ObjectType{Name: "object"},
+		Fields: []*mkdoc.ObjectField{
+			{Name: "extra", Desc: "extra info", Type: &mkdoc.ObjectType{Name: "interface{}"}, Extensions: []mkdoc.Extension{
+				&mkdoc.ExtensionExample{Value: `{"a":{"b":1},"c":2}`},
+			}},
+			{Name: "name", Desc: "user name", Type: &mkdoc.ObjectType{Name: "string"}},
+		},
+	}
+	o, err := new(JSONMocker).MockPrettyComment(obj, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the keys of example are not numbered as fields
+	for _, line := range strings.Split(o, "\n") {
+		switch {
+		case strings.Contains(line, `"extra"`):
+			if !strings.HasSuffix(line, "// extra info") {
+				t.Errorf("got %q, want the comment of extra", line)
+			}
+		case strings.Contains(line, `"name"`):
+			if !strings.HasSuffix(line, "// user name") {
+				t.Errorf("got %q, want the comment of name", line)
+			}
+		case strings.Contains(line, "//"):
+			t.Errorf("got unexpected comment %q\n%s", line, o)
+		}
+	}
+}
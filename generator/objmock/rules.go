@@ -0,0 +1,87 @@
+package objmock
+
+import (
+	"encoding/json"
+	"github.com/thewinds/mkdoc"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// rulesExample returns a value satisfies the rules as a json literal of builtin type
+func rulesExample(rules *mkdoc.ExtensionRules, typ string) (string, bool) {
+	if rules == nil {
+		return "", false
+	}
+	if len(rules.Enum) > 0 {
+		switch typ {
+		case "string":
+			return enumExample(&mkdoc.ExtensionEnum{Values: []*mkdoc.EnumValue{{Value: rules.Enum[0]}}}, typ)
+		case "bool", "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "byte",
+			"float", "float32", "float64":
+			// the value is not a literal of the type,eg. oneof of the elements
+			if json.Valid([]byte(rules.Enum[0])) {
+				return rules.Enum[0], true
+			}
+		}
+		return "", false
+	}
+	switch typ {
+	case "string":
+		if rules.Format != "" {
+			return formatExample(&mkdoc.ExtensionFormat{Format: rules.Format}, typ)
+		}
+		if rules.MinLength == nil && rules.MaxLength == nil {
+			return "", false
+		}
+		n := int64(3)
+		if rules.MaxLength != nil && n > *rules.MaxLength {
+			n = *rules.MaxLength
+		}
+		if rules.MinLength != nil && n < *rules.MinLength {
+			n = *rules.MinLength
+		}
+		if n < 0 {
+			n = 0
+		}
+		b, _ := json.Marshal(strings.Repeat("s", int(n)))
+		return string(b), true
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "byte":
+		if rules.Minimum == nil && rules.Maximum == nil {
+			return "", false
+		}
+		v := 10.0
+		if rules.Maximum != nil && (v > *rules.Maximum || rules.ExclusiveMaximum && v >= *rules.Maximum) {
+			v = math.Floor(*rules.Maximum)
+			if rules.ExclusiveMaximum && v >= *rules.Maximum {
+				v--
+			}
+		}
+		if rules.Minimum != nil && (v < *rules.Minimum || rules.ExclusiveMinimum && v <= *rules.Minimum) {
+			v = math.Ceil(*rules.Minimum)
+			if rules.ExclusiveMinimum && v <= *rules.Minimum {
+				v++
+			}
+		}
+		return strconv.FormatFloat(v, 'f', 0, 64), true
+	case "float", "float32", "float64":
+		if rules.Minimum == nil && rules.Maximum == nil {
+			return "", false
+		}
+		v := 10.2
+		if rules.Maximum != nil && (v > *rules.Maximum || rules.ExclusiveMaximum && v >= *rules.Maximum) {
+			v = *rules.Maximum
+			if rules.ExclusiveMaximum {
+				v -= 0.1
+			}
+		}
+		if rules.Minimum != nil && (v < *rules.Minimum || rules.ExclusiveMinimum && v <= *rules.Minimum) {
+			v = *rules.Minimum
+			if rules.ExclusiveMinimum {
+				v += 0.1
+			}
+		}
+		return strconv.FormatFloat(v, 'g', -1, 64), true
+	}
+	return "", false
+}
@@ -0,0 +1,23 @@
+package objschema
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Example returns the value of example declared by the example tag or `@example`,
+// the example of string schema is kept as string if it is not a json string,
+// returns nil if the example of other types is not a json literal
+func Example(raw string, s *Schema) interface{} {
+	var v interface{}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(raw)), &v); err != nil {
+		v = nil
+	}
+	if s != nil && s.Type == "string" {
+		if str, ok := v.(string); ok {
+			return str
+		}
+		return raw
+	}
+	return v
+}
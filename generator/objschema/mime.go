@@ -0,0 +1,36 @@
+package objschema
+
+import "strings"
+
+// ContentType convert mkdoc mime type to http content type
+//
+//	form => multipart/form-data
+//	json => application/json
+func ContentType(mime string) string {
+	switch mime {
+	case "form":
+		return "multipart/form-data"
+	case "json":
+		return "application/json"
+	case "xml":
+		return "application/xml"
+	case "":
+		return ""
+	}
+	if strings.Contains(mime, "/") {
+		return mime
+	}
+	return "application/" + mime
+}
+
+// DefaultContentType convert mkdoc mime type to http content type,
+// the default mime type is used if mime is empty,json if both are empty
+func DefaultContentType(mime string, def string) string {
+	if mime == "" {
+		mime = def
+	}
+	if mime == "" {
+		mime = "json"
+	}
+	return ContentType(mime)
+}
@@ -0,0 +1,42 @@
+package objschema
+
+import (
+	"github.com/thewinds/mkdoc"
+	"github.com/thewinds/mkdoc/schema"
+	"strconv"
+	"strings"
+)
+
+// ParamSchema returns the schema of parameter,
+// the type of parameter is a builtin type or an array of it
+func ParamSchema(p *schema.Param) *Schema {
+	if strings.HasPrefix(p.Type, "[]") {
+		return &Schema{Type: "array", Items: ParamSchema(&schema.Param{Type: p.Type[2:], Enum: p.Enum})}
+	}
+	s := Primitive(p.Type)
+	if s.Type == "" || s.Type == "object" {
+		s = &Schema{Type: "string"}
+	}
+	if len(p.Enum) > 0 {
+		s = applyRules(s, &mkdoc.ExtensionRules{Enum: p.Enum}, false)
+	}
+	if p.Default != "" {
+		s.Default = scalarValue(p.Default, s.Type)
+	}
+	return s
+}
+
+// scalarValue convert the value to the json type,the value is kept if it is invalid
+func scalarValue(v string, typ string) interface{} {
+	switch typ {
+	case "integer", "number":
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	}
+	return v
+}
@@ -0,0 +1,13 @@
+package objschema
+
+import "github.com/thewinds/mkdoc"
+
+// PathTemplate convert router style path to OpenAPI path template
+// and returns the path parameter names
+//
+//	/user/:uid/files/*path => /user/{uid}/files/{path}
+func PathTemplate(path string) (string, []string) {
+	return mkdoc.ReplacePathParams(path, func(name string) string {
+		return "{" + name + "}"
+	})
+}
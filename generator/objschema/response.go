@@ -0,0 +1,62 @@
+package objschema
+
+import (
+	"github.com/thewinds/mkdoc"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ResponseCode returns the status code of response in OpenAPI,eg. 4xx => 4XX,
+// the ranges are not supported before OpenAPI 3 so they are default if ranged is false
+func ResponseCode(status string, ranged bool) string {
+	if _, err := strconv.Atoi(status); err == nil || status == "default" {
+		return status
+	}
+	if ranged {
+		return strings.ToUpper(status)
+	}
+	return "default"
+}
+
+// ResponseDesc returns the description of response,
+// the status text is used if the description is omitted
+func ResponseDesc(r *mkdoc.Response) string {
+	if len(r.Desc) > 0 {
+		return r.Desc
+	}
+	code, _ := strconv.Atoi(r.Status)
+	if text := http.StatusText(code); text != "" {
+		return text
+	}
+	if text, ok := rangeTexts[r.Status]; ok {
+		return text
+	}
+	return "Default"
+}
+
+var rangeTexts = map[string]string{
+	"1xx": "Informational",
+	"2xx": "Success",
+	"3xx": "Redirection",
+	"4xx": "Client Error",
+	"5xx": "Server Error",
+}
+
+// OperationDesc returns the description of api with the table of error codes it may return,
+// the description is rendered as markdown by the OpenAPI tools
+func OperationDesc(api *mkdoc.API) string {
+	if len(api.Errors) == 0 {
+		return api.Desc
+	}
+	var sb strings.Builder
+	if desc := strings.TrimSpace(api.Desc); desc != "" {
+		sb.WriteString(desc + "\n\n")
+	}
+	sb.WriteString("| Error Code | Name | Description |\n|---|---|---|\n")
+	for _, e := range api.Errors {
+		desc := strings.Replace(strings.TrimSpace(e.Desc), "|", "\\|", -1)
+		sb.WriteString("| `" + e.Code + "` | " + e.Name + " | " + strings.Replace(desc, "\n", "<br>", -1) + " |\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,342 @@
+package objschema
+
+import (
+	"fmt"
+	"github.com/thewinds/mkdoc"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Schema is a json schema like definition of mkdoc object
+// it's the common subset of OpenAPI 3 and Swagger 2 schema object
+type Schema struct {
+	Ref         string      `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Type        string      `json:"type,omitempty" yaml:"type,omitempty"`
+	Format      string      `json:"format,omitempty" yaml:"format,omitempty"`
+	Description string      `json:"description,omitempty" yaml:"description,omitempty"`
+	Items       *Schema     `json:"items,omitempty" yaml:"items,omitempty"`
+	Properties  *OrderedMap `json:"properties,omitempty" yaml:"properties,omitempty"`
+	// Required are the names of required properties
+	Required []string `json:"required,omitempty" yaml:"required,omitempty"`
+	// AdditionalProperties is the value schema of map
+	AdditionalProperties *Schema     `json:"additionalProperties,omitempty" yaml:"additionalProperties,omitempty"`
+	Default              interface{} `json:"default,omitempty" yaml:"default,omitempty"`
+	Example              interface{} `json:"example,omitempty" yaml:"example,omitempty"`
+	// the validation keywords,
+	// the exclusive bounds are booleans in Swagger 2 and numbers in OpenAPI 3.1
+	Enum             []interface{} `json:"enum,omitempty" yaml:"enum,omitempty"`
+	Minimum          *float64      `json:"minimum,omitempty" yaml:"minimum,omitempty"`
+	Maximum          *float64      `json:"maximum,omitempty" yaml:"maximum,omitempty"`
+	ExclusiveMinimum interface{}   `json:"exclusiveMinimum,omitempty" yaml:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum interface{}   `json:"exclusiveMaximum,omitempty" yaml:"exclusiveMaximum,omitempty"`
+	MinLength        *int64        `json:"minLength,omitempty" yaml:"minLength,omitempty"`
+	MaxLength        *int64        `json:"maxLength,omitempty" yaml:"maxLength,omitempty"`
+	MinItems         *int64        `json:"minItems,omitempty" yaml:"minItems,omitempty"`
+	MaxItems         *int64        `json:"maxItems,omitempty" yaml:"maxItems,omitempty"`
+}
+
+// Builder walk the mkdoc object graph and build schemas
+//
+// named objects are collected into definitions and referenced by `$ref`,
+// anonymous objects(id starts with @),array wrappers and builtin types are inlined
+type Builder struct {
+	refs        map[mkdoc.LangObjectId]*mkdoc.Object
+	refPrefix   string
+	lang        string
+	definitions *OrderedMap
+	names       map[mkdoc.LangObjectId]string
+	nameOwners  map[string]mkdoc.LangObjectId
+	// numericBounds makes the exclusive bounds numbers like JSON Schema 2020-12
+	numericBounds bool
+}
+
+// NewBuilder create a schema builder
+// refPrefix is the prefix of `$ref`,eg. `#/components/schemas/` or `#/definitions/`
+func NewBuilder(refs map[mkdoc.LangObjectId]*mkdoc.Object, refPrefix string) *Builder {
+	return &Builder{
+		refs:        refs,
+		refPrefix:   refPrefix,
+		definitions: NewOrderedMap(),
+		names:       make(map[mkdoc.LangObjectId]string),
+		nameOwners:  make(map[string]mkdoc.LangObjectId),
+	}
+}
+
+func (b *Builder) SetLanguage(lang string) *Builder {
+	b.lang = lang
+	return b
+}
+
+// SetNumericBounds makes the exclusive bounds numbers instead of booleans,
+// OpenAPI 3.1 uses JSON Schema 2020-12 where exclusiveMinimum replaces minimum
+func (b *Builder) SetNumericBounds(numeric bool) *Builder {
+	b.numericBounds = numeric
+	return b
+}
+
+// Definitions all named object schemas which has been referenced
+func (b *Builder) Definitions() *OrderedMap {
+	return b.definitions
+}
+
+// Build the schema of object
+func (b *Builder) Build(object *mkdoc.Object) (*Schema, error) {
+	if object == nil {
+		return nil, nil
+	}
+	if isNamed(object) {
+		return b.buildRef(object.ID)
+	}
+	return b.buildObject(object)
+}
+
+// Resolve get the referenced definition if schema is a `$ref`
+func (b *Builder) Resolve(s *Schema) *Schema {
+	if s == nil || !strings.HasPrefix(s.Ref, b.refPrefix) {
+		return s
+	}
+	def, ok := b.definitions.Get(s.Ref[len(b.refPrefix):])
+	if !ok {
+		return s
+	}
+	return def.(*Schema)
+}
+
+func (b *Builder) buildRef(refID string) (*Schema, error) {
+	id := mkdoc.LangObjectId{Lang: b.lang, Id: refID}
+	refObj := b.refs[id]
+	if refObj == nil {
+		return nil, fmt.Errorf("schema: type %s not exist", refID)
+	}
+	if !isNamed(refObj) {
+		return b.buildObject(refObj)
+	}
+	name, defined := b.names[id]
+	if !defined {
+		name = b.definitionName(id)
+		b.names[id] = name
+		b.nameOwners[name] = id
+		// set a placeholder before walk fields to limit circle ref
+		b.definitions.Set(name, &Schema{})
+		s, err := b.buildObject(refObj)
+		if err != nil {
+			return nil, err
+		}
+		b.definitions.Set(name, s)
+	}
+	return &Schema{Ref: b.refPrefix + name}, nil
+}
+
+func (b *Builder) buildObject(object *mkdoc.Object) (*Schema, error) {
+	s, err := b.buildElem(object)
+	if err != nil {
+		return nil, err
+	}
+	if object.Type.MapKey != "" {
+		s = &Schema{Type: "object", AdditionalProperties: s}
+	}
+	if object.Type.IsRepeated {
+		return &Schema{Type: "array", Items: s}, nil
+	}
+	return s, nil
+}
+
+func (b *Builder) buildElem(object *mkdoc.Object) (*Schema, error) {
+	objType := object.Type
+	if objType.Name != "object" {
+		s := Primitive(objType.Name)
+		if format := mkdoc.FindExtension[*mkdoc.ExtensionFormat](object.Extensions); format != nil {
+			s.Format = format.Format
+		}
+		if opaque := mkdoc.FindExtension[*mkdoc.ExtensionOpaque](object.Extensions); opaque != nil {
+			s.Description = opaque.Reason
+		}
+		return s, nil
+	}
+	if objType.Ref != "" {
+		return b.buildRef(objType.Ref)
+	}
+	s := &Schema{Type: "object"}
+	for _, field := range object.Fields {
+		name := FieldName(field)
+		if name == "" {
+			continue
+		}
+		fs, err := b.buildField(field)
+		if err != nil {
+			return nil, err
+		}
+		if s.Properties == nil {
+			s.Properties = NewOrderedMap()
+		}
+		s.Properties.Set(name, fs)
+		if c := mkdoc.FindExtension[*mkdoc.ExtensionConstraints](field.Extensions); c != nil && c.Required {
+			s.Required = append(s.Required, name)
+		}
+	}
+	return s, nil
+}
+
+func (b *Builder) buildField(field *mkdoc.ObjectField) (*Schema, error) {
+	var s *Schema
+	if field.Type.Ref != "" {
+		ref, err := b.buildRef(field.Type.Ref)
+		if err != nil {
+			return nil, err
+		}
+		s = ref
+	} else {
+		s = Primitive(field.Type.Name)
+	}
+	// the numbers and booleans of `json:",string"` are encoded as string
+	if c := mkdoc.FindExtension[*mkdoc.ExtensionConstraints](field.Extensions); c != nil && c.String && !field.Type.IsRepeated && isScalar(s) {
+		cp := *s
+		cp.Type = "string"
+		s = &cp
+	}
+	if field.Type.IsRepeated {
+		s = &Schema{Type: "array", Items: s}
+	}
+	if rules := mkdoc.FindExtension[*mkdoc.ExtensionRules](field.Extensions); rules != nil && s.Ref == "" {
+		s = applyRules(s, rules, b.numericBounds)
+	}
+	if ex := mkdoc.FindExtension[*mkdoc.ExtensionExample](field.Extensions); ex != nil && s.Ref == "" {
+		cp := *s
+		cp.Example = Example(ex.Value, s)
+		s = &cp
+	}
+	if field.Desc != "" {
+		// copy to avoid write description to the shared ref schema
+		cp := *s
+		cp.Description = field.Desc
+		s = &cp
+	}
+	return s, nil
+}
+
+var reInvalidNameChar = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// definitionName use the last element of package path as name,eg. model.User
+// the full path will be used if name conflict
+func (b *Builder) definitionName(id mkdoc.LangObjectId) string {
+	short := mkdoc.ShortObjectID(id.Id)
+	candidates := []string{
+		short,
+		id.Id,
+		id.Lang + "." + id.Id,
+	}
+	for _, c := range candidates {
+		name := reInvalidNameChar.ReplaceAllString(c, "_")
+		if _, used := b.nameOwners[name]; !used {
+			return name
+		}
+	}
+	return reInvalidNameChar.ReplaceAllString(fmt.Sprintf("%s_%d", short, len(b.nameOwners)), "_")
+}
+
+// isNamed report whether the object should be a definition
+func isNamed(object *mkdoc.Object) bool {
+	if strings.HasPrefix(object.ID, "@") {
+		return false
+	}
+	return object.Type.Name == "object" && object.Type.Ref == ""
+}
+
+// Primitive returns the schema of builtin type
+func Primitive(typ string) *Schema {
+	switch typ {
+	case "string":
+		return &Schema{Type: "string"}
+	case "bool":
+		return &Schema{Type: "boolean"}
+	case "byte", "int8", "int16", "int32", "uint8", "uint16":
+		return &Schema{Type: "integer", Format: "int32"}
+	case "int", "int64", "uint", "uint32", "uint64":
+		return &Schema{Type: "integer", Format: "int64"}
+	case "float", "float32":
+		return &Schema{Type: "number", Format: "float"}
+	case "float64":
+		return &Schema{Type: "number", Format: "double"}
+	case "object":
+		return &Schema{Type: "object"}
+	default:
+		// interface{} and unknown types accept any value
+		return &Schema{}
+	}
+}
+
+// IsRequired report whether the property is required
+func (s *Schema) IsRequired(name string) bool {
+	for _, v := range s.Required {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
+
+// applyRules returns a copy of schema with the validation rules,
+// the exclusive bounds are numbers if numericBounds is true or booleans modify the bounds
+func applyRules(s *Schema, rules *mkdoc.ExtensionRules, numericBounds bool) *Schema {
+	cp := *s
+	switch cp.Type {
+	case "array":
+		cp.MinItems, cp.MaxItems = rules.MinLength, rules.MaxLength
+	case "string":
+		cp.MinLength, cp.MaxLength = rules.MinLength, rules.MaxLength
+		if rules.Format != "" {
+			cp.Format = rules.Format
+		}
+		for _, v := range rules.Enum {
+			cp.Enum = append(cp.Enum, v)
+		}
+	case "integer", "number":
+		cp.Minimum, cp.Maximum = rules.Minimum, rules.Maximum
+		switch {
+		case !rules.ExclusiveMinimum || rules.Minimum == nil:
+		case numericBounds:
+			cp.Minimum, cp.ExclusiveMinimum = nil, *rules.Minimum
+		default:
+			cp.ExclusiveMinimum = true
+		}
+		switch {
+		case !rules.ExclusiveMaximum || rules.Maximum == nil:
+		case numericBounds:
+			cp.Maximum, cp.ExclusiveMaximum = nil, *rules.Maximum
+		default:
+			cp.ExclusiveMaximum = true
+		}
+		for _, v := range rules.Enum {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				cp.Enum = append(cp.Enum, f)
+			}
+		}
+	}
+	return &cp
+}
+
+func isScalar(s *Schema) bool {
+	switch s.Type {
+	case "boolean", "integer", "number":
+		return s.Ref == ""
+	}
+	return false
+}
+
+// FieldName get the json name of field
+// returns "" if the field is ignored by `json:"-"`
+func FieldName(field *mkdoc.ObjectField) string {
+	goTagExt := mkdoc.FindExtension[*mkdoc.ExtensionGoTag](field.Extensions)
+	var jsonTag string
+	if goTagExt != nil {
+		jsonTag = goTagExt.Tag.GetFirstValue("json", ",")
+	}
+	if jsonTag == "-" {
+		return ""
+	}
+	if jsonTag == "" {
+		return field.Name
+	}
+	return jsonTag
+}
@@ -0,0 +1,113 @@
+package objschema
+
+import (
+	"encoding/json"
+	"github.com/thewinds/mkdoc"
+	"testing"
+)
+
+func TestBuilder_Build(t *testing.T) {
+	refs := make(map[mkdoc.LangObjectId]*mkdoc.Object)
+	add := func(obj *mkdoc.Object) {
+		refs[mkdoc.LangObjectId{Lang: "go", Id: obj.ID}] = obj
+	}
+	add(&mkdoc.Object{ID: "int", Type: &mkdoc.ObjectType{Name: "int"}, Loaded: true})
+	add(&mkdoc.Object{
+		ID:   "github.com/a/model.User",
+		Type: &mkdoc.ObjectType{Name: "object"},
+		Fields: []*mkdoc.ObjectField{
+			{Name: "ID", Type: &mkdoc.ObjectType{Name: "int"}, Extensions: []mkdoc.Extension{
+				&mkdoc.ExtensionConstraints{Required: true, String: true},
+			}},
+			{Name: "Profile", Desc: "profile", Type: &mkdoc.ObjectType{Name: "object", Ref: "github.com/a/model.Profile"}},
+		},
+		Loaded: true,
+	})
+	add(&mkdoc.Object{
+		ID:   "github.com/a/model.Profile",
+		Type: &mkdoc.ObjectType{Name: "object"},
+		Fields: []*mkdoc.ObjectField{
+			{Name: "Friends", Type: &mkdoc.ObjectType{Name: "object", Ref: "@obj_arr_#1"}},
+		},
+		Loaded: true,
+	})
+	add(&mkdoc.Object{
+		ID:     "@obj_arr_#1",
+		Type:   &mkdoc.ObjectType{Name: "object", Ref: "github.com/a/model.User", IsRepeated: true},
+		Loaded: true,
+	})
+	add(&mkdoc.Object{
+		ID:     "@obj_map_#2",
+		Type:   &mkdoc.ObjectType{Name: "object", Ref: "github.com/a/model.User", MapKey: "string"},
+		Loaded: true,
+	})
+	// same short name in another package
+	add(&mkdoc.Object{
+		ID:     "github.com/b/model.User",
+		Type:   &mkdoc.ObjectType{Name: "object"},
+		Loaded: true,
+	})
+
+	tests := []struct {
+		objectID string
+		want     string
+	}{
+		{"github.com/a/model.User", `{"$ref":"#/definitions/model.User"}`},
+		{"@obj_arr_#1", `{"type":"array","items":{"$ref":"#/definitions/model.User"}}`},
+		{"@obj_map_#2", `{"type":"object","additionalProperties":{"$ref":"#/definitions/model.User"}}`},
+		{"int", `{"type":"integer","format":"int64"}`},
+		{"github.com/b/model.User", `{"$ref":"#/definitions/github.com_b_model.User"}`},
+	}
+	builder := NewBuilder(refs, "#/definitions/").SetLanguage("go")
+	for _, tt := range tests {
+		s, err := builder.Build(refs[mkdoc.LangObjectId{Lang: "go", Id: tt.objectID}])
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		b, _ := json.Marshal(s)
+		if string(b) != tt.want {
+			t.Errorf("\n got= %s\n want=%s\n", b, tt.want)
+		}
+	}
+
+	wantDefs := `{` +
+		`"model.User":{"type":"object","properties":{"ID":{"type":"string","format":"int64"},"Profile":{"$ref":"#/definitions/model.Profile","description":"profile"}},"required":["ID"]},` +
+		`"model.Profile":{"type":"object","properties":{"Friends":{"type":"array","items":{"$ref":"#/definitions/model.User"}}}},` +
+		`"github.com_b_model.User":{"type":"object"}}`
+	b, _ := json.Marshal(builder.Definitions())
+	if string(b) != wantDefs {
+		t.Errorf("\n got= %s\n want=%s\n", b, wantDefs)
+	}
+}
+
+func TestBuilder_Build_ExclusiveBounds(t *testing.T) {
+	min, max := 0.0, 150.0
+	obj := &mkdoc.Object{
+		ID:   "@obj_in_#1",
+		Type: &mkdoc.ObjectType{Name: "object"},
+		Fields: []*mkdoc.ObjectField{
+			{Name: "Age", Type: &mkdoc.ObjectType{Name: "int"}, Extensions: []mkdoc.Extension{
+				&mkdoc.ExtensionRules{Minimum: &min, ExclusiveMinimum: true, Maximum: &max},
+			}},
+		},
+		Loaded: true,
+	}
+	tests := []struct {
+		numeric bool
+		want    string
+	}{
+		{false, `{"type":"object","properties":{"Age":{"type":"integer","format":"int64","minimum":0,"maximum":150,"exclusiveMinimum":true}}}`},
+		{true, `{"type":"object","properties":{"Age":{"type":"integer","format":"int64","maximum":150,"exclusiveMinimum":0}}}`},
+	}
+	for _, tt := range tests {
+		s, err := NewBuilder(nil, "#/definitions/").SetNumericBounds(tt.numeric).Build(obj)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := json.Marshal(s)
+		if string(b) != tt.want {
+			t.Errorf("numeric %v\n got= %s\n want=%s\n", tt.numeric, b, tt.want)
+		}
+	}
+}
@@ -0,0 +1,269 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/go-yaml/yaml"
+	"github.com/thewinds/mkdoc"
+	"github.com/thewinds/mkdoc/generator/objschema"
+	"github.com/thewinds/mkdoc/schema"
+	"strings"
+)
+
+type Generator struct{}
+
+func init() {
+	mkdoc.RegisterGenerator(&Generator{})
+}
+
+const refPrefix = "#/components/schemas/"
+
+var httpMethods = map[string]bool{
+	"get": true, "put": true, "post": true, "delete": true,
+	"options": true, "head": true, "patch": true, "trace": true,
+}
+
+// Gen make a OpenAPI 3.1 document
+//
+// args:
+//
+//	format:  yaml,json or empty(both)
+//	version: version of the api,default is 1.0.0
+func (g *Generator) Gen(ctx *mkdoc.DocGenContext) (output *mkdoc.GeneratedOutput, err error) {
+	version := ctx.Args["version"]
+	if version == "" {
+		version = "1.0.0"
+	}
+	doc := &document{
+		OpenAPI: "3.1.0",
+		Info: &info{
+			Title:       ctx.Config.Name,
+			Description: ctx.Config.Description,
+			Version:     version,
+		},
+		Paths:      objschema.NewOrderedMap(),
+		Components: &components{},
+	}
+	if ctx.Config.APIBaseURL != "" {
+		doc.Servers = append(doc.Servers, &server{URL: ctx.Config.APIBaseURL})
+	}
+
+	var commonParams []*parameter
+	sharedParams := objschema.NewOrderedMap()
+	for _, e := range ctx.Config.Injects {
+		switch e.Scope {
+		case "header", "query":
+			if e.Name == "" {
+				continue
+			}
+			p := &parameter{
+				Name:        e.Name,
+				In:          e.Scope,
+				Description: e.Desc,
+				Schema:      &objschema.Schema{Type: "string"},
+			}
+			if e.Default != "" {
+				p.Example = e.Default
+			}
+			sharedParams.Set(e.Name, p)
+			commonParams = append(commonParams, &parameter{Ref: "#/components/parameters/" + e.Name})
+		}
+	}
+	if sharedParams.Len() > 0 {
+		doc.Components.Parameters = sharedParams
+	}
+
+	// the mime types of config are used if the api doesn't declare
+	var mimeIn, mimeOut string
+	if ctx.Config.Mime != nil {
+		mimeIn, mimeOut = ctx.Config.Mime.In, ctx.Config.Mime.Out
+	}
+	builder := objschema.NewBuilder(ctx.RefObj, refPrefix).SetNumericBounds(true)
+	for _, api := range ctx.APIs {
+		path, pathParams := objschema.PathTemplate(api.Path)
+		if path == "" {
+			continue
+		}
+		method := strings.ToLower(api.Method)
+		if !httpMethods[method] {
+			method = "post"
+		}
+		op := &operation{
+			Tags:        api.Tags,
+			Summary:     api.Name,
+			Description: objschema.OperationDesc(api),
+			Responses:   objschema.NewOrderedMap(),
+		}
+		for _, name := range pathParams {
+			param := &parameter{
+				Name:     name,
+				In:       "path",
+				Required: true,
+				Schema:   &objschema.Schema{Type: "string"},
+			}
+			if p := api.PathParams.Get(name); p != nil {
+				param.Description = p.Desc
+				param.Schema = objschema.ParamSchema(p)
+			}
+			op.Parameters = append(op.Parameters, param)
+		}
+		op.Parameters = append(op.Parameters, params(api.Query, "query")...)
+		op.Parameters = append(op.Parameters, params(api.Header, "header")...)
+		op.Parameters = append(op.Parameters, commonParams...)
+
+		if api.InArgument != nil || api.InExample != "" {
+			media, err := newMediaType(builder.SetLanguage(api.InLanguage), api.InArgument, api.InExample)
+			if err != nil {
+				return nil, err
+			}
+			op.RequestBody = &requestBody{
+				Required: true,
+				Content: map[string]*mediaType{
+					objschema.DefaultContentType(api.Mime.In, mimeIn): media,
+				},
+			}
+		}
+
+		for _, r := range api.AllResponses() {
+			resp := &response{Description: objschema.ResponseDesc(r)}
+			if r.Argument != nil || r.Example != "" {
+				media, err := newMediaType(builder.SetLanguage(r.Language), r.Argument, r.Example)
+				if err != nil {
+					return nil, err
+				}
+				resp.Content = map[string]*mediaType{
+					objschema.DefaultContentType(r.Mime, mimeOut): media,
+				}
+			}
+			op.Responses.Set(objschema.ResponseCode(r.Status, true), resp)
+		}
+
+		var pathItem *objschema.OrderedMap
+		if v, ok := doc.Paths.Get(path); ok {
+			pathItem = v.(*objschema.OrderedMap)
+		} else {
+			pathItem = objschema.NewOrderedMap()
+			doc.Paths.Set(path, pathItem)
+		}
+		pathItem.Set(method, op)
+	}
+	if builder.Definitions().Len() > 0 {
+		doc.Components.Schemas = builder.Definitions()
+	}
+	if doc.Components.Schemas == nil && doc.Components.Parameters == nil {
+		doc.Components = nil
+	}
+
+	output = &mkdoc.GeneratedOutput{}
+	format := ctx.Args["format"]
+	if format == "" || format == "yaml" {
+		b, err := yaml.Marshal(doc)
+		if err != nil {
+			return nil, err
+		}
+		output.Files = append(output.Files, &mkdoc.GeneratedFile{Name: "openapi.yaml", Data: b})
+	}
+	if format == "" || format == "json" {
+		b, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		output.Files = append(output.Files, &mkdoc.GeneratedFile{Name: "openapi.json", Data: b})
+	}
+	if len(output.Files) == 0 {
+		return nil, fmt.Errorf("openapi: unknown format '%s'", format)
+	}
+	return output, nil
+}
+
+func (g *Generator) Name() string {
+	return "openapi"
+}
+
+// params convert the query or header parameters
+func params(list schema.Params, in string) []*parameter {
+	params := make([]*parameter, 0, len(list))
+	for _, p := range list {
+		params = append(params, &parameter{
+			Name:        p.Name,
+			In:          in,
+			Description: p.Desc,
+			Required:    p.Required,
+			Schema:      objschema.ParamSchema(p),
+		})
+	}
+	return params
+}
+
+type document struct {
+	OpenAPI    string                `json:"openapi" yaml:"openapi"`
+	Info       *info                 `json:"info" yaml:"info"`
+	Servers    []*server             `json:"servers,omitempty" yaml:"servers,omitempty"`
+	Paths      *objschema.OrderedMap `json:"paths" yaml:"paths"`
+	Components *components           `json:"components,omitempty" yaml:"components,omitempty"`
+}
+
+type info struct {
+	Title       string `json:"title" yaml:"title"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Version     string `json:"version" yaml:"version"`
+}
+
+type server struct {
+	URL string `json:"url" yaml:"url"`
+}
+
+type components struct {
+	Schemas    *objschema.OrderedMap `json:"schemas,omitempty" yaml:"schemas,omitempty"`
+	Parameters *objschema.OrderedMap `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+}
+
+type operation struct {
+	Tags        []string              `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Summary     string                `json:"summary,omitempty" yaml:"summary,omitempty"`
+	Description string                `json:"description,omitempty" yaml:"description,omitempty"`
+	Parameters  []*parameter          `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	RequestBody *requestBody          `json:"requestBody,omitempty" yaml:"requestBody,omitempty"`
+	Responses   *objschema.OrderedMap `json:"responses" yaml:"responses"`
+}
+
+type parameter struct {
+	Ref         string            `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Name        string            `json:"name,omitempty" yaml:"name,omitempty"`
+	In          string            `json:"in,omitempty" yaml:"in,omitempty"`
+	Description string            `json:"description,omitempty" yaml:"description,omitempty"`
+	Required    bool              `json:"required,omitempty" yaml:"required,omitempty"`
+	Schema      *objschema.Schema `json:"schema,omitempty" yaml:"schema,omitempty"`
+	Example     string            `json:"example,omitempty" yaml:"example,omitempty"`
+}
+
+type requestBody struct {
+	Required bool                  `json:"required,omitempty" yaml:"required,omitempty"`
+	Content  map[string]*mediaType `json:"content" yaml:"content"`
+}
+
+type mediaType struct {
+	Schema  *objschema.Schema `json:"schema,omitempty" yaml:"schema,omitempty"`
+	Example interface{}       `json:"example,omitempty" yaml:"example,omitempty"`
+}
+
+// newMediaType returns the media type of body,the example is declared by `@example`
+func newMediaType(builder *objschema.Builder, obj *mkdoc.Object, example string) (*mediaType, error) {
+	media := &mediaType{}
+	if obj != nil {
+		s, err := builder.Build(obj)
+		if err != nil {
+			return nil, err
+		}
+		media.Schema = s
+	}
+	if example != "" {
+		media.Example = objschema.Example(example, nil)
+	}
+	return media, nil
+}
+
+type response struct {
+	Description string                `json:"description" yaml:"description"`
+	Content     map[string]*mediaType `json:"content,omitempty" yaml:"content,omitempty"`
+}
@@ -0,0 +1,81 @@
+package openapi
+
+import (
+	"bytes"
+	"flag"
+	"github.com/thewinds/mkdoc"
+	"github.com/thewinds/mkdoc/schema"
+	"os"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func TestGenerator_Gen(t *testing.T) {
+	refs := make(map[mkdoc.LangObjectId]*mkdoc.Object)
+	add := func(lang string, obj *mkdoc.Object) *mkdoc.Object {
+		refs[mkdoc.LangObjectId{Lang: lang, Id: obj.ID}] = obj
+		return obj
+	}
+	// the out type is in the other language,eg. `@out proto:model.User`
+	user := add("proto", &mkdoc.Object{
+		ID:   "github.com/a/model.User",
+		Type: &mkdoc.ObjectType{Name: "object"},
+		Fields: []*mkdoc.ObjectField{
+			{Name: "ID", Desc: "用户 ID", Type: &mkdoc.ObjectType{Name: "int"}},
+			{Name: "Name", Desc: "用户名", Type: &mkdoc.ObjectType{Name: "string"}},
+		},
+		Loaded: true,
+	})
+	in := add("go", &mkdoc.Object{
+		ID:   "@obj_in_#1",
+		Type: &mkdoc.ObjectType{Name: "object"},
+		Fields: []*mkdoc.ObjectField{
+			{Name: "name", Desc: "用户名", Type: &mkdoc.ObjectType{Name: "string"}},
+		},
+		Loaded: true,
+	})
+	api := &mkdoc.API{
+		API: schema.API{
+			Name:       "修改用户",
+			Desc:       "update the user",
+			Method:     "PUT",
+			Path:       "/user/:uid",
+			Tags:       []string{"user"},
+			PathParams: schema.Params{{Name: "uid", Type: "int", Desc: "用户 ID", Required: true}},
+			Query:      schema.Params{{Name: "notify", Type: "bool", Default: "true", Desc: "是否通知"}},
+			Language:   "go",
+		},
+		InArgument:  in,
+		InLanguage:  "go",
+		OutArgument: user,
+		OutLanguage: "proto",
+		Errors:      []*mkdoc.ErrorCode{{Code: "10024", Name: "ErrUserNotFound", Desc: "用户不存在"}},
+		// the out mime type is the mime type of config
+		Mime: &mkdoc.MimeType{In: "json"},
+	}
+	ctx := &mkdoc.DocGenContext{
+		APIs:   []*mkdoc.API{api},
+		Config: mkdoc.Config{Name: "example", APIBaseURL: "http://localhost:8080", Mime: &mkdoc.MimeType{In: "form", Out: "json"}},
+		RefObj: refs,
+		Args:   map[string]string{"format": "json"},
+	}
+	output, err := new(Generator).Gen(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := output.Files[0].Data
+	golden := "testdata/openapi.golden.json"
+	if *update {
+		if err := os.WriteFile(golden, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output of %s got:\n%s", golden, got)
+	}
+}
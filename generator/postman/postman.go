@@ -0,0 +1,372 @@
+package postman
+
+import (
+	"crypto/md5"
+	"encoding/json"
+	"fmt"
+	"github.com/thewinds/mkdoc"
+	"github.com/thewinds/mkdoc/generator/objfield"
+	"github.com/thewinds/mkdoc/generator/objmock"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const collectionSchema = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+type Generator struct{}
+
+func init() {
+	mkdoc.RegisterGenerator(&Generator{})
+}
+
+func (g *Generator) Gen(ctx *mkdoc.DocGenContext) (output *mkdoc.GeneratedOutput, err error) {
+	name := ctx.Config.Name
+	if ctx.Tag != "" {
+		name = fmt.Sprintf("%s-%s", ctx.Tag, ctx.Config.Name)
+	}
+	coll := &collection{
+		Info: &collectionInfo{
+			PostmanID:   genUUID("collection", name),
+			Name:        name,
+			Description: ctx.Config.Description,
+			Schema:      collectionSchema,
+		},
+		Item:     make([]interface{}, 0),
+		Variable: []*variable{{Key: "base_url", Value: ctx.Config.APIBaseURL}},
+	}
+
+	env := &environment{
+		ID:    genUUID("environment", name),
+		Name:  name,
+		Scope: "environment",
+		Values: []*envValue{
+			{Key: "base_url", Value: ctx.Config.APIBaseURL, Enabled: true},
+		},
+	}
+
+	var commonHeaderScript []string
+	var commonQuery []*queryParam
+	var commonFormParam []*formParam
+
+	for _, e := range ctx.Config.Injects {
+		if e.Name == "" {
+			continue
+		}
+		env.Values = append(env.Values, &envValue{Key: e.Name, Value: e.Default, Enabled: true})
+		switch e.Scope {
+		case "header":
+			commonHeaderScript = append(commonHeaderScript,
+				fmt.Sprintf("pm.request.headers.upsert({key: %q, value: pm.variables.get(%q)});", e.Name, e.Name))
+		case "query":
+			commonQuery = append(commonQuery, &queryParam{
+				Key:         e.Name,
+				Value:       fmt.Sprintf("{{%s}}", e.Name),
+				Description: e.Desc,
+			})
+		case "form", "form_param":
+			commonFormParam = append(commonFormParam, &formParam{
+				Key:         e.Name,
+				Value:       fmt.Sprintf("{{%s}}", e.Name),
+				Description: e.Desc,
+				Type:        "text",
+			})
+		}
+	}
+	// postman has no collection level header,add them by a pre-request script
+	if len(commonHeaderScript) > 0 {
+		coll.Event = append(coll.Event, &event{
+			Listen: "prerequest",
+			Script: &script{Type: "text/javascript", Exec: commonHeaderScript},
+		})
+	}
+
+	folders := make(map[string]*folder)
+	var folderNames []string
+	pathVars := make(map[string]bool)
+	for _, api := range ctx.APIs {
+		req := &request{
+			Method:      strings.ToUpper(api.Method),
+			Description: api.Desc,
+			Header:      make([]*header, 0),
+		}
+		for _, p := range api.Header {
+			req.Header = append(req.Header, &header{
+				Key:         p.Name,
+				Value:       p.Default,
+				Description: p.Desc,
+				Type:        "text",
+			})
+		}
+
+		query := make([]*queryParam, 0, len(api.Query)+len(commonQuery))
+		for _, p := range api.Query {
+			query = append(query, &queryParam{Key: p.Name, Value: p.Default, Description: p.Desc})
+		}
+		query = append(query, commonQuery...)
+		// the path parameters are filled by the collection variables
+		path, _ := mkdoc.ReplacePathParams(api.Path, func(name string) string {
+			return fmt.Sprintf("{{%s}}", name)
+		})
+		for _, p := range api.PathParams {
+			if !pathVars[p.Name] {
+				pathVars[p.Name] = true
+				coll.Variable = append(coll.Variable, &variable{Key: p.Name, Value: ""})
+			}
+		}
+		req.URL = newURL(path, query)
+
+		switch api.Mime.In {
+		case "json":
+			text, err := objmock.NewJSONMocker().SetLanguage(api.InLanguage).WithExample(api.InExample).MockPretty(api.InArgument, ctx.RefObj)
+			if err != nil {
+				return nil, err
+			}
+			req.Header = append(req.Header, &header{Key: "Content-Type", Value: "application/json", Type: "text"})
+			req.Body = &body{
+				Mode:    "raw",
+				Raw:     text,
+				Options: &bodyOptions{Raw: &rawOptions{Language: "json"}},
+			}
+		default:
+			// default: form
+			b := &body{Mode: "formdata", FormData: make([]*formParam, 0)}
+			if api.InArgument != nil {
+				for _, field := range api.InArgument.Fields {
+					paramName := objfield.FormFieldName(field)
+					if paramName == "" {
+						continue
+					}
+					b.FormData = append(b.FormData, &formParam{
+						Key:         paramName,
+						Value:       "",
+						Description: field.Desc,
+						Type:        "text",
+					})
+				}
+			}
+			b.FormData = append(b.FormData, commonFormParam...)
+			req.Body = b
+		}
+
+		it := &item{Name: api.Name, Request: req, Response: []interface{}{}}
+		// the success response is saved as the example
+		if r := api.SuccessResponse(); r != nil && (r.Argument != nil || r.Example != "") {
+			text, err := objmock.NewJSONMocker().SetLanguage(r.Language).WithExample(r.Example).MockPretty(r.Argument, ctx.RefObj)
+			if err != nil {
+				return nil, err
+			}
+			it.Response = append(it.Response, newResponse(r, req, text))
+		}
+		if len(api.Tags) == 0 {
+			coll.Item = append(coll.Item, it)
+			continue
+		}
+		for _, tag := range api.Tags {
+			f := folders[tag]
+			if f == nil {
+				f = &folder{Name: tag}
+				folders[tag] = f
+				folderNames = append(folderNames, tag)
+			}
+			f.Item = append(f.Item, it)
+		}
+	}
+	sort.Strings(folderNames)
+	for _, name := range folderNames {
+		coll.Item = append(coll.Item, folders[name])
+	}
+
+	var outName string
+	if ctx.Tag == "" {
+		outName = fmt.Sprintf("all_doc_%s", time.Now().Format("2006_01_02_150405"))
+	} else {
+		outName = ctx.Tag
+	}
+
+	collData, err := json.MarshalIndent(coll, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	envData, err := json.MarshalIndent(env, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	output = &mkdoc.GeneratedOutput{}
+	output.Files = append(output.Files,
+		&mkdoc.GeneratedFile{Name: outName + ".postman_collection.json", Data: collData},
+		&mkdoc.GeneratedFile{Name: outName + ".postman_environment.json", Data: envData},
+	)
+	return output, nil
+}
+
+func (g *Generator) Name() string {
+	return "postman"
+}
+
+func newURL(path string, query []*queryParam) *url {
+	u := &url{
+		Host:  []string{"{{base_url}}"},
+		Query: query,
+	}
+	for _, seg := range strings.Split(path, "/") {
+		if seg != "" {
+			u.Path = append(u.Path, seg)
+		}
+	}
+	raw := "{{base_url}}" + path
+	for i, q := range query {
+		sep := "&"
+		if i == 0 {
+			sep = "?"
+		}
+		raw += sep + q.Key + "=" + q.Value
+	}
+	u.Raw = raw
+	return u
+}
+
+// genUUID returns an uuid derived from the keys,
+// the ids are stable between runs so that the exports can be diffed
+func genUUID(keys ...string) string {
+	s := md5.Sum([]byte(strings.Join(keys, "\x00")))
+	return fmt.Sprintf("%x-%x-%x-%x-%x", s[0:4], s[4:6], s[6:8], s[8:10], s[10:])
+}
+
+type collection struct {
+	Info     *collectionInfo `json:"info"`
+	Item     []interface{}   `json:"item"`
+	Event    []*event        `json:"event,omitempty"`
+	Variable []*variable     `json:"variable,omitempty"`
+}
+
+type collectionInfo struct {
+	PostmanID   string `json:"_postman_id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Schema      string `json:"schema"`
+}
+
+type folder struct {
+	Name string        `json:"name"`
+	Item []interface{} `json:"item"`
+}
+
+type item struct {
+	Name     string        `json:"name"`
+	Request  *request      `json:"request"`
+	Response []interface{} `json:"response"`
+}
+
+// response is the saved example response of request
+type response struct {
+	Name            string    `json:"name"`
+	OriginalRequest *request  `json:"originalRequest"`
+	Status          string    `json:"status"`
+	Code            int       `json:"code"`
+	PreviewLanguage string    `json:"_postman_previewlanguage"`
+	Header          []*header `json:"header"`
+	Body            string    `json:"body"`
+}
+
+// newResponse returns the example response of request,
+// the code of ranged status is the first code of range,eg. 2xx => 200
+func newResponse(r *mkdoc.Response, req *request, body string) *response {
+	code, err := strconv.Atoi(r.Status)
+	if err != nil {
+		code = int(r.Status[0]-'0') * 100
+	}
+	name := r.Desc
+	if name == "" {
+		name = http.StatusText(code)
+	}
+	return &response{
+		Name:            name,
+		OriginalRequest: req,
+		Status:          http.StatusText(code),
+		Code:            code,
+		PreviewLanguage: "json",
+		Header:          []*header{{Key: "Content-Type", Value: "application/json", Type: "text"}},
+		Body:            body,
+	}
+}
+
+type request struct {
+	Method      string    `json:"method"`
+	Header      []*header `json:"header"`
+	Body        *body     `json:"body,omitempty"`
+	URL         *url      `json:"url"`
+	Description string    `json:"description,omitempty"`
+}
+
+type header struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Description string `json:"description,omitempty"`
+	Type        string `json:"type"`
+}
+
+type body struct {
+	Mode     string       `json:"mode"`
+	Raw      string       `json:"raw,omitempty"`
+	FormData []*formParam `json:"formdata,omitempty"`
+	Options  *bodyOptions `json:"options,omitempty"`
+}
+
+type bodyOptions struct {
+	Raw *rawOptions `json:"raw"`
+}
+
+type rawOptions struct {
+	Language string `json:"language"`
+}
+
+type formParam struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Description string `json:"description,omitempty"`
+	Type        string `json:"type"`
+}
+
+type url struct {
+	Raw   string        `json:"raw"`
+	Host  []string      `json:"host"`
+	Path  []string      `json:"path"`
+	Query []*queryParam `json:"query,omitempty"`
+}
+
+type queryParam struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Description string `json:"description,omitempty"`
+}
+
+type event struct {
+	Listen string  `json:"listen"`
+	Script *script `json:"script"`
+}
+
+type script struct {
+	Type string   `json:"type"`
+	Exec []string `json:"exec"`
+}
+
+type variable struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type environment struct {
+	ID     string      `json:"id"`
+	Name   string      `json:"name"`
+	Values []*envValue `json:"values"`
+	Scope  string      `json:"_postman_variable_scope"`
+}
+
+type envValue struct {
+	Key     string `json:"key"`
+	Value   string `json:"value"`
+	Enabled bool   `json:"enabled"`
+}
@@ -0,0 +1,64 @@
+package postman
+
+import (
+	"encoding/json"
+	"github.com/thewinds/mkdoc"
+	"github.com/thewinds/mkdoc/schema"
+	"testing"
+)
+
+func goTag(raw string) mkdoc.Extension {
+	tag, err := mkdoc.NewObjectFieldTag(raw)
+	if err != nil {
+		panic(err)
+	}
+	return &mkdoc.ExtensionGoTag{Tag: tag}
+}
+
+func TestGenerator_Gen_FormData(t *testing.T) {
+	in := &mkdoc.Object{
+		ID:   "@obj_in_#1",
+		Type: &mkdoc.ObjectType{Name: "object"},
+		Fields: []*mkdoc.ObjectField{
+			{Name: "Name", Type: &mkdoc.ObjectType{Name: "string"}, Extensions: []mkdoc.Extension{goTag(`form:"user_name" json:"name"`)}},
+			{Name: "Age", Type: &mkdoc.ObjectType{Name: "int"}, Extensions: []mkdoc.Extension{goTag(`json:"age,omitempty"`)}},
+			{Name: "Secret", Type: &mkdoc.ObjectType{Name: "string"}, Extensions: []mkdoc.Extension{goTag(`form:"-"`)}},
+			{Name: "Email", Type: &mkdoc.ObjectType{Name: "string"}},
+		},
+		Loaded: true,
+	}
+	ctx := &mkdoc.DocGenContext{
+		APIs: []*mkdoc.API{{
+			API:        schema.API{Name: "create user", Method: "post", Path: "/user", Language: "go"},
+			InArgument: in,
+			InLanguage: "go",
+			Mime:       &mkdoc.MimeType{In: "form", Out: "json"},
+		}},
+		Config: mkdoc.Config{Name: "example"},
+		RefObj: map[mkdoc.LangObjectId]*mkdoc.Object{},
+		Tag:    "user",
+	}
+	output, err := new(Generator).Gen(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	coll := new(collection)
+	coll.Item = []interface{}{new(item)}
+	if err := json.Unmarshal(output.Files[0].Data, coll); err != nil {
+		t.Fatal(err)
+	}
+	req := coll.Item[0].(*item).Request
+	var keys []string
+	for _, p := range req.Body.FormData {
+		keys = append(keys, p.Key)
+	}
+	want := []string{"user_name", "age", "Email"}
+	if len(keys) != len(want) {
+		t.Fatalf("form keys got %v want %v", keys, want)
+	}
+	for i := range want {
+		if keys[i] != want[i] {
+			t.Errorf("form keys got %v want %v", keys, want)
+		}
+	}
+}
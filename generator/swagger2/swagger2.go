@@ -0,0 +1,318 @@
+package swagger2
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/go-yaml/yaml"
+	"github.com/thewinds/mkdoc"
+	"github.com/thewinds/mkdoc/generator/objschema"
+	"github.com/thewinds/mkdoc/schema"
+	"net/url"
+	"strings"
+)
+
+type Generator struct{}
+
+func init() {
+	mkdoc.RegisterGenerator(&Generator{})
+}
+
+const refPrefix = "#/definitions/"
+
+var httpMethods = map[string]bool{
+	"get": true, "put": true, "post": true, "delete": true,
+	"options": true, "head": true, "patch": true,
+}
+
+// Gen make a Swagger 2.0 document
+//
+// args:
+//
+//	format:  yaml,json or empty(both)
+//	version: version of the api,default is 1.0.0
+func (g *Generator) Gen(ctx *mkdoc.DocGenContext) (output *mkdoc.GeneratedOutput, err error) {
+	version := ctx.Args["version"]
+	if version == "" {
+		version = "1.0.0"
+	}
+	doc := &document{
+		Swagger: "2.0",
+		Info: &info{
+			Title:       ctx.Config.Name,
+			Description: ctx.Config.Description,
+			Version:     version,
+		},
+		Paths: objschema.NewOrderedMap(),
+	}
+	if u, err := url.Parse(ctx.Config.APIBaseURL); err == nil && u.Host != "" {
+		doc.Host = u.Host
+		doc.BasePath = u.Path
+		if u.Scheme != "" {
+			doc.Schemes = []string{u.Scheme}
+		}
+	}
+
+	var commonParams, commonFormParams []*parameter
+	sharedParams := objschema.NewOrderedMap()
+	for _, e := range ctx.Config.Injects {
+		if e.Name == "" {
+			continue
+		}
+		p := &parameter{
+			Name:        e.Name,
+			Description: e.Desc,
+			Type:        "string",
+		}
+		if e.Default != "" {
+			p.Default = e.Default
+		}
+		ref := &parameter{Ref: "#/parameters/" + e.Name}
+		switch e.Scope {
+		case "header", "query":
+			p.In = e.Scope
+			commonParams = append(commonParams, ref)
+		case "form", "form_param":
+			p.In = "formData"
+			commonFormParams = append(commonFormParams, ref)
+		default:
+			continue
+		}
+		sharedParams.Set(e.Name, p)
+	}
+	if sharedParams.Len() > 0 {
+		doc.Parameters = sharedParams
+	}
+
+	// the mime types of config are used if the api doesn't declare
+	var mimeIn, mimeOut string
+	if ctx.Config.Mime != nil {
+		mimeIn, mimeOut = ctx.Config.Mime.In, ctx.Config.Mime.Out
+	}
+	builder := objschema.NewBuilder(ctx.RefObj, refPrefix)
+	for _, api := range ctx.APIs {
+		path, pathParams := objschema.PathTemplate(api.Path)
+		if path == "" {
+			continue
+		}
+		method := strings.ToLower(api.Method)
+		if !httpMethods[method] {
+			method = "post"
+		}
+		op := &operation{
+			Tags:        api.Tags,
+			Summary:     api.Name,
+			Description: objschema.OperationDesc(api),
+			Responses:   objschema.NewOrderedMap(),
+		}
+		for _, name := range pathParams {
+			param := &parameter{
+				Name:     name,
+				In:       "path",
+				Required: true,
+				Type:     "string",
+			}
+			if p := api.PathParams.Get(name); p != nil {
+				param = newParam(p, "path")
+				param.Required = true
+			}
+			op.Parameters = append(op.Parameters, param)
+		}
+		for _, p := range api.Query {
+			op.Parameters = append(op.Parameters, newParam(p, "query"))
+		}
+		for _, p := range api.Header {
+			op.Parameters = append(op.Parameters, newParam(p, "header"))
+		}
+		op.Parameters = append(op.Parameters, commonParams...)
+
+		consume := objschema.DefaultContentType(api.Mime.In, mimeIn)
+		isForm := consume == objschema.ContentType("form")
+		if api.InArgument != nil || (isForm && len(commonFormParams) > 0) {
+			op.Consumes = []string{consume}
+		}
+		if api.InArgument != nil {
+			s, err := builder.SetLanguage(api.InLanguage).Build(api.InArgument)
+			if err != nil {
+				return nil, err
+			}
+			if isForm {
+				op.Parameters = append(op.Parameters, formParams(builder.Resolve(s))...)
+			} else {
+				op.Parameters = append(op.Parameters, &parameter{
+					Name:     "body",
+					In:       "body",
+					Required: true,
+					Schema:   s,
+				})
+			}
+		}
+		if isForm {
+			op.Parameters = append(op.Parameters, commonFormParams...)
+		}
+
+		for _, r := range api.AllResponses() {
+			code := objschema.ResponseCode(r.Status, false)
+			// the ranged responses share the default response
+			if _, ok := op.Responses.Get(code); ok {
+				continue
+			}
+			resp := &response{Description: objschema.ResponseDesc(r)}
+			if r.Argument != nil {
+				s, err := builder.SetLanguage(r.Language).Build(r.Argument)
+				if err != nil {
+					return nil, err
+				}
+				resp.Schema = s
+				if op.Produces == nil {
+					op.Produces = []string{objschema.DefaultContentType(r.Mime, mimeOut)}
+				}
+			}
+			if r.Example != "" {
+				resp.Examples = map[string]interface{}{
+					objschema.DefaultContentType(r.Mime, mimeOut): objschema.Example(r.Example, nil),
+				}
+			}
+			op.Responses.Set(code, resp)
+		}
+
+		var pathItem *objschema.OrderedMap
+		if v, ok := doc.Paths.Get(path); ok {
+			pathItem = v.(*objschema.OrderedMap)
+		} else {
+			pathItem = objschema.NewOrderedMap()
+			doc.Paths.Set(path, pathItem)
+		}
+		pathItem.Set(method, op)
+	}
+	if builder.Definitions().Len() > 0 {
+		doc.Definitions = builder.Definitions()
+	}
+
+	output = &mkdoc.GeneratedOutput{}
+	format := ctx.Args["format"]
+	if format == "" || format == "yaml" {
+		b, err := yaml.Marshal(doc)
+		if err != nil {
+			return nil, err
+		}
+		output.Files = append(output.Files, &mkdoc.GeneratedFile{Name: "swagger.yaml", Data: b})
+	}
+	if format == "" || format == "json" {
+		b, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		output.Files = append(output.Files, &mkdoc.GeneratedFile{Name: "swagger.json", Data: b})
+	}
+	if len(output.Files) == 0 {
+		return nil, fmt.Errorf("swagger2: unknown format '%s'", format)
+	}
+	return output, nil
+}
+
+func (g *Generator) Name() string {
+	return "swagger2"
+}
+
+// formParams expand the top level properties to formData parameters
+// formData parameter only support primitive types and array of them,
+// nested objects will be sent as string
+func formParams(s *objschema.Schema) []*parameter {
+	if s == nil || s.Properties == nil {
+		return nil
+	}
+	var params []*parameter
+	for _, name := range s.Properties.Keys() {
+		v, _ := s.Properties.Get(name)
+		prop := v.(*objschema.Schema)
+		p := &parameter{
+			Name:        name,
+			In:          "formData",
+			Description: prop.Description,
+			Required:    s.IsRequired(name),
+			Type:        prop.Type,
+			Format:      prop.Format,
+		}
+		switch {
+		case prop.Type == "array" && prop.Items != nil && isPrimitive(prop.Items):
+			p.Items = &objschema.Schema{Type: prop.Items.Type, Format: prop.Items.Format}
+		case !isPrimitive(prop):
+			p.Type = "string"
+			p.Format = ""
+		}
+		params = append(params, p)
+	}
+	return params
+}
+
+func isPrimitive(s *objschema.Schema) bool {
+	switch s.Type {
+	case "string", "boolean", "integer", "number":
+		return s.Ref == ""
+	}
+	return false
+}
+
+// newParam convert the path,query or header parameter,
+// the parameters which are not in body are described by type rather than schema
+func newParam(p *schema.Param, in string) *parameter {
+	s := objschema.ParamSchema(p)
+	return &parameter{
+		Name:        p.Name,
+		In:          in,
+		Description: p.Desc,
+		Required:    p.Required,
+		Type:        s.Type,
+		Format:      s.Format,
+		Items:       s.Items,
+		Default:     s.Default,
+		Enum:        s.Enum,
+	}
+}
+
+type document struct {
+	Swagger     string                `json:"swagger" yaml:"swagger"`
+	Info        *info                 `json:"info" yaml:"info"`
+	Host        string                `json:"host,omitempty" yaml:"host,omitempty"`
+	BasePath    string                `json:"basePath,omitempty" yaml:"basePath,omitempty"`
+	Schemes     []string              `json:"schemes,omitempty" yaml:"schemes,omitempty"`
+	Paths       *objschema.OrderedMap `json:"paths" yaml:"paths"`
+	Definitions *objschema.OrderedMap `json:"definitions,omitempty" yaml:"definitions,omitempty"`
+	Parameters  *objschema.OrderedMap `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+}
+
+type info struct {
+	Title       string `json:"title" yaml:"title"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Version     string `json:"version" yaml:"version"`
+}
+
+type operation struct {
+	Tags        []string              `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Summary     string                `json:"summary,omitempty" yaml:"summary,omitempty"`
+	Description string                `json:"description,omitempty" yaml:"description,omitempty"`
+	Consumes    []string              `json:"consumes,omitempty" yaml:"consumes,omitempty"`
+	Produces    []string              `json:"produces,omitempty" yaml:"produces,omitempty"`
+	Parameters  []*parameter          `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	Responses   *objschema.OrderedMap `json:"responses" yaml:"responses"`
+}
+
+type parameter struct {
+	Ref         string            `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Name        string            `json:"name,omitempty" yaml:"name,omitempty"`
+	In          string            `json:"in,omitempty" yaml:"in,omitempty"`
+	Description string            `json:"description,omitempty" yaml:"description,omitempty"`
+	Required    bool              `json:"required,omitempty" yaml:"required,omitempty"`
+	Type        string            `json:"type,omitempty" yaml:"type,omitempty"`
+	Format      string            `json:"format,omitempty" yaml:"format,omitempty"`
+	Items       *objschema.Schema `json:"items,omitempty" yaml:"items,omitempty"`
+	Default     interface{}       `json:"default,omitempty" yaml:"default,omitempty"`
+	Enum        []interface{}     `json:"enum,omitempty" yaml:"enum,omitempty"`
+	Schema      *objschema.Schema `json:"schema,omitempty" yaml:"schema,omitempty"`
+}
+
+type response struct {
+	Description string                 `json:"description" yaml:"description"`
+	Schema      *objschema.Schema      `json:"schema,omitempty" yaml:"schema,omitempty"`
+	Examples    map[string]interface{} `json:"examples,omitempty" yaml:"examples,omitempty"`
+}
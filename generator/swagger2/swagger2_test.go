@@ -0,0 +1,126 @@
+package swagger2
+
+import (
+	"bytes"
+	"flag"
+	"github.com/thewinds/mkdoc"
+	"github.com/thewinds/mkdoc/schema"
+	"os"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func TestGenerator_Gen(t *testing.T) {
+	refs := make(map[mkdoc.LangObjectId]*mkdoc.Object)
+	add := func(obj *mkdoc.Object) *mkdoc.Object {
+		refs[mkdoc.LangObjectId{Lang: "go", Id: obj.ID}] = obj
+		return obj
+	}
+	minAge := 0.0
+	add(&mkdoc.Object{
+		ID:     "[]string",
+		Type:   &mkdoc.ObjectType{Name: "object", Ref: "string", IsRepeated: true},
+		Loaded: true,
+	})
+	add(&mkdoc.Object{ID: "string", Type: &mkdoc.ObjectType{Name: "string"}, Loaded: true})
+	add(&mkdoc.Object{
+		ID:   "github.com/a/model.Profile",
+		Type: &mkdoc.ObjectType{Name: "object"},
+		Fields: []*mkdoc.ObjectField{
+			{Name: "Bio", Type: &mkdoc.ObjectType{Name: "string"}},
+		},
+		Loaded: true,
+	})
+	user := add(&mkdoc.Object{
+		ID:   "github.com/a/model.User",
+		Type: &mkdoc.ObjectType{Name: "object"},
+		Fields: []*mkdoc.ObjectField{
+			{Name: "ID", Desc: "用户 ID", Type: &mkdoc.ObjectType{Name: "int"}},
+			{Name: "Name", Desc: "用户名", Type: &mkdoc.ObjectType{Name: "string"}},
+		},
+		Loaded: true,
+	})
+	// the nested object of form is sent as string
+	form := add(&mkdoc.Object{
+		ID:   "@obj_in_#1",
+		Type: &mkdoc.ObjectType{Name: "object"},
+		Fields: []*mkdoc.ObjectField{
+			{Name: "name", Desc: "用户名", Type: &mkdoc.ObjectType{Name: "string"}, Extensions: []mkdoc.Extension{
+				&mkdoc.ExtensionConstraints{Required: true},
+			}},
+			{Name: "age", Type: &mkdoc.ObjectType{Name: "int"}, Extensions: []mkdoc.Extension{
+				&mkdoc.ExtensionRules{Minimum: &minAge, ExclusiveMinimum: true},
+			}},
+			{Name: "tags", Type: &mkdoc.ObjectType{Name: "object", Ref: "[]string"}},
+			{Name: "profile", Type: &mkdoc.ObjectType{Name: "object", Ref: "github.com/a/model.Profile"}},
+		},
+		Loaded: true,
+	})
+	create := &mkdoc.API{
+		API: schema.API{
+			Name:     "创建用户",
+			Method:   "POST",
+			Path:     "/user",
+			Tags:     []string{"user"},
+			Language: "go",
+		},
+		InArgument:  form,
+		InLanguage:  "go",
+		OutLanguage: "go",
+		// the in mime type is the mime type of config
+		Mime: &mkdoc.MimeType{Out: "json"},
+		Responses: []*mkdoc.Response{
+			{Status: "201", Mime: "json", Argument: user, Language: "go"},
+			// the ranged responses share the default response
+			{Status: "4xx", Desc: "client error"},
+			{Status: "5xx", Desc: "server error"},
+		},
+	}
+	get := &mkdoc.API{
+		API: schema.API{
+			Name:       "获取用户",
+			Method:     "GET",
+			Path:       "/user/:uid",
+			Tags:       []string{"user"},
+			PathParams: schema.Params{{Name: "uid", Type: "int", Desc: "用户 ID", Required: true}},
+			Language:   "go",
+		},
+		OutArgument: user,
+		InLanguage:  "go",
+		OutLanguage: "go",
+		Mime:        &mkdoc.MimeType{In: "json", Out: "json"},
+	}
+	ctx := &mkdoc.DocGenContext{
+		APIs: []*mkdoc.API{create, get},
+		Config: mkdoc.Config{
+			Name:       "example",
+			APIBaseURL: "https://api.example.com/v1",
+			Mime:       &mkdoc.MimeType{In: "form", Out: "json"},
+			Injects: []*mkdoc.Inject{
+				{Name: "token", Desc: "jwt token", Scope: "header"},
+				{Name: "device", Desc: "device id", Default: "web", Scope: "form_param"},
+			},
+		},
+		RefObj: refs,
+		Args:   map[string]string{"format": "json"},
+	}
+	output, err := new(Generator).Gen(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := output.Files[0].Data
+	golden := "testdata/swagger.golden.json"
+	if *update {
+		if err := os.WriteFile(golden, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output of %s got:\n%s", golden, got)
+	}
+}
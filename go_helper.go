@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"go/ast"
+	"go/build"
 	"go/parser"
 	"go/token"
 	"os"
@@ -127,6 +128,11 @@ func GetFileImportsAtFile(fileName string, mod *GoModuleInfo) (map[string]string
 
 // getFilePkgPath get go package name from absolute file name
 func getFilePkgPath(fileName string, mod *GoModuleInfo) string {
+	// the standard library
+	goRootSrc := filepath.Join(build.Default.GOROOT, "src") + string(os.PathSeparator)
+	if strings.HasPrefix(fileName, goRootSrc) {
+		return filepath.Dir(fileName[len(goRootSrc):])
+	}
 	if mod == nil {
 		goSrcPaths := GetGOSrcPaths()
 		for _, v := range goSrcPaths {
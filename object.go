@@ -1,8 +1,12 @@
 package mkdoc
 
 import (
+	"crypto/sha1"
 	"fmt"
 	"math/rand"
+	"regexp"
+	"strconv"
+	"sync/atomic"
 )
 
 // Object info
@@ -19,10 +23,14 @@ type LangObjectId struct {
 	Id   string
 }
 
-// Clone object with a random id
+// cloneSeq is the number of cloned objects
+var cloneSeq uint64
+
+// Clone object with an id derived from the id of origin object,
+// the ids of clones are distinct by the number of clone
 func (obj *Object) Clone() *Object {
 	newObj := new(Object)
-	newObj.ID = RandObjectID("clone")
+	newObj.ID = ObjectID("clone", obj.ID, strconv.FormatUint(atomic.AddUint64(&cloneSeq, 1), 10))
 	t := *obj.Type
 	newObj.Type = &t
 	for _, field := range obj.Fields {
@@ -32,18 +40,47 @@ func (obj *Object) Clone() *Object {
 			Desc: field.Desc,
 			Type: &ft,
 		}
-		// TODO copy extensions
-		//field.Extensions
+		for _, ext := range field.Extensions {
+			newField.Extensions = append(newField.Extensions, CloneExtension(ext))
+		}
 		newObj.Fields = append(newObj.Fields, newField)
 	}
+	for _, ext := range obj.Extensions {
+		newObj.Extensions = append(newObj.Extensions, CloneExtension(ext))
+	}
 	newObj.Loaded = obj.Loaded
 	return newObj
 }
 
+var rePkgPath = regexp.MustCompile(`[^\[\],]*/`)
+
+// ShortObjectID remove the package path of object id but the last element,
+//
+//	github.com/x/api.Resp[github.com/x/model.User] => api.Resp[model.User]
+func ShortObjectID(id string) string {
+	return rePkgPath.ReplaceAllString(id, "")
+}
+
+// RandObjectID returns a random id of anonymous object,
+//
+// Deprecated: the random ids make the docs unstable between runs,use ObjectID instead
 func RandObjectID(s string) string {
 	return fmt.Sprintf("@obj_%s_#%d", s, rand.Int63())
 }
 
+// ObjectID returns the id of anonymous object derived from the kind and content,
+// the same content always gets the same id so the docs are stable between runs,
+//
+//	ObjectID("in", "POST", "/user", "name string") => @obj_in_#3f2a9c0d1e5b7a68
+func ObjectID(kind string, content ...string) string {
+	h := sha1.New()
+	for _, s := range content {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	return fmt.Sprintf("@obj_%s_#%x", kind, h.Sum(nil)[:8])
+}
+
 // CreateRootObject
 // create root object by package and type
 // returns the created obj(return Object[0]) an refs object
@@ -79,14 +116,16 @@ func CreateRootObject(pkgTyp string, loadFn func(id string) *Object) ([]*Object,
 	return CreateArrayObject(leaf, arrDep), nil
 }
 
-// Create a n-dimensional(dep) array object
+// Create a n-dimensional(dep) array object,
+// the id of array object is the id of element with a [] prefix,eg. [][]model.User,
+// so the identical arrays are the same object
 func CreateArrayObject(leaf *Object, dep int) []*Object {
 	var deps []*Object
 	root := leaf
 	deps = append(deps, root)
 	for k := 0; k < dep; k++ {
 		obj := &Object{
-			ID: RandObjectID("arr"),
+			ID: "[]" + root.ID,
 			Type: &ObjectType{
 				Name:       "object",
 				Ref:        root.ID,
@@ -100,6 +139,25 @@ func CreateArrayObject(leaf *Object, dep int) []*Object {
 	return deps
 }
 
+// Create a map object,the key of map is a builtin type
+// and the value is described by value type,
+// the id of map object is like map[string]model.User
+func CreateMapObject(key string, value *ObjectType) *Object {
+	elem := value.Ref
+	if elem == "" {
+		elem = value.Name
+	}
+	return &Object{
+		ID: "map[" + key + "]" + elem,
+		Type: &ObjectType{
+			Name:   value.Name,
+			Ref:    value.Ref,
+			MapKey: key,
+		},
+		Loaded: true,
+	}
+}
+
 // Create and register a n-dimensional(dep) array object by leaf object id
 func CreateArrayObjectByID(leafObjID string, dep int, loadFn func(id string) *Object) []*Object {
 	leaf := loadFn(leafObjID)
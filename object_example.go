@@ -0,0 +1,41 @@
+package mkdoc
+
+import (
+	"encoding/json"
+	"github.com/thewinds/mkdoc/schema"
+)
+
+// ExtensionExample keeps the example of field declared by the example tag
+// or the `@example` directive in doc comment,Value is the raw text of example,
+// it's a json literal or a plain string
+type ExtensionExample struct {
+	Value string `json:"value"`
+}
+
+func (e *ExtensionExample) Name() string {
+	return "example"
+}
+
+func (e *ExtensionExample) Parse(schema *schema.Extension) (Extension, error) {
+	if err := json.Unmarshal(schema.Data, e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *ExtensionExample) Schema() (*schema.Extension, error) {
+	return marshalExtension(e)
+}
+
+// NewExtensionExample returns the example declared by the example tag,
+// the example of doc comment is used if the tag is omitted,
+// returns nil if there is no example
+func NewExtensionExample(tag *ObjectFieldTag, doc string) *ExtensionExample {
+	if v := tag.GetValue("example"); v != "" {
+		return &ExtensionExample{Value: v}
+	}
+	if doc != "" {
+		return &ExtensionExample{Value: doc}
+	}
+	return nil
+}
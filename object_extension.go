@@ -3,11 +3,86 @@ package mkdoc
 import (
 	"encoding/json"
 	"github.com/thewinds/mkdoc/schema"
+	"log"
 )
 
+// Extension is the typed metadata of objects and fields,
+// Parse parse the extension from schema and Schema serialize it back
 type Extension interface {
 	Name() string
 	Parse(schema *schema.Extension) (Extension, error)
+	Schema() (*schema.Extension, error)
+}
+
+var extensions map[string]func() Extension
+
+func init() {
+	RegisterExtension("go_tag", func() Extension { return new(ExtensionGoTag) })
+	RegisterExtension("graphql", func() Extension { return new(ExtensionGraphQL) })
+	RegisterExtension("enum", func() Extension { return new(ExtensionEnum) })
+	RegisterExtension("format", func() Extension { return new(ExtensionFormat) })
+	RegisterExtension("opaque", func() Extension { return new(ExtensionOpaque) })
+	RegisterExtension("constraints", func() Extension { return new(ExtensionConstraints) })
+	RegisterExtension("rules", func() Extension { return new(ExtensionRules) })
+	RegisterExtension("example", func() Extension { return new(ExtensionExample) })
+}
+
+// RegisterExtension to global extensions,
+// the extensions named name in schema are parsed by the extension created by factory
+func RegisterExtension(name string, factory func() Extension) {
+	if extensions == nil {
+		extensions = make(map[string]func() Extension)
+	}
+	if extensions[name] != nil {
+		log.Fatalf("duplicate register extension : %s", name)
+	}
+	extensions[name] = factory
+}
+
+// ParseExtension parse the schema extension by the registered extension,
+// the unregistered extensions are parsed as ExtensionUnknown
+func ParseExtension(ext *schema.Extension) (Extension, error) {
+	factory := extensions[ext.Name]
+	if factory == nil {
+		return new(ExtensionUnknown).Parse(ext)
+	}
+	return factory().Parse(ext)
+}
+
+// CloneExtension deep copy the extension by serializing and parsing it,
+// the extension is shared if it can't be serialized
+func CloneExtension(ext Extension) Extension {
+	s, err := ext.Schema()
+	if err != nil {
+		return ext
+	}
+	cloned, err := ParseExtension(s)
+	if err != nil {
+		return ext
+	}
+	return cloned
+}
+
+// FindExtension returns the first extension of type T,the zero value of T if not found
+//
+//	format := FindExtension[*ExtensionFormat](obj.Extensions)
+func FindExtension[T Extension](exts []Extension) T {
+	for _, ext := range exts {
+		if e, ok := ext.(T); ok {
+			return e
+		}
+	}
+	var zero T
+	return zero
+}
+
+// marshalExtension serialize the extension as json data
+func marshalExtension(ext Extension) (*schema.Extension, error) {
+	data, err := json.Marshal(ext)
+	if err != nil {
+		return nil, err
+	}
+	return &schema.Extension{Name: ext.Name(), Data: data}, nil
 }
 
 type ExtensionGoTag struct {
@@ -31,6 +106,14 @@ func (e *ExtensionGoTag) Parse(schema *schema.Extension) (Extension, error) {
 	return e, nil
 }
 
+func (e *ExtensionGoTag) Schema() (*schema.Extension, error) {
+	data, err := json.Marshal(e.Tag.String())
+	if err != nil {
+		return nil, err
+	}
+	return &schema.Extension{Name: e.Name(), Data: data}, nil
+}
+
 type ExtensionUnknown struct {
 	OriginExtensionName string
 	OriginData          json.RawMessage
@@ -45,3 +128,147 @@ func (e *ExtensionUnknown) Parse(schema *schema.Extension) (Extension, error) {
 	e.OriginExtensionName = schema.Name
 	return e,nil
 }
+
+// Schema returns the origin extension
+func (e *ExtensionUnknown) Schema() (*schema.Extension, error) {
+	data := make(json.RawMessage, len(e.OriginData))
+	copy(data, e.OriginData)
+	return &schema.Extension{Name: e.OriginExtensionName, Data: data}, nil
+}
+
+// ExtensionGraphQL keeps the GraphQL SDL info of objects and fields
+//
+// Type is the SDL type of field like `[User!]!`,
+// Kind is one of type,input,interface,enum,union,scalar,
+// Values are the enum values or the union member types,
+// Default is the default value of argument
+type ExtensionGraphQL struct {
+	Type    string   `json:"type,omitempty"`
+	Kind    string   `json:"kind,omitempty"`
+	Values  []string `json:"values,omitempty"`
+	Default string   `json:"default,omitempty"`
+}
+
+func (e *ExtensionGraphQL) Name() string {
+	return "graphql"
+}
+
+func (e *ExtensionGraphQL) Parse(schema *schema.Extension) (Extension, error) {
+	if err := json.Unmarshal(schema.Data, e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *ExtensionGraphQL) Schema() (*schema.Extension, error) {
+	return marshalExtension(e)
+}
+
+// ExtensionEnum keeps the allowed values of an object of builtin type,
+// eg. the constants of `type Status int`
+type ExtensionEnum struct {
+	Values []*EnumValue `json:"values"`
+}
+
+// EnumValue is an allowed value,Value is the plain value without quotes
+type EnumValue struct {
+	Name  string `json:"name,omitempty"`
+	Value string `json:"value"`
+	Desc  string `json:"desc,omitempty"`
+}
+
+func (e *ExtensionEnum) Name() string {
+	return "enum"
+}
+
+func (e *ExtensionEnum) Parse(schema *schema.Extension) (Extension, error) {
+	if err := json.Unmarshal(schema.Data, e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *ExtensionEnum) Schema() (*schema.Extension, error) {
+	return marshalExtension(e)
+}
+
+// ExtensionFormat keeps the format of an object of builtin type,
+// eg. the `date-time` of time.Time which is mapped to string
+type ExtensionFormat struct {
+	Format string `json:"format"`
+}
+
+func (e *ExtensionFormat) Name() string {
+	return "format"
+}
+
+func (e *ExtensionFormat) Parse(schema *schema.Extension) (Extension, error) {
+	if err := json.Unmarshal(schema.Data, e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *ExtensionFormat) Schema() (*schema.Extension, error) {
+	return marshalExtension(e)
+}
+
+// ExtensionOpaque marks the object whose json shape is unknown,
+// eg. the types implement json.Marshaler without declared shape,
+// Reason tells why the object is opaque
+type ExtensionOpaque struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+func (e *ExtensionOpaque) Name() string {
+	return "opaque"
+}
+
+func (e *ExtensionOpaque) Parse(schema *schema.Extension) (Extension, error) {
+	if err := json.Unmarshal(schema.Data, e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *ExtensionOpaque) Schema() (*schema.Extension, error) {
+	return marshalExtension(e)
+}
+
+// ExtensionConstraints keeps the constraints of field,
+// OmitEmpty and String are the options of json tag,
+// Required is declared by `binding:"required"` or `validate:"required"`
+type ExtensionConstraints struct {
+	Required  bool `json:"required,omitempty"`
+	OmitEmpty bool `json:"omitempty,omitempty"`
+	String    bool `json:"string,omitempty"`
+}
+
+// NewExtensionConstraints parse the constraints from the go tag of field,
+// returns nil if there is no constraint
+func NewExtensionConstraints(tag *ObjectFieldTag) *ExtensionConstraints {
+	e := &ExtensionConstraints{
+		Required:  tag.HasValue("binding", ",", "required") || tag.HasValue("validate", ",", "required"),
+		OmitEmpty: tag.HasOption("json", ",", "omitempty"),
+		String:    tag.HasOption("json", ",", "string"),
+	}
+	if !e.Required && !e.OmitEmpty && !e.String {
+		return nil
+	}
+	return e
+}
+
+func (e *ExtensionConstraints) Name() string {
+	return "constraints"
+}
+
+func (e *ExtensionConstraints) Parse(schema *schema.Extension) (Extension, error) {
+	if err := json.Unmarshal(schema.Data, e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *ExtensionConstraints) Schema() (*schema.Extension, error) {
+	return marshalExtension(e)
+}
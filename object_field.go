@@ -2,6 +2,7 @@ package mkdoc
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 )
 
@@ -18,6 +19,8 @@ type ObjectFieldTag struct {
 	m   map[string]string
 }
 
+// parse the tag by the convention of reflect.StructTag,
+// the values may contain spaces,eg. `validate:"oneof=a b c"`
 func (o *ObjectFieldTag) parse() error {
 	raw := o.raw
 	if len(raw) == 0 {
@@ -26,22 +29,44 @@ func (o *ObjectFieldTag) parse() error {
 	if raw[0] == '`' && raw[len(raw)-1] == '`' {
 		raw = raw[1 : len(raw)-1]
 	}
-	tags := strings.Fields(raw)
 	o.m = make(map[string]string)
-	for _, t := range tags {
-		r := strings.Split(t, ":")
-		if len(r) != 2 {
+	for {
+		raw = strings.TrimLeft(raw, " \t\r\n")
+		if raw == "" {
+			return nil
+		}
+		i := strings.Index(raw, ":")
+		if i <= 0 || i+1 >= len(raw) || raw[i+1] != '"' || strings.ContainsAny(raw[:i], " \t\"") {
+			return fmt.Errorf("tag parse error:%v", o.raw)
+		}
+		id := raw[:i]
+		raw = raw[i+1:]
+		// scan to the closing quote
+		j := 1
+		for j < len(raw) && raw[j] != '"' {
+			if raw[j] == '\\' {
+				j++
+			}
+			j++
+		}
+		if j >= len(raw) {
 			return fmt.Errorf("tag parse error:%v", o.raw)
 		}
-		id, body := r[0], r[1]
-		id = strings.TrimSpace(id)
-		body = strings.TrimSpace(body)
-		if len(body) < 2 || !(body[0] == '"' && body[len(body)-1] == '"') {
+		body, err := strconv.Unquote(raw[:j+1])
+		if err != nil {
 			return fmt.Errorf("tag parse error:%v", o.raw)
 		}
-		o.m[id] = body[1 : len(body)-1]
+		o.m[id] = body
+		raw = raw[j+1:]
 	}
-	return nil
+}
+
+// String returns the raw tag
+func (o *ObjectFieldTag) String() string {
+	if o == nil {
+		return ""
+	}
+	return o.raw
 }
 
 func (o *ObjectFieldTag) GetValue(tagName string) string {
@@ -63,6 +88,34 @@ func (o *ObjectFieldTag) GetFirstValue(tagName string, sep string) string {
 	return v
 }
 
+// HasOption report whether the option is set in the tag,
+// options are the values after the first sep,eg. omitempty of `json:"name,omitempty"`
+func (o *ObjectFieldTag) HasOption(tagName string, sep string, option string) bool {
+	if o == nil {
+		return false
+	}
+	values := strings.Split(o.m[tagName], sep)
+	for _, v := range values[1:] {
+		if strings.TrimSpace(v) == option {
+			return true
+		}
+	}
+	return false
+}
+
+// HasValue report whether the value is one of the values in the tag
+func (o *ObjectFieldTag) HasValue(tagName string, sep string, value string) bool {
+	if o == nil {
+		return false
+	}
+	for _, v := range strings.Split(o.m[tagName], sep) {
+		if strings.TrimSpace(v) == value {
+			return true
+		}
+	}
+	return false
+}
+
 func NewObjectFieldTag(raw string) (*ObjectFieldTag, error) {
 	tag := &ObjectFieldTag{raw: raw}
 	err := tag.parse()
@@ -0,0 +1,181 @@
+package mkdoc
+
+import (
+	"encoding/json"
+	"github.com/thewinds/mkdoc/schema"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ExtensionRules keeps the validation rules of field declared by the validate or binding tag
+// of go-playground/validator,
+// MinLength and MaxLength limit the length of string,array and map,
+// Minimum and Maximum limit the value of number,they are exclusive if ExclusiveXXX is set,
+// Enum is the allowed values of oneof and Format is the format of string,eg. email,uuid
+type ExtensionRules struct {
+	MinLength        *int64   `json:"min_length,omitempty"`
+	MaxLength        *int64   `json:"max_length,omitempty"`
+	Minimum          *float64 `json:"minimum,omitempty"`
+	Maximum          *float64 `json:"maximum,omitempty"`
+	ExclusiveMinimum bool     `json:"exclusive_minimum,omitempty"`
+	ExclusiveMaximum bool     `json:"exclusive_maximum,omitempty"`
+	Enum             []string `json:"enum,omitempty"`
+	Format           string   `json:"format,omitempty"`
+}
+
+func (e *ExtensionRules) Name() string {
+	return "rules"
+}
+
+func (e *ExtensionRules) Parse(schema *schema.Extension) (Extension, error) {
+	if err := json.Unmarshal(schema.Data, e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *ExtensionRules) Schema() (*schema.Extension, error) {
+	return marshalExtension(e)
+}
+
+// ruleFormats are the validator tags describe the format of string
+var ruleFormats = map[string]string{
+	"email":    "email",
+	"url":      "uri",
+	"uri":      "uri",
+	"http_url": "uri",
+	"uuid":     "uuid",
+	"uuid3":    "uuid",
+	"uuid4":    "uuid",
+	"uuid5":    "uuid",
+	"ipv4":     "ipv4",
+	"ip4_addr": "ipv4",
+	"ipv6":     "ipv6",
+	"ip6_addr": "ipv6",
+	"hostname": "hostname",
+	"fqdn":     "hostname",
+	"datetime": "date-time",
+	"base64":   "byte",
+	"e164":     "phone",
+}
+
+// NewExtensionRules parse the rules of the validate and binding tag,
+// numeric tells whether the bounds limit the value or the length of field,
+// the rules after dive are ignored since they are the rules of elements,
+// returns nil if there is no rule
+func NewExtensionRules(tag *ObjectFieldTag, numeric bool) *ExtensionRules {
+	e := new(ExtensionRules)
+	found := false
+	for _, name := range []string{"binding", "validate"} {
+		for _, rule := range strings.Split(tag.GetValue(name), ",") {
+			rule = strings.TrimSpace(rule)
+			if rule == "dive" {
+				break
+			}
+			// the or rules can't be described
+			if rule == "" || strings.Contains(rule, "|") {
+				continue
+			}
+			if e.parseRule(rule, numeric) {
+				found = true
+			}
+		}
+	}
+	if !found {
+		return nil
+	}
+	return e
+}
+
+func (e *ExtensionRules) parseRule(rule string, numeric bool) bool {
+	key, param := rule, ""
+	if i := strings.Index(rule, "="); i != -1 {
+		key, param = rule[:i], rule[i+1:]
+	}
+	if format, ok := ruleFormats[key]; ok {
+		e.Format = format
+		// the layout of datetime may be a date only,eg. datetime=2006-01-02
+		if key == "datetime" && param != "" && !strings.Contains(param, "15") && !strings.Contains(param, "03") {
+			e.Format = "date"
+		}
+		return true
+	}
+	switch key {
+	case "oneof":
+		e.Enum = oneofValues(param)
+		return len(e.Enum) > 0
+	case "eq":
+		if !numeric {
+			e.Enum = []string{param}
+			return true
+		}
+	}
+	v, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return false
+	}
+	switch key {
+	case "min", "gte":
+		e.setMin(v, false, numeric)
+	case "max", "lte":
+		e.setMax(v, false, numeric)
+	case "gt":
+		e.setMin(v, true, numeric)
+	case "lt":
+		e.setMax(v, true, numeric)
+	case "len", "eq":
+		e.setMin(v, false, numeric)
+		e.setMax(v, false, numeric)
+	default:
+		return false
+	}
+	return true
+}
+
+func (e *ExtensionRules) setMin(v float64, exclusive bool, numeric bool) {
+	if numeric {
+		e.Minimum, e.ExclusiveMinimum = &v, exclusive
+		return
+	}
+	n := int64(math.Ceil(v))
+	if exclusive {
+		n = int64(math.Floor(v)) + 1
+	}
+	e.MinLength = &n
+}
+
+func (e *ExtensionRules) setMax(v float64, exclusive bool, numeric bool) {
+	if numeric {
+		e.Maximum, e.ExclusiveMaximum = &v, exclusive
+		return
+	}
+	n := int64(math.Floor(v))
+	if exclusive {
+		n = int64(math.Ceil(v)) - 1
+	}
+	e.MaxLength = &n
+}
+
+// oneofValues split the values of oneof,the values with spaces are quoted by '
+//
+//	red green 'light blue' => [red, green, light blue]
+func oneofValues(param string) []string {
+	var values []string
+	for param = strings.TrimSpace(param); param != ""; param = strings.TrimSpace(param) {
+		if param[0] == '\'' {
+			if end := strings.Index(param[1:], "'"); end != -1 {
+				values = append(values, param[1:end+1])
+				param = param[end+2:]
+				continue
+			}
+		}
+		i := strings.IndexAny(param, " \t")
+		if i == -1 {
+			i = len(param)
+		}
+		values = append(values, param[:i])
+		param = param[i:]
+	}
+	return values
+}
@@ -0,0 +1,77 @@
+package mkdoc
+
+import (
+	"encoding/json"
+	"github.com/thewinds/mkdoc/schema"
+	"testing"
+)
+
+func TestObject_Clone(t *testing.T) {
+	tag, err := ParseExtension(&schema.Extension{Name: "go_tag", Data: json.RawMessage(`"json:\"name,omitempty\" validate:\"oneof=a b\""`)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj := &Object{
+		ID:   "a.User",
+		Type: &ObjectType{Name: "object"},
+		Fields: []*ObjectField{
+			{Name: "Name", Type: &ObjectType{Name: "string"}, Extensions: []Extension{
+				tag,
+				&ExtensionConstraints{OmitEmpty: true},
+				&ExtensionUnknown{OriginExtensionName: "x_custom", OriginData: json.RawMessage(`{"a":1}`)},
+			}},
+		},
+		Extensions: []Extension{&ExtensionEnum{Values: []*EnumValue{{Value: "1"}}}},
+	}
+	cloned := obj.Clone()
+	if cloned.ID == obj.ID || cloned.ID == obj.Clone().ID {
+		t.Errorf("id of clone got %s, want a distinct id", cloned.ID)
+	}
+
+	exts := cloned.Fields[0].Extensions
+	if len(exts) != 3 {
+		t.Fatalf("extensions of field got %d, want 3", len(exts))
+	}
+	if got := exts[0].(*ExtensionGoTag).Tag.GetValue("validate"); got != "oneof=a b" {
+		t.Errorf("go tag got %q", got)
+	}
+	if c := exts[1].(*ExtensionConstraints); c == obj.Fields[0].Extensions[1] || !c.OmitEmpty {
+		t.Errorf("constraints got %+v, want a copy", c)
+	}
+	if u := exts[2].(*ExtensionUnknown); u.OriginExtensionName != "x_custom" || string(u.OriginData) != `{"a":1}` {
+		t.Errorf("unknown extension got %s %s", u.OriginExtensionName, u.OriginData)
+	}
+	enum := cloned.Extensions[0].(*ExtensionEnum)
+	enum.Values[0].Value = "2"
+	if obj.Extensions[0].(*ExtensionEnum).Values[0].Value != "1" {
+		t.Error("enum extension is not deep copied")
+	}
+}
+
+func TestFindExtension(t *testing.T) {
+	format := &ExtensionFormat{Format: "date-time"}
+	exts := []Extension{&ExtensionOpaque{}, format, &ExtensionFormat{Format: "date"}}
+	if got := FindExtension[*ExtensionFormat](exts); got != format {
+		t.Errorf("FindExtension got %+v, want %+v", got, format)
+	}
+	if got := FindExtension[*ExtensionEnum](exts); got != nil {
+		t.Errorf("FindExtension got %+v, want nil", got)
+	}
+}
+
+func TestCreateArrayObject(t *testing.T) {
+	leaf := &Object{ID: "a.User", Type: &ObjectType{Name: "object"}}
+	objs := CreateArrayObject(leaf, 2)
+	if got := objs[2].ID; got != "[][]a.User" {
+		t.Errorf("id of array got %s, want [][]a.User", got)
+	}
+	if got := objs[2].Type.Ref; got != "[]a.User" {
+		t.Errorf("ref of array got %s, want []a.User", got)
+	}
+	if again := CreateArrayObject(leaf, 2); again[2].ID != objs[2].ID {
+		t.Errorf("id of identical array got %s and %s", objs[2].ID, again[2].ID)
+	}
+	if got := CreateMapObject("string", &ObjectType{Name: "object", Ref: objs[1].ID}).ID; got != "map[string][]a.User" {
+		t.Errorf("id of map got %s, want map[string][]a.User", got)
+	}
+}
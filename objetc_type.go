@@ -15,8 +15,12 @@ package mkdoc
 // Ref describe which object to reference
 //
 // IsRepeated will be true if that is a array/slice type
+//
+// MapKey is the key type if that is a map type,
+// the value type is described by Name and Ref
 type ObjectType struct {
 	Name       string
 	Ref        string
 	IsRepeated bool
+	MapKey     string
 }
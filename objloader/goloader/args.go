@@ -2,4 +2,7 @@ package goloader
 
 const (
 	EnableGoModule = "enable_go_mod"
+	// LoaderBackend is the way to resolve types,
+	// "ast"(default) or "packages" which loads types by the type checker
+	LoaderBackend = "go_loader"
 )
@@ -0,0 +1,219 @@
+package goloader
+
+import (
+	"errors"
+	"fmt"
+	"github.com/thewinds/mkdoc"
+	"go/ast"
+	"strings"
+)
+
+// promotedField is a field of struct or embedded structs
+type promotedField struct {
+	field    *GoStructField
+	jsonName string
+	tagged   bool
+	depth    int
+}
+
+// flattenFields promote the fields of embedded structs into the struct
+// by the rules of encoding/json:
+//   - an embedded struct without json name is flattened,or it's a normal field
+//   - the shallower field shadows the deeper fields with the same json name
+//   - the tagged field wins at the same depth,the fields are dropped if it's still ambiguous
+func (g *GoLoader) flattenFields(info *GoStructInfo) ([]*GoStructField, error) {
+	var fields []*promotedField
+	visiting := make(map[string]bool)
+	if err := g.collectFields(info, 0, visiting, &fields); err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string][]*promotedField)
+	for _, f := range fields {
+		byName[f.jsonName] = append(byName[f.jsonName], f)
+	}
+	dominant := make(map[string]*promotedField)
+	for name, candidates := range byName {
+		dominant[name] = dominantField(candidates)
+	}
+	var r []*GoStructField
+	for _, f := range fields {
+		if dominant[f.jsonName] == f {
+			r = append(r, f.field)
+		}
+	}
+	return r, nil
+}
+
+// dominantField returns the field which shadows the others,nil if it's ambiguous
+func dominantField(fields []*promotedField) *promotedField {
+	depth := fields[0].depth
+	for _, f := range fields {
+		if f.depth < depth {
+			depth = f.depth
+		}
+	}
+	var dominant, tagged []*promotedField
+	for _, f := range fields {
+		if f.depth != depth {
+			continue
+		}
+		dominant = append(dominant, f)
+		if f.tagged {
+			tagged = append(tagged, f)
+		}
+	}
+	if len(dominant) == 1 {
+		return dominant[0]
+	}
+	if len(tagged) == 1 {
+		return tagged[0]
+	}
+	return nil
+}
+
+func (g *GoLoader) collectFields(info *GoStructInfo, depth int, visiting map[string]bool, fields *[]*promotedField) error {
+	for _, field := range info.Fields {
+		tag, err := mkdoc.NewObjectFieldTag(field.Tag)
+		if err != nil {
+			return err
+		}
+		jsonName := tag.GetFirstValue("json", ",")
+		if jsonName == "-" {
+			continue
+		}
+		// unexported fields are ignored by encoding/json,eg. the fields of sync.Mutex
+		if !field.Embedded && !ast.IsExported(field.Name) {
+			continue
+		}
+		if field.Embedded && jsonName == "" {
+			embedded, err := g.embeddedStruct(field.GoType)
+			if err == errEmbeddedSkipped {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if embedded != nil {
+				id := refID(field.GoType)
+				// circular embedding
+				if visiting[id] {
+					continue
+				}
+				visiting[id] = true
+				err := g.collectFields(embedded, depth+1, visiting, fields)
+				delete(visiting, id)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			// unexported embedded non struct types are ignored
+			if !ast.IsExported(field.Name) {
+				continue
+			}
+		}
+		f := &promotedField{field: field, jsonName: jsonName, tagged: jsonName != "", depth: depth}
+		if f.jsonName == "" {
+			f.jsonName = field.Name
+		}
+		*fields = append(*fields, f)
+	}
+	return nil
+}
+
+// encoding is the json encoding of a type other than the fields,
+// mapping is the mapped or declared type and marshaler is the marshal method
+type encoding struct {
+	mapping   string
+	marshaler string
+}
+
+// promotedEncoding returns the encoding promoted from the embedded fields like the methods,
+// eg. a struct embeds time.Time is encoded as time.
+// The encoding of the shallowest embedded type is promoted,none is promoted if it's ambiguous.
+func (g *GoLoader) promotedEncoding(info *GoStructInfo) (mapping string, marshaler string, err error) {
+	visited := make(map[string]bool)
+	for level := []*GoStructInfo{info}; len(level) > 0; {
+		var found []encoding
+		var next []*GoStructInfo
+		for _, st := range level {
+			for _, field := range st.Fields {
+				goType := field.GoType
+				if !field.Embedded || goType.NotSupport || goType.IsBuiltin || goType.IsArray || goType.IsMap {
+					continue
+				}
+				id := refID(goType)
+				if visited[id] {
+					continue
+				}
+				visited[id] = true
+				if mapping, ok := g.typeMapping[goType.ImportPkgName+"."+goType.TypeName]; ok {
+					found = append(found, encoding{mapping: mapping})
+					continue
+				}
+				query, err := newPkgType(id)
+				if err != nil {
+					return "", "", err
+				}
+				embedded, err := g.getStructInfo(query)
+				if err != nil {
+					// the type not found is skipped by flattenFields
+					continue
+				}
+				switch {
+				case embedded.Directive != "":
+					found = append(found, encoding{mapping: embedded.Directive})
+				case embedded.Marshaler != "":
+					found = append(found, encoding{marshaler: embedded.Marshaler})
+				case embedded.IsStruct:
+					next = append(next, embedded)
+				}
+			}
+		}
+		if len(found) == 1 {
+			return found[0].mapping, found[0].marshaler, nil
+		}
+		if len(found) > 1 {
+			return "", "", nil
+		}
+		level = next
+	}
+	return "", "", nil
+}
+
+// errEmbeddedSkipped is returned if the embedded type is not found
+var errEmbeddedSkipped = errors.New("embedded type skipped")
+
+// embeddedStruct returns the struct info of embedded type,nil if it's not a struct
+func (g *GoLoader) embeddedStruct(goType *GoType) (*GoStructInfo, error) {
+	if goType.NotSupport || goType.IsBuiltin || goType.IsArray || goType.IsMap {
+		return nil, nil
+	}
+	// the mapped types are marshaled as builtin types
+	if _, ok := g.typeMapping[goType.ImportPkgName+"."+goType.TypeName]; ok {
+		return nil, nil
+	}
+	query, err := newPkgType(refID(goType))
+	if err != nil {
+		return nil, err
+	}
+	info, err := g.getStructInfo(query)
+	if err != nil {
+		// the type may be not found by the ast backend,eg. the types in the modules not vendored
+		fmt.Printf("WARNING: embedded type %s is skipped: %v \n", query, strings.TrimSpace(err.Error()))
+		return nil, errEmbeddedSkipped
+	}
+	// the types with declared type or marshal method are not flattened
+	if info.Directive != "" || info.Marshaler != "" {
+		return nil, nil
+	}
+	// alias or named type of struct
+	if !info.IsStruct && info.Underlying != nil {
+		return g.embeddedStruct(info.Underlying)
+	}
+	if !info.IsStruct {
+		return nil, nil
+	}
+	return info, nil
+}
@@ -0,0 +1,209 @@
+package goloader
+
+import (
+	"github.com/thewinds/mkdoc"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"golang.org/x/tools/go/packages"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// astEnums collect the exported constants of type typeName in the package,
+// the values are evaluated from the const declarations,
+// constants which can't be evaluated are ignored
+func astEnums(pkg *ast.Package, typeName string) []*mkdoc.EnumValue {
+	var fileNames []string
+	for fileName := range pkg.Files {
+		fileNames = append(fileNames, fileName)
+	}
+	sort.Strings(fileNames)
+
+	var enums []*mkdoc.EnumValue
+	consts := make(map[string]constant.Value)
+	for _, fileName := range fileNames {
+		for _, decl := range pkg.Files[fileName].Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.CONST {
+				continue
+			}
+			// the type and values are repeated if they are omitted
+			var typ ast.Expr
+			var values []ast.Expr
+			for iota, spec := range genDecl.Specs {
+				vs := spec.(*ast.ValueSpec)
+				if vs.Type != nil || len(vs.Values) > 0 {
+					typ, values = vs.Type, vs.Values
+				}
+				for i, name := range vs.Names {
+					if i >= len(values) || name.Name == "_" {
+						continue
+					}
+					v := evalConst(values[i], iota, consts)
+					if v == nil {
+						continue
+					}
+					consts[name.Name] = v
+					if ident, ok := typ.(*ast.Ident); !ok || ident.Name != typeName || !name.IsExported() {
+						continue
+					}
+					doc := vs.Doc
+					if doc == nil && !genDecl.Lparen.IsValid() {
+						doc = genDecl.Doc
+					}
+					if doc == nil {
+						doc = vs.Comment
+					}
+					enums = append(enums, &mkdoc.EnumValue{
+						Name:  name.Name,
+						Value: constValue(v),
+						Desc:  strings.TrimSpace(doc.Text()),
+					})
+				}
+			}
+		}
+	}
+	return enums
+}
+
+// evalConst evaluate the constant expression,nil if it's not support
+func evalConst(x ast.Expr, iota int, consts map[string]constant.Value) (v constant.Value) {
+	// operations of mismatched kinds panic
+	defer func() {
+		if recover() != nil {
+			v = nil
+		}
+	}()
+	switch t := x.(type) {
+	case *ast.BasicLit:
+		return constant.MakeFromLiteral(t.Value, t.Kind, 0)
+	case *ast.Ident:
+		switch t.Name {
+		case "iota":
+			return constant.MakeInt64(int64(iota))
+		case "true", "false":
+			return constant.MakeBool(t.Name == "true")
+		}
+		return consts[t.Name]
+	case *ast.ParenExpr:
+		return evalConst(t.X, iota, consts)
+	case *ast.CallExpr:
+		// type conversion,eg. Status(1)
+		if len(t.Args) == 1 {
+			return evalConst(t.Args[0], iota, consts)
+		}
+	case *ast.UnaryExpr:
+		if x := evalConst(t.X, iota, consts); x != nil {
+			return constant.UnaryOp(t.Op, x, 0)
+		}
+	case *ast.BinaryExpr:
+		x, y := evalConst(t.X, iota, consts), evalConst(t.Y, iota, consts)
+		if x == nil || y == nil {
+			return nil
+		}
+		switch t.Op {
+		case token.SHL, token.SHR:
+			s, ok := constant.Uint64Val(constant.ToInt(y))
+			if !ok {
+				return nil
+			}
+			return constant.Shift(x, t.Op, uint(s))
+		case token.QUO:
+			if x.Kind() == constant.Int && y.Kind() == constant.Int {
+				return constant.BinaryOp(x, token.QUO_ASSIGN, y)
+			}
+		}
+		return constant.BinaryOp(x, t.Op, y)
+	}
+	return nil
+}
+
+// typesEnums collect the exported constants of the named type in the package of it
+func typesEnums(pkg *packages.Package, named types.Type) []*mkdoc.EnumValue {
+	scope := pkg.Types.Scope()
+	var consts []*types.Const
+	for _, name := range scope.Names() {
+		c, ok := scope.Lookup(name).(*types.Const)
+		if ok && c.Exported() && types.Identical(c.Type(), named) {
+			consts = append(consts, c)
+		}
+	}
+	sort.Slice(consts, func(i, j int) bool {
+		pi, pj := pkg.Fset.Position(consts[i].Pos()), pkg.Fset.Position(consts[j].Pos())
+		if pi.Filename != pj.Filename {
+			return pi.Filename < pj.Filename
+		}
+		return pi.Offset < pj.Offset
+	})
+	var enums []*mkdoc.EnumValue
+	for _, c := range consts {
+		enums = append(enums, &mkdoc.EnumValue{
+			Name:  c.Name(),
+			Value: constValue(c.Val()),
+			Desc:  constDoc(pkg, c.Pos()),
+		})
+	}
+	return enums
+}
+
+// constDoc returns the doc comment of the constant declared at pos
+func constDoc(pkg *packages.Package, pos token.Pos) string {
+	var doc *ast.CommentGroup
+	for _, file := range pkg.Syntax {
+		if pos < file.Pos() || pos > file.End() {
+			continue
+		}
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.CONST || pos < genDecl.Pos() || pos > genDecl.End() {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				vs := spec.(*ast.ValueSpec)
+				for _, name := range vs.Names {
+					if name.Pos() != pos {
+						continue
+					}
+					doc = vs.Doc
+					if doc == nil && !genDecl.Lparen.IsValid() {
+						doc = genDecl.Doc
+					}
+					if doc == nil {
+						doc = vs.Comment
+					}
+				}
+			}
+		}
+	}
+	return strings.TrimSpace(doc.Text())
+}
+
+// hasEnums report whether there are exported constants of the named type
+func hasEnums(named *types.Named) bool {
+	obj := named.Obj()
+	if obj.Pkg() == nil {
+		return false
+	}
+	scope := obj.Pkg().Scope()
+	for _, name := range scope.Names() {
+		if c, ok := scope.Lookup(name).(*types.Const); ok && c.Exported() && types.Identical(c.Type(), named) {
+			return true
+		}
+	}
+	return false
+}
+
+// constValue returns the plain value of constant,strings are unquoted
+func constValue(v constant.Value) string {
+	switch v.Kind() {
+	case constant.String:
+		return constant.StringVal(v)
+	case constant.Float:
+		f, _ := constant.Float64Val(v)
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+	return v.ExactString()
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"github.com/thewinds/mkdoc"
 	"github.com/thewinds/mkdoc/schema"
+	"go/build"
 	"os"
 	"path/filepath"
 	"strings"
@@ -25,6 +26,8 @@ type GoLoader struct {
 	mod         *mkdoc.GoModuleInfo
 	pkg         string
 	enableGoMod bool
+	types       *typesLoader
+	typeMapping map[string]string
 }
 
 func (g *GoLoader) init(config *mkdoc.ObjectLoaderConfig) {
@@ -41,6 +44,15 @@ func (g *GoLoader) init(config *mkdoc.ObjectLoaderConfig) {
 		if config.Args[EnableGoModule] == "true" {
 			g.enableGoMod = true
 		}
+		g.typeMapping = typeMapping(config.TypeMapping)
+		if config.Args[LoaderBackend] == "packages" {
+			if g.enableGoMod {
+				g.types = newTypesLoader(g.pkg, "./...")
+			} else {
+				g.types = newTypesLoader("", g.pkg+"/...")
+			}
+			g.types.typeMapping = g.typeMapping
+		}
 		for _, object := range BuiltinObjects() {
 			g.cached[object.ID] = object
 		}
@@ -66,7 +78,7 @@ func (g *GoLoader) GetObjectId(ts mkdoc.TypeScope) (string, error) {
 	if !g.initialed {
 		return "", errors.New("loader not initialed")
 	}
-	if g.enableGoMod && (g.mod == nil) {
+	if g.enableGoMod && g.types == nil && g.mod == nil {
 		if err := g.initGoModule(g.pkg); err != nil {
 			return "", err
 		}
@@ -74,12 +86,23 @@ func (g *GoLoader) GetObjectId(ts mkdoc.TypeScope) (string, error) {
 	if strings.HasPrefix(ts.TypeName, "@") {
 		return ts.TypeName, nil
 	}
+	if g.tsId[ts] == "" && g.types != nil {
+		id, err := g.types.objectID(ts)
+		if err != nil {
+			return "", err
+		}
+		g.tsId[ts] = id
+	}
 	if g.tsId[ts] == "" {
 		imports, err := mkdoc.GetFileImportsAtFile(ts.FileName, g.mod)
 		if err != nil {
 			return "", err
 		}
-		g.tsId[ts] = replacePkg(ts.TypeName, imports)
+		id, err := replacePkg(ts.TypeName, imports)
+		if err != nil {
+			return "", fmt.Errorf("%s: %v", ts.FileName, err)
+		}
+		g.tsId[ts] = id
 	}
 	return g.tsId[ts], nil
 }
@@ -88,7 +111,7 @@ func (g *GoLoader) LoadAll(tss []mkdoc.TypeScope) ([]*mkdoc.Object, error) {
 	if !g.initialed {
 		return nil, errors.New("loader not initialed")
 	}
-	if g.enableGoMod && (g.mod == nil) {
+	if g.enableGoMod && g.types == nil && g.mod == nil {
 		if err := g.initGoModule(g.pkg); err != nil {
 			return nil, err
 		}
@@ -154,6 +177,9 @@ func (g *GoLoader) loadUnloads(unloads []*mkdoc.Object) error {
 		}
 		i++
 	}
+	for _, id := range queue {
+		g.addRules(g.loadCache(id))
+	}
 	return nil
 }
 
@@ -175,10 +201,31 @@ func (g *GoLoader) loadObj(query *PkgType, queue *[]string) error {
 	if query == nil {
 		return nil
 	}
+	// the mapped types are loaded as builtin types without fields
+	if mapping, ok := g.typeMapping[query.Package+"."+query.TypeName]; ok {
+		return g.loadMappedObj(query, mapping)
+	}
 	structInfo, err := g.getStructInfo(query)
 	if err != nil {
 		return err
 	}
+	// the declared type takes precedence over the fields
+	if structInfo.Directive != "" {
+		return g.loadMappedObj(query, structInfo.Directive)
+	}
+	// the encoding of embedded type is promoted unless the struct declares its marshaler
+	if structInfo.IsStruct && (structInfo.Marshaler == "" || structInfo.Promoted) {
+		mapping, marshaler, err := g.promotedEncoding(structInfo)
+		if err != nil {
+			return err
+		}
+		if mapping != "" {
+			return g.loadMappedObj(query, mapping)
+		}
+		if marshaler != "" {
+			structInfo.Marshaler = marshaler
+		}
+	}
 
 	rootObj := g.loadCache(query.fullPath)
 	rootObj.Type = &mkdoc.ObjectType{
@@ -187,7 +234,36 @@ func (g *GoLoader) loadObj(query *PkgType, queue *[]string) error {
 	}
 	rootObj.Fields = make([]*mkdoc.ObjectField, 0)
 
-	for _, field := range structInfo.Fields {
+	// the json encoding is changed by the marshal method,
+	// a TextMarshaler is encoded as string and a json.Marshaler is unknown
+	switch structInfo.Marshaler {
+	case "MarshalText":
+		rootObj.Type = &mkdoc.ObjectType{Name: "string"}
+		rootObj.Loaded = true
+		return nil
+	case "MarshalJSON":
+		rootObj.Type = &mkdoc.ObjectType{Name: "interface{}"}
+		rootObj.Extensions = append(rootObj.Extensions, &mkdoc.ExtensionOpaque{Reason: "implements json.Marshaler"})
+		rootObj.Loaded = true
+		return nil
+	}
+
+	if !structInfo.IsStruct {
+		if structInfo.Underlying != nil {
+			rootObj.Type = g.objectType(structInfo.Underlying, queue)
+		}
+		if len(structInfo.Enums) > 0 {
+			rootObj.Extensions = append(rootObj.Extensions, &mkdoc.ExtensionEnum{Values: structInfo.Enums})
+		}
+		rootObj.Loaded = true
+		return nil
+	}
+
+	fields, err := g.flattenFields(structInfo)
+	if err != nil {
+		return err
+	}
+	for _, field := range fields {
 		if field.GoType.NotSupport {
 			continue
 		}
@@ -198,6 +274,7 @@ func (g *GoLoader) loadObj(query *PkgType, queue *[]string) error {
 		} else {
 			comment = field.Comment
 		}
+		comment, example := exampleDirective(comment)
 		fieldTagExt, err := new(mkdoc.ExtensionGoTag).Parse(&schema.Extension{
 			Name: "go_tag",
 			Data: json.RawMessage(fmt.Sprintf("%q", field.Tag)),
@@ -208,78 +285,120 @@ func (g *GoLoader) loadObj(query *PkgType, queue *[]string) error {
 		objField := &mkdoc.ObjectField{
 			Name:       field.Name,
 			Desc:       comment,
-			Type:       &mkdoc.ObjectType{},
+			Type:       g.objectType(field.GoType, queue),
 			Extensions: []mkdoc.Extension{fieldTagExt},
 		}
-		goType := field.GoType
-
-		// builtin type
-		if goType.IsBuiltin && !goType.IsArray {
-			objField.Type.Name = goType.TypeName
-			rootObj.Fields = append(rootObj.Fields, objField)
-			continue
+		if constraints := mkdoc.NewExtensionConstraints(fieldTagExt.(*mkdoc.ExtensionGoTag).Tag); constraints != nil {
+			objField.Extensions = append(objField.Extensions, constraints)
 		}
+		if ex := mkdoc.NewExtensionExample(fieldTagExt.(*mkdoc.ExtensionGoTag).Tag, example); ex != nil {
+			objField.Extensions = append(objField.Extensions, ex)
+		}
+		rootObj.Fields = append(rootObj.Fields, objField)
+	}
+	rootObj.Loaded = true
+	return nil
+}
 
-		objField.Type.Name = "object"
+// loadMappedObj load the object as the mapped builtin type without fields
+func (g *GoLoader) loadMappedObj(query *PkgType, mapping string) error {
+	mapped, err := parseMappedType(mapping)
+	if err != nil {
+		return fmt.Errorf("%s: %v", query, err)
+	}
+	rootObj := g.loadCache(query.fullPath)
+	rootObj.Type = &mkdoc.ObjectType{Name: mapped.TypeName}
+	rootObj.Fields = nil
+	if mapped.Format != "" {
+		rootObj.Extensions = append(rootObj.Extensions, &mkdoc.ExtensionFormat{Format: mapped.Format})
+	}
+	rootObj.Loaded = true
+	return nil
+}
 
-		// builtin array type
-		if goType.IsBuiltin {
-			arrObjs := mkdoc.CreateArrayObjectByID(goType.TypeName, goType.ArrayDepth, g.loadCache)
-			for _, obj := range arrObjs {
-				g.cached[obj.ID] = obj
-			}
-			objField.Type.Ref = arrObjs[len(arrObjs)-1].ID
-			rootObj.Fields = append(rootObj.Fields, objField)
-			continue
-		}
+// objectType returns the object type of go type,
+// the array and map objects are cached and the unloaded structs are queued
+func (g *GoLoader) objectType(goType *GoType, queue *[]string) *mkdoc.ObjectType {
+	// builtin type
+	if goType.IsBuiltin && !goType.IsArray {
+		return &mkdoc.ObjectType{Name: goType.TypeName}
+	}
 
-		pkgTypePath := fmt.Sprintf("%s.%s", goType.ImportPkgName, goType.TypeName)
-		obj := g.loadCache(pkgTypePath)
-		objCached := true
-		if obj == nil {
-			objCached = false
-			obj = &mkdoc.Object{
-				ID: pkgTypePath,
-				Type: &mkdoc.ObjectType{
-					Name:       "object",
-					Ref:        "",
-					IsRepeated: false,
-				},
-				Fields: nil,
-				Loaded: false,
-			}
-			*queue = append(*queue, pkgTypePath)
+	// builtin array type
+	if goType.IsBuiltin {
+		arrObjs := mkdoc.CreateArrayObjectByID(goType.TypeName, goType.ArrayDepth, g.loadCache)
+		for _, obj := range arrObjs {
+			g.cached[obj.ID] = obj
 		}
-		var arrObjs []*mkdoc.Object
-		if goType.IsArray {
-			arrObjs = mkdoc.CreateArrayObject(obj, goType.ArrayDepth)
-		} else {
-			arrObjs = mkdoc.CreateArrayObject(obj, 0)
+		return &mkdoc.ObjectType{Name: "object", Ref: arrObjs[len(arrObjs)-1].ID}
+	}
+
+	// map type,the map object may be wrapped by arrays
+	if goType.IsMap {
+		mapObj := mkdoc.CreateMapObject(goType.MapKey, g.objectType(goType.MapValue, queue))
+		arrObjs := mkdoc.CreateArrayObject(mapObj, goType.ArrayDepth)
+		for _, obj := range arrObjs {
+			g.cached[obj.ID] = obj
 		}
+		return &mkdoc.ObjectType{Name: "object", Ref: arrObjs[len(arrObjs)-1].ID}
+	}
 
-		for _, o := range arrObjs {
-			if !objCached {
-				g.cached[o.ID] = o
-				continue
-			}
-			if o.ID != obj.ID {
-				g.cached[o.ID] = o
-			}
+	pkgTypePath := refID(goType)
+	obj := g.loadCache(pkgTypePath)
+	objCached := true
+	if obj == nil {
+		objCached = false
+		obj = &mkdoc.Object{
+			ID: pkgTypePath,
+			Type: &mkdoc.ObjectType{
+				Name:       "object",
+				Ref:        "",
+				IsRepeated: false,
+			},
+			Fields: nil,
+			Loaded: false,
 		}
-		objField.Type.Ref = arrObjs[len(arrObjs)-1].ID
+		*queue = append(*queue, pkgTypePath)
+	}
+	var arrObjs []*mkdoc.Object
+	if goType.IsArray {
+		arrObjs = mkdoc.CreateArrayObject(obj, goType.ArrayDepth)
+	} else {
+		arrObjs = mkdoc.CreateArrayObject(obj, 0)
+	}
 
-		rootObj.Fields = append(rootObj.Fields, objField)
+	for _, o := range arrObjs {
+		if !objCached {
+			g.cached[o.ID] = o
+			continue
+		}
+		if o.ID != obj.ID {
+			g.cached[o.ID] = o
+		}
 	}
-	rootObj.Loaded = true
-	return nil
+	return &mkdoc.ObjectType{Name: "object", Ref: arrObjs[len(arrObjs)-1].ID}
 }
 
 func (g *GoLoader) getStructInfo(query *PkgType) (*GoStructInfo, error) {
 	var structInfo *GoStructInfo
 	var err error
+	if g.types != nil {
+		return g.types.structInfo(query)
+	}
+	var typeArgs []*GoType
+	for _, arg := range query.TypeArgs {
+		argType, err := parseTypeID(arg)
+		if err != nil {
+			return nil, err
+		}
+		typeArgs = append(typeArgs, argType)
+	}
 	if g.enableGoMod {
 		pkgAbsPath := strings.Replace(query.Package, g.mod.ModulePkg, g.mod.ModulePath, 1)
-		structInfo, err = newStructFinder(g.mod).Find(pkgAbsPath, query.TypeName)
+		if !strings.HasPrefix(query.Package, g.mod.ModulePkg) && isStdPkg(query.Package) {
+			pkgAbsPath = filepath.Join(build.Default.GOROOT, "src", query.Package)
+		}
+		structInfo, err = newStructFinder(g.mod).Find(pkgAbsPath, query.TypeName, typeArgs...)
 		if err != nil {
 			return nil, err
 		}
@@ -290,6 +409,9 @@ func (g *GoLoader) getStructInfo(query *PkgType) (*GoStructInfo, error) {
 	}
 
 	goSrcPaths := mkdoc.GetGOSrcPaths()
+	if isStdPkg(query.Package) {
+		goSrcPaths = append(goSrcPaths, filepath.Join(build.Default.GOROOT, "src"))
+	}
 	pkgAbsPaths := make([]string, 0)
 	for _, p := range goSrcPaths {
 		pkgAbsPath := filepath.Join(p, query.Package)
@@ -297,7 +419,7 @@ func (g *GoLoader) getStructInfo(query *PkgType) (*GoStructInfo, error) {
 		if _, err := os.Stat(pkgAbsPath); err != nil {
 			continue
 		}
-		structInfo, err = newStructFinder(nil).Find(pkgAbsPath, query.TypeName)
+		structInfo, err = newStructFinder(nil).Find(pkgAbsPath, query.TypeName, typeArgs...)
 		if err != nil && err != errGoStructNotFound {
 			return nil, err
 		}
@@ -311,6 +433,13 @@ func (g *GoLoader) getStructInfo(query *PkgType) (*GoStructInfo, error) {
 	return structInfo, nil
 }
 
+// isStdPkg report whether the package is in the standard library which is found in GOROOT,
+// the first element of the path of other packages is a domain,eg. github.com
+func isStdPkg(pkg string) bool {
+	first := strings.SplitN(pkg, "/", 2)[0]
+	return first != "" && !strings.Contains(first, ".")
+}
+
 func (g *GoLoader) Load(ts mkdoc.TypeScope) (*mkdoc.Object, error) {
 	if !g.initialed {
 		return nil, errors.New("loader not initialed")
@@ -4,36 +4,499 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/thewinds/mkdoc"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
-func TestGoLoader_LoadAll(t *testing.T) {
+func newTestLoader(backend string) *GoLoader {
 	loader := new(GoLoader)
-	loader.SetConfig(mkdoc.ObjectLoaderConfig{
+	loader.SetConfig(&mkdoc.ObjectLoaderConfig{
 		Config: mkdoc.Config{
-			Path:        "",
-			Name:        "",
-			Description: "",
-			APIBaseURL:  "",
-			Injects:     nil,
-			BaseType:    "",
-			Scanner:     nil,
-			Generator:   nil,
-			Mime:        nil,
-			Args:        nil,
+			Args: map[string]string{
+				"path":         "testdata/mod",
+				EnableGoModule: "true",
+				LoaderBackend:  backend,
+			},
 		},
 	})
-	tss := []mkdoc.TypeScope{
-		{
-			FileName: "/Users/thewinds/develop/zhidduoke/project/src/corego/service/sale/api/class_adviser.pb.go",
-			TypeName: "CreateClassAdviserReq",
-		},
+	return loader
+}
+
+func fieldNames(obj *mkdoc.Object) []string {
+	var names []string
+	for _, field := range obj.Fields {
+		names = append(names, field.Name+":"+field.Type.Name+":"+field.Desc)
+	}
+	return names
+}
+
+func TestGoLoader_LoadAll(t *testing.T) {
+	fileName, _ := filepath.Abs("testdata/mod/api/api.go")
+	tests := []struct {
+		backend  string
+		typeName string
+		id       string
+		fields   []string
+	}{
+		{"ast", "m.Profile", "example.com/mod/model.Profile", []string{"Age:int:age of user"}},
+		{"packages", "m.Profile", "example.com/mod/model.Profile", []string{"Age:int:age of user"}},
+		// dot import
+		{"packages", "Profile", "example.com/mod/model.Profile", []string{"Age:int:age of user"}},
+		// alias,multiple names,embedded struct and unexported field
+		{"packages", "m.Member", "example.com/mod/model.User", []string{
+			"ID:int64:user id",
+			"Name:string:// name and nick name",
+			"Nick:string:// name and nick name",
+			"Tags:object:",
+			"Profile:object:",
+			"Status:int:",
+			"CreatedAt:int64:",
+		}},
+	}
+	// embedded structs are flattened by the rules of encoding/json
+	for _, backend := range []string{"ast", "packages"} {
+		tests = append(tests, []struct {
+			backend  string
+			typeName string
+			id       string
+			fields   []string
+		}{
+			{backend, "m.UserView", "example.com/mod/model.UserView", []string{
+				"CreatedAt:int64:", "Version:int:", "Extra:object:", "ID:string:", "Name:string:",
+			}},
+			{backend, "m.Conflict", "example.com/mod/model.Conflict", []string{"Source:string:"}},
+			{backend, "m.Node", "example.com/mod/model.Node", []string{"Value:int:"}},
+			// the embedded type not found by the ast backend is skipped
+			{backend, "m.Record", "example.com/mod/model.Record", []string{"Name:string:"}},
+			{backend, "Resp", "example.com/mod/api.Resp", []string{"ID:int64:", "CreatedAt:int64:", "Data:string:"}},
+		}...)
+	}
+	for _, tt := range tests {
+		loader := newTestLoader(tt.backend)
+		ts := mkdoc.TypeScope{FileName: fileName, TypeName: tt.typeName}
+		id, err := loader.GetObjectId(ts)
+		if err != nil {
+			t.Errorf("%s: GetObjectId(%s) error: %v", tt.backend, tt.typeName, err)
+			continue
+		}
+		if id != tt.id {
+			t.Errorf("%s: GetObjectId(%s) got %s, want %s", tt.backend, tt.typeName, id, tt.id)
+		}
+		if _, err := loader.LoadAll([]mkdoc.TypeScope{ts}); err != nil {
+			t.Errorf("%s: LoadAll(%s) error: %v", tt.backend, tt.typeName, err)
+			continue
+		}
+		obj := loader.cached[id]
+		if got := fieldNames(obj); len(got) != len(tt.fields) {
+			t.Errorf("%s: fields of %s got %q, want %q", tt.backend, id, got, tt.fields)
+		} else {
+			for i := range got {
+				if got[i] != tt.fields[i] {
+					t.Errorf("%s: fields of %s got %q, want %q", tt.backend, id, got, tt.fields)
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestTypesLoader_ObjectID(t *testing.T) {
+	fileName, _ := filepath.Abs("testdata/mod/api/api.go")
+	l := newTypesLoader("testdata/mod", "./...")
+	tests := map[string]string{
+		"[]m.User":   "[]example.com/mod/model.User",
+		"[][]*User":  "[][]example.com/mod/model.User",
+		"m.Status":   "int",
+		"[]m.Status": "[]int",
+		"string":     "string",
+	}
+	for typeName, want := range tests {
+		got, err := l.objectID(mkdoc.TypeScope{FileName: fileName, TypeName: typeName})
+		if err != nil {
+			t.Errorf("objectID(%s) error: %v", typeName, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("objectID(%s) got %s, want %s", typeName, got, want)
+		}
+	}
+}
+
+func TestGoLoader_LoadMap(t *testing.T) {
+	fileName, _ := filepath.Abs("testdata/mod/api/api.go")
+	for _, backend := range []string{"ast", "packages"} {
+		loader := newTestLoader(backend)
+		if _, err := loader.LoadAll([]mkdoc.TypeScope{{FileName: fileName, TypeName: "m.Group"}}); err != nil {
+			t.Errorf("%s: LoadAll error: %v", backend, err)
+			continue
+		}
+		group := loader.cached["example.com/mod/model.Group"]
+		if len(group.Fields) != 3 {
+			t.Errorf("%s: fields of group got %q", backend, fieldNames(group))
+			continue
+		}
+		members := loader.cached[group.Fields[0].Type.Ref]
+		if members == nil || members.Type.MapKey != "string" || members.Type.Ref != "example.com/mod/model.User" {
+			t.Errorf("%s: unexpected members %+v", backend, members)
+		}
+		scores := loader.cached[group.Fields[1].Type.Ref]
+		if scores == nil || scores.Type.MapKey != "int64" || loader.cached[scores.Type.Ref] == nil ||
+			!loader.cached[scores.Type.Ref].Type.IsRepeated {
+			t.Errorf("%s: unexpected scores %+v", backend, scores)
+		}
+		labels := loader.cached[group.Fields[2].Type.Ref]
+		if labels == nil || !labels.Type.IsRepeated || loader.cached[labels.Type.Ref] == nil ||
+			loader.cached[labels.Type.Ref].Type.MapKey != "string" || loader.cached[labels.Type.Ref].Type.Name != "string" {
+			t.Errorf("%s: unexpected labels %+v", backend, labels)
+		}
+	}
+}
+
+func TestGoLoader_LoadGeneric(t *testing.T) {
+	fileName, _ := filepath.Abs("testdata/mod/api/api.go")
+	const (
+		user    = "example.com/mod/model.User"
+		profile = "example.com/mod/model.Profile"
+	)
+	tests := []struct {
+		typeName string
+		id       string
+		fields   map[string]string
+	}{
+		{"Result[m.User]", "example.com/mod/api.Result[" + user + "]", map[string]string{
+			"Code": "int",
+			"Data": user,
+			"List": "[]" + user,
+			"Page": "example.com/mod/api.Page[" + user + "]",
+		}},
+		{"[]Pair[string,*m.Profile]", "[]example.com/mod/api.Pair[string," + profile + "]", map[string]string{
+			"Key":   "string",
+			"Value": profile,
+		}},
+		{"Result[[]int]", "example.com/mod/api.Result[[]int]", map[string]string{
+			"Code": "int",
+			"Data": "[]int",
+			"List": "[][]int",
+			"Page": "example.com/mod/api.Page[[]int]",
+		}},
+		// type parameters are interface{} if the type is not instantiated
+		{"Pair", "example.com/mod/api.Pair", map[string]string{
+			"Key":   "interface{}",
+			"Value": "interface{}",
+		}},
+	}
+	for _, backend := range []string{"ast", "packages"} {
+		for _, tt := range tests {
+			loader := newTestLoader(backend)
+			ts := mkdoc.TypeScope{FileName: fileName, TypeName: tt.typeName}
+			id, err := loader.GetObjectId(ts)
+			if err != nil {
+				t.Errorf("%s: GetObjectId(%s) error: %v", backend, tt.typeName, err)
+				continue
+			}
+			if id != tt.id {
+				t.Errorf("%s: GetObjectId(%s) got %s, want %s", backend, tt.typeName, id, tt.id)
+				continue
+			}
+			if _, err := loader.LoadAll([]mkdoc.TypeScope{ts}); err != nil {
+				t.Errorf("%s: LoadAll(%s) error: %v", backend, tt.typeName, err)
+				continue
+			}
+			obj := loader.cached[id]
+			for obj != nil && obj.Type.IsRepeated {
+				obj = loader.cached[obj.Type.Ref]
+			}
+			if obj == nil || len(obj.Fields) != len(tt.fields) {
+				t.Errorf("%s: unexpected object %s %+v", backend, id, obj)
+				continue
+			}
+			for _, field := range obj.Fields {
+				if got := typeOfField(loader, field.Type); got != tt.fields[field.Name] {
+					t.Errorf("%s: field %s.%s got %s, want %s", backend, id, field.Name, got, tt.fields[field.Name])
+				}
+			}
+		}
+	}
+	// the generic type referenced by field is instantiated too
+	loader := newTestLoader("ast")
+	if _, err := loader.LoadAll([]mkdoc.TypeScope{{FileName: fileName, TypeName: "Result[m.User]"}}); err != nil {
+		t.Fatal(err)
+	}
+	page := loader.cached["example.com/mod/api.Page["+user+"]"]
+	if page == nil || !page.Loaded || len(page.Fields) != 1 {
+		t.Fatalf("unexpected page %+v", page)
+	}
+	if items := loader.cached[page.Fields[0].Type.Ref]; items == nil || items.Type.MapKey != "string" || items.Type.Ref != user {
+		t.Errorf("unexpected items %+v", items)
+	}
+}
+
+// typeOfField returns the referenced object id of field type,arrays are prefixed by [],
+// named builtin types and aliases are resolved
+func typeOfField(loader *GoLoader, typ *mkdoc.ObjectType) string {
+	if typ.Ref == "" {
+		return typ.Name
+	}
+	obj := loader.cached[typ.Ref]
+	switch {
+	case obj == nil:
+		return typ.Ref
+	case obj.Type.IsRepeated:
+		return "[]" + typeOfField(loader, &mkdoc.ObjectType{Name: obj.Type.Name, Ref: obj.Type.Ref})
+	case obj.Type.Name != "object":
+		return obj.Type.Name
+	case obj.Type.Ref != "":
+		return typeOfField(loader, obj.Type)
+	}
+	return typ.Ref
+}
+
+func TestGoLoader_LoadNamedType(t *testing.T) {
+	fileName, _ := filepath.Abs("testdata/mod/api/api.go")
+	wantFields := map[string]string{
+		"ID":     "string",
+		"Level":  "int",
+		"Kind":   "string",
+		"Tags":   "[]string",
+		"Owner":  "example.com/mod/model.User",
+		"Levels": "[]int",
+	}
+	wantEnums := map[string][]string{
+		"Level": {"LevelNormal=1:normal user", "LevelVIP=2:vip user", "LevelAdmin=3:administrator"},
+		"Kind":  {"KindPersonal=personal:Kind of account", "KindTeam=team:", "KindOrg=org:"},
+	}
+	for _, backend := range []string{"ast", "packages"} {
+		loader := newTestLoader(backend)
+		if _, err := loader.LoadAll([]mkdoc.TypeScope{{FileName: fileName, TypeName: "m.Account"}}); err != nil {
+			t.Errorf("%s: LoadAll error: %v", backend, err)
+			continue
+		}
+		account := loader.cached["example.com/mod/model.Account"]
+		if len(account.Fields) != len(wantFields) {
+			t.Errorf("%s: fields of account got %q", backend, fieldNames(account))
+			continue
+		}
+		for _, field := range account.Fields {
+			if got := typeOfField(loader, field.Type); got != wantFields[field.Name] {
+				t.Errorf("%s: field %s got %s, want %s", backend, field.Name, got, wantFields[field.Name])
+			}
+			want, ok := wantEnums[field.Name]
+			if !ok {
+				continue
+			}
+			var enum *mkdoc.ExtensionEnum
+			if obj := loader.cached[field.Type.Ref]; obj != nil && len(obj.Extensions) > 0 {
+				enum, _ = obj.Extensions[0].(*mkdoc.ExtensionEnum)
+			}
+			if enum == nil {
+				t.Errorf("%s: field %s want enum", backend, field.Name)
+				continue
+			}
+			var got []string
+			for _, v := range enum.Values {
+				got = append(got, v.Name+"="+v.Value+":"+v.Desc)
+			}
+			if strings.Join(got, ",") != strings.Join(want, ",") {
+				t.Errorf("%s: enum of %s got %q, want %q", backend, field.Name, got, want)
+			}
+		}
+	}
+}
+
+func TestGoLoader_LoadMappedType(t *testing.T) {
+	fileName, _ := filepath.Abs("testdata/mod/api/api.go")
+	wantFields := map[string]string{
+		"Event":    "string(date-time)",
+		"EndAt":    "string(date-time)",
+		"Duration": "string(duration)",
+		"Remind":   "[]string(date-time)",
+		"Remark":   "string",
+		"Payload":  "interface{}",
+		"Price":    "string(decimal)",
+		"Rate":     "string",
+		"Deleted":  "database/sql.NullTime",
+	}
+	for _, backend := range []string{"ast", "packages"} {
+		loader := new(GoLoader)
+		loader.SetConfig(&mkdoc.ObjectLoaderConfig{
+			Config: mkdoc.Config{
+				Args: map[string]string{
+					"path":         "testdata/mod",
+					EnableGoModule: "true",
+					LoaderBackend:  backend,
+				},
+				TypeMapping: map[string]string{
+					"time.Duration":               "string(duration)",
+					"database/sql.NullString":     "string",
+					"example.com/mod/model.Money": "string(decimal)",
+				},
+			},
+		})
+		if _, err := loader.LoadAll([]mkdoc.TypeScope{{FileName: fileName, TypeName: "m.Schedule"}}); err != nil {
+			t.Errorf("%s: LoadAll error: %v", backend, err)
+			continue
+		}
+		var nullTime []string
+		for _, field := range loader.cached["database/sql.NullTime"].Fields {
+			nullTime = append(nullTime, field.Name)
+		}
+		if strings.Join(nullTime, ",") != "Time,Valid" {
+			t.Errorf("%s: fields of sql.NullTime got %q", backend, nullTime)
+		}
+		schedule := loader.cached["example.com/mod/model.Schedule"]
+		if len(schedule.Fields) != len(wantFields) {
+			t.Errorf("%s: fields of schedule got %q", backend, fieldNames(schedule))
+			continue
+		}
+		for _, field := range schedule.Fields {
+			got := typeOfField(loader, field.Type)
+			// the format is kept by the element of array
+			for obj := loader.cached[field.Type.Ref]; obj != nil; obj = loader.cached[obj.Type.Ref] {
+				if len(obj.Extensions) > 0 {
+					got += "(" + obj.Extensions[0].(*mkdoc.ExtensionFormat).Format + ")"
+				}
+			}
+			if got != wantFields[field.Name] {
+				t.Errorf("%s: field %s got %s, want %s", backend, field.Name, got, wantFields[field.Name])
+			}
+		}
+	}
+}
+
+func TestGoLoader_LoadMarshaler(t *testing.T) {
+	fileName, _ := filepath.Abs("testdata/mod/api/api.go")
+	wantFields := map[string]string{
+		"Status":      "string",
+		"Location":    "interface{}(opaque)",
+		"Path":        "[]interface{}(opaque)",
+		"Birthday":    "string(date)",
+		"Version":     "int",
+		"Profile":     "example.com/mod/model.Profile",
+		"Shipment":    "string",
+		"Anniversary": "string(date)",
+		"Amount":      "interface{}(opaque)",
+		"Grade":       "string",
+	}
+	for _, backend := range []string{"ast", "packages"} {
+		loader := newTestLoader(backend)
+		if _, err := loader.LoadAll([]mkdoc.TypeScope{{FileName: fileName, TypeName: "m.Order"}}); err != nil {
+			t.Errorf("%s: LoadAll error: %v", backend, err)
+			continue
+		}
+		order := loader.cached["example.com/mod/model.Order"]
+		if len(order.Fields) != len(wantFields) {
+			t.Errorf("%s: fields of order got %q", backend, fieldNames(order))
+			continue
+		}
+		for _, field := range order.Fields {
+			got := typeOfField(loader, field.Type)
+			for obj := loader.cached[field.Type.Ref]; obj != nil; obj = loader.cached[obj.Type.Ref] {
+				for _, ext := range obj.Extensions {
+					switch e := ext.(type) {
+					case *mkdoc.ExtensionFormat:
+						got += "(" + e.Format + ")"
+					case *mkdoc.ExtensionOpaque:
+						got += "(opaque)"
+					}
+				}
+			}
+			if got != wantFields[field.Name] {
+				t.Errorf("%s: field %s got %s, want %s", backend, field.Name, got, wantFields[field.Name])
+			}
+		}
+	}
+}
+
+func TestGoLoader_LoadConstraints(t *testing.T) {
+	fileName, _ := filepath.Abs("testdata/mod/api/api.go")
+	want := []string{
+		"Name:required=true,omitempty=false,string=false",
+		"Age:required=false,omitempty=true,string=true",
+		"Email:required=true,omitempty=true,string=false",
+		"Meta:-",
+		"Color:-",
+		"Level:-",
+		"Tags:-",
+	}
+	for _, backend := range []string{"ast", "packages"} {
+		loader := newTestLoader(backend)
+		if _, err := loader.LoadAll([]mkdoc.TypeScope{{FileName: fileName, TypeName: "m.CreateReq"}}); err != nil {
+			t.Errorf("%s: LoadAll error: %v", backend, err)
+			continue
+		}
+		var got []string
+		for _, field := range loader.cached["example.com/mod/model.CreateReq"].Fields {
+			c := "-"
+			for _, ext := range field.Extensions {
+				if e, ok := ext.(*mkdoc.ExtensionConstraints); ok {
+					c = fmt.Sprintf("required=%v,omitempty=%v,string=%v", e.Required, e.OmitEmpty, e.String)
+				}
+			}
+			got = append(got, field.Name+":"+c)
+		}
+		if strings.Join(got, " ") != strings.Join(want, " ") {
+			t.Errorf("%s: constraints got %q, want %q", backend, got, want)
+		}
+	}
+}
+
+func TestGoLoader_LoadRules(t *testing.T) {
+	fileName, _ := filepath.Abs("testdata/mod/api/api.go")
+	want := map[string]string{
+		"Name":  `{"min_length":2,"max_length":32}`,
+		"Age":   `{"minimum":1}`,
+		"Email": `{"format":"email"}`,
+		"Color": `{"enum":["red","green","light blue"]}`,
+		"Level": `{"minimum":0,"maximum":3,"exclusive_minimum":true}`,
+		"Tags":  `{"max_length":5}`,
+	}
+	for _, backend := range []string{"ast", "packages"} {
+		loader := newTestLoader(backend)
+		if _, err := loader.LoadAll([]mkdoc.TypeScope{{FileName: fileName, TypeName: "m.CreateReq"}}); err != nil {
+			t.Errorf("%s: LoadAll error: %v", backend, err)
+			continue
+		}
+		for _, field := range loader.cached["example.com/mod/model.CreateReq"].Fields {
+			got := ""
+			for _, ext := range field.Extensions {
+				if e, ok := ext.(*mkdoc.ExtensionRules); ok {
+					b, _ := json.Marshal(e)
+					got = string(b)
+				}
+			}
+			if got != want[field.Name] {
+				t.Errorf("%s: rules of %s got %s, want %s", backend, field.Name, got, want[field.Name])
+			}
+		}
+	}
+}
+
+func TestGoLoader_LoadExample(t *testing.T) {
+	fileName, _ := filepath.Abs("testdata/mod/api/api.go")
+	want := map[string]string{
+		"Name":  "alice",
+		"Email": "alice@example.com",
 	}
-	objects, err := loader.LoadAll(tss)
-	if err != nil {
-		t.Error(err)
-		return
+	for _, backend := range []string{"ast", "packages"} {
+		loader := newTestLoader(backend)
+		if _, err := loader.LoadAll([]mkdoc.TypeScope{{FileName: fileName, TypeName: "m.CreateReq"}}); err != nil {
+			t.Errorf("%s: LoadAll error: %v", backend, err)
+			continue
+		}
+		for _, field := range loader.cached["example.com/mod/model.CreateReq"].Fields {
+			got := ""
+			for _, ext := range field.Extensions {
+				if e, ok := ext.(*mkdoc.ExtensionExample); ok {
+					got = e.Value
+				}
+			}
+			if got != want[field.Name] {
+				t.Errorf("%s: example of %s got %s, want %s", backend, field.Name, got, want[field.Name])
+			}
+			if field.Name == "Email" && field.Desc != "邮箱" {
+				t.Errorf("%s: desc of Email got %q, want 邮箱", backend, field.Desc)
+			}
+		}
 	}
-	b, _ := json.MarshalIndent(objects, "", "\t")
-	fmt.Println(string(b))
 }
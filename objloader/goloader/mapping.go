@@ -0,0 +1,51 @@
+package goloader
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// builtinTypeMapping maps the well-known types to builtin types,
+// they are marshaled to json as the builtin type rather than the struct fields,
+// database/sql.NullXXX are not mapped since they are marshaled as structs without a marshaler
+var builtinTypeMapping = map[string]string{
+	"time.Time":                             "string(date-time)",
+	"time.Duration":                         "int64",
+	"math/big.Int":                          "int64",
+	"math/big.Float":                        "string",
+	"encoding/json.RawMessage":              "interface{}",
+	"github.com/google/uuid.UUID":           "string(uuid)",
+	"github.com/gofrs/uuid.UUID":            "string(uuid)",
+	"github.com/satori/go.uuid.UUID":        "string(uuid)",
+	"github.com/shopspring/decimal.Decimal": "string(decimal)",
+}
+
+// mappedType is a builtin type with an optional format
+type mappedType struct {
+	TypeName string
+	Format   string
+}
+
+var reMappedType = regexp.MustCompile(`^\s*([^\s()]+)\s*(?:\(\s*([^\s()]+)\s*\))?\s*$`)
+
+// parseMappedType parse the mapped type,eg. string(date-time),int64
+func parseMappedType(s string) (*mappedType, error) {
+	matches := reMappedType.FindStringSubmatch(s)
+	if len(matches) != 3 || !isBuiltinType(matches[1]) {
+		return nil, fmt.Errorf("invalid type mapping '%s',want a builtin type with optional format like string(date-time)", s)
+	}
+	return &mappedType{TypeName: matches[1], Format: matches[2]}, nil
+}
+
+// typeMapping merge the builtin type mapping and the mapping of config,
+// the mapping of config takes precedence
+func typeMapping(config map[string]string) map[string]string {
+	mapping := make(map[string]string, len(builtinTypeMapping)+len(config))
+	for k, v := range builtinTypeMapping {
+		mapping[k] = v
+	}
+	for k, v := range config {
+		mapping[k] = v
+	}
+	return mapping
+}
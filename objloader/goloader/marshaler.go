@@ -0,0 +1,128 @@
+package goloader
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// marshalers are the methods change the json encoding of type,
+// MarshalJSON takes precedence over MarshalText like encoding/json
+var marshalers = []string{"MarshalJSON", "MarshalText"}
+
+var reTypeDirective = regexp.MustCompile(`(?m)^\s*@mkdoc:type\s+(\S+)\s*$`)
+
+// typeDirective returns the type declared by `@mkdoc:type` in doc comment,
+// eg. `// @mkdoc:type string(date-time)`
+func typeDirective(doc string) string {
+	matches := reTypeDirective.FindStringSubmatch(doc)
+	if len(matches) != 2 {
+		return ""
+	}
+	return matches[1]
+}
+
+var reExampleDirective = regexp.MustCompile(`(?m)^[ \t]*(?://[ \t]*)?@example[ \t]+(.*?)[ \t]*(?:\n|$)`)
+
+// exampleDirective split the example declared by `@example` from the comment of field,
+// eg. `// @example alice@example.com`
+func exampleDirective(comment string) (string, string) {
+	matches := reExampleDirective.FindStringSubmatch(comment)
+	if len(matches) != 2 {
+		return comment, ""
+	}
+	return strings.TrimSpace(reExampleDirective.ReplaceAllString(comment, "")), matches[1]
+}
+
+// typeDoc returns the doc comment of the type spec matched in files,
+// the doc of declaration is used if the type is not declared in a group
+func typeDoc(files []*ast.File, match func(spec *ast.TypeSpec) bool) string {
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				ts := spec.(*ast.TypeSpec)
+				if !match(ts) {
+					continue
+				}
+				if ts.Doc == nil && !genDecl.Lparen.IsValid() {
+					return genDecl.Doc.Text()
+				}
+				return ts.Doc.Text()
+			}
+		}
+	}
+	return ""
+}
+
+// astMarshaler returns the marshal method declared on the type or the pointer of it,
+// the methods promoted from embedded fields are found by promotedEncoding
+func astMarshaler(pkg *ast.Package, typeName string) string {
+	declared := make(map[string]bool)
+	for _, file := range pkg.Files {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv == nil || len(fn.Recv.List) == 0 {
+				continue
+			}
+			if recvTypeName(fn.Recv.List[0].Type) == typeName {
+				declared[fn.Name.Name] = true
+			}
+		}
+	}
+	for _, name := range marshalers {
+		if declared[name] {
+			return name
+		}
+	}
+	return ""
+}
+
+// recvTypeName returns the type name of receiver,eg. *Resp[T] => Resp
+func recvTypeName(x ast.Expr) string {
+	switch t := x.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.StarExpr:
+		return recvTypeName(t.X)
+	case *ast.ParenExpr:
+		return recvTypeName(t.X)
+	case *ast.IndexExpr:
+		return recvTypeName(t.X)
+	case *ast.IndexListExpr:
+		return recvTypeName(t.X)
+	}
+	return ""
+}
+
+// typesMarshaler returns the marshal method in the method set of the pointer of type,
+// including the methods promoted from embedded fields
+func typesMarshaler(typ types.Type) (name string, promoted bool) {
+	methods := types.NewMethodSet(types.NewPointer(typ))
+	for _, name := range marshalers {
+		if sel := methods.Lookup(nil, name); sel != nil {
+			return name, len(sel.Index()) > 1
+		}
+	}
+	return "", false
+}
+
+// astFiles returns the files of package sorted by file name
+func astFiles(pkg *ast.Package) []*ast.File {
+	var fileNames []string
+	for fileName := range pkg.Files {
+		fileNames = append(fileNames, fileName)
+	}
+	sort.Strings(fileNames)
+	files := make([]*ast.File, 0, len(fileNames))
+	for _, fileName := range fileNames {
+		files = append(files, pkg.Files[fileName])
+	}
+	return files
+}
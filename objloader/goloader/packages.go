@@ -0,0 +1,371 @@
+package goloader
+
+import (
+	"fmt"
+	"github.com/thewinds/mkdoc"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"golang.org/x/tools/go/packages"
+	"path/filepath"
+	"strings"
+)
+
+// the dependencies are type checked from source,
+// so the export data of the go toolchain is not needed
+const packagesLoadMode = packages.NeedName | packages.NeedFiles | packages.NeedSyntax |
+	packages.NeedTypes | packages.NeedTypesInfo | packages.NeedImports | packages.NeedDeps
+
+// typesLoader resolve go types by the type checker,
+// the packages are loaded by golang.org/x/tools/go/packages
+// so vendor,replace directives,go.work and external modules are supported
+type typesLoader struct {
+	dir     string
+	pattern string
+	loaded  bool
+	pkgs    map[string]*packages.Package
+	// typeMapping is the mapping of go loader,the mapped types are kept
+	typeMapping map[string]string
+}
+
+// newTypesLoader create a loader which loads the packages matched pattern in dir first,
+// the packages not imported by them are loaded on demand
+func newTypesLoader(dir string, pattern string) *typesLoader {
+	return &typesLoader{dir: dir, pattern: pattern, pkgs: make(map[string]*packages.Package)}
+}
+
+// load the packages and their dependencies,returns the first matched package
+func (l *typesLoader) load(dir string, pattern string) (*packages.Package, error) {
+	cfg := &packages.Config{Mode: packagesLoadMode, Dir: dir}
+	pkgs, err := packages.Load(cfg, pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) == 0 {
+		return nil, fmt.Errorf("package %s not found", pattern)
+	}
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		if pkg.Types != nil && l.pkgs[pkg.PkgPath] == nil {
+			l.pkgs[pkg.PkgPath] = pkg
+		}
+	})
+	pkg := pkgs[0]
+	// type errors are ignored,most of the types can be resolved anyway
+	for _, e := range pkg.Errors {
+		if e.Kind == packages.ListError {
+			return nil, fmt.Errorf("load package %s: %v", pattern, e)
+		}
+	}
+	if pkg.Types == nil {
+		return nil, fmt.Errorf("package %s not found", pattern)
+	}
+	return pkg, nil
+}
+
+func (l *typesLoader) init() error {
+	if l.loaded {
+		return nil
+	}
+	l.loaded = true
+	_, err := l.load(l.dir, l.pattern)
+	return err
+}
+
+func (l *typesLoader) packageOf(path string) (*packages.Package, error) {
+	if err := l.init(); err != nil {
+		return nil, err
+	}
+	if pkg := l.pkgs[path]; pkg != nil {
+		return pkg, nil
+	}
+	return l.load(l.dir, path)
+}
+
+// fileOf returns the package and the syntax of a go file
+func (l *typesLoader) fileOf(fileName string) (*packages.Package, *ast.File, error) {
+	if err := l.init(); err != nil {
+		return nil, nil, err
+	}
+	fileName, err := filepath.Abs(fileName)
+	if err != nil {
+		return nil, nil, err
+	}
+	find := func(pkg *packages.Package) *ast.File {
+		for _, file := range pkg.Syntax {
+			if pkg.Fset.File(file.Pos()).Name() == fileName {
+				return file
+			}
+		}
+		return nil
+	}
+	for _, pkg := range l.pkgs {
+		if file := find(pkg); file != nil {
+			return pkg, file, nil
+		}
+	}
+	pkg, err := l.load(filepath.Dir(fileName), "file="+fileName)
+	if err != nil {
+		return nil, nil, err
+	}
+	if file := find(pkg); file != nil {
+		return pkg, file, nil
+	}
+	return nil, nil, fmt.Errorf("file %s not found in package %s", fileName, pkg.PkgPath)
+}
+
+// objectID resolve the type name in the scope of file,
+// import alias,dot import and type alias are resolved by the type checker
+//
+//	[]model.User => []github.com/x/model.User
+func (l *typesLoader) objectID(ts mkdoc.TypeScope) (string, error) {
+	pkg, file, err := l.fileOf(ts.FileName)
+	if err != nil {
+		return "", err
+	}
+	tv, err := types.Eval(pkg.Fset, pkg.Types, file.Package, ts.TypeName)
+	if err != nil {
+		return "", fmt.Errorf("%s: %v", ts.FileName, err)
+	}
+	if !tv.IsType() {
+		return "", fmt.Errorf("%s: %s is not a type", ts.FileName, ts.TypeName)
+	}
+	goType := l.goTypeOf(tv.Type)
+	if goType.NotSupport || goType.IsMap {
+		return "", fmt.Errorf("%s: type %s is not support", ts.FileName, ts.TypeName)
+	}
+	return typeID(goType), nil
+}
+
+// structInfo returns the struct info of type query,
+// generic types are instantiated by the type arguments,
+// unexported fields are ignored
+func (l *typesLoader) structInfo(query *PkgType) (*GoStructInfo, error) {
+	pkg, err := l.packageOf(query.Package)
+	if err != nil {
+		return nil, err
+	}
+	obj := pkg.Types.Scope().Lookup(query.TypeName)
+	if obj == nil {
+		return nil, fmt.Errorf("struct %s not found", query)
+	}
+	typ := obj.Type()
+	if len(query.TypeArgs) > 0 {
+		var args []types.Type
+		for _, arg := range query.TypeArgs {
+			argType, err := parseTypeID(arg)
+			if err != nil {
+				return nil, err
+			}
+			t, err := l.typeOf(argType)
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, t)
+		}
+		typ, err = types.Instantiate(nil, typ, args, true)
+		if err != nil {
+			return nil, fmt.Errorf("instantiate %s: %v", query, err)
+		}
+	}
+	info := &GoStructInfo{
+		Name:      query.TypeName,
+		Fields:    make([]*GoStructField, 0),
+		Directive: typeDirective(typeDoc(pkg.Syntax, func(ts *ast.TypeSpec) bool { return ts.Name.Pos() == obj.Pos() })),
+	}
+	info.Marshaler, info.Promoted = typesMarshaler(typ)
+	st, ok := typ.Underlying().(*types.Struct)
+	if !ok {
+		// named non struct types and aliases are resolved to the underlying type
+		underlying := l.goTypeOf(typ.Underlying())
+		if underlying.NotSupport {
+			fmt.Printf("WARNING: type %s is not support,please check it \n", query.TypeName)
+			return info, nil
+		}
+		info.Underlying = underlying
+		if underlying.IsBuiltin && !underlying.IsArray {
+			info.Enums = typesEnums(pkg, typ)
+		}
+		return info, nil
+	}
+	info.IsStruct = true
+	astFields := structFields(pkg, obj.Pos())
+	for i := 0; i < st.NumFields(); i++ {
+		v := st.Field(i)
+		if !v.Exported() && !v.Anonymous() {
+			continue
+		}
+		field := &GoStructField{
+			Name:     v.Name(),
+			GoType:   l.goTypeOf(v.Type()),
+			Embedded: v.Anonymous(),
+		}
+		if st.Tag(i) != "" {
+			field.Tag = "`" + st.Tag(i) + "`"
+		}
+		if len(astFields) == st.NumFields() {
+			f := astFields[i]
+			if f.Comment != nil && len(f.Comment.List) != 0 {
+				field.Comment = f.Comment.List[0].Text
+			}
+			field.DocComment = strings.TrimRight(f.Doc.Text(), "\n")
+		}
+		info.Fields = append(info.Fields, field)
+	}
+	return info, nil
+}
+
+// structFields returns the ast field of each struct field,
+// a field declared with multiple names is repeated
+func structFields(pkg *packages.Package, pos token.Pos) []*ast.Field {
+	var fields []*ast.Field
+	for _, file := range pkg.Syntax {
+		if pos < file.Pos() || pos > file.End() {
+			continue
+		}
+		ast.Inspect(file, func(node ast.Node) bool {
+			spec, ok := node.(*ast.TypeSpec)
+			if !ok || spec.Name.Pos() != pos {
+				return fields == nil
+			}
+			if st, ok := spec.Type.(*ast.StructType); ok {
+				for _, field := range st.Fields.List {
+					fields = append(fields, field)
+					for i := 1; i < len(field.Names); i++ {
+						fields = append(fields, field)
+					}
+				}
+			}
+			return false
+		})
+	}
+	return fields
+}
+
+// goTypeOf convert the type checked type to GoType,
+// named types of non struct are converted to the underlying type unless they have constants or are mapped
+func (l *typesLoader) goTypeOf(t types.Type) *GoType {
+	// the mapped types are kept even if they are aliases,eg. json.RawMessage
+	if obj := typeNameOf(t); obj != nil && obj.Pkg() != nil {
+		if _, ok := l.typeMapping[obj.Pkg().Path()+"."+obj.Name()]; ok {
+			return &GoType{
+				TypeName:      obj.Name(),
+				PkgName:       obj.Pkg().Name(),
+				ImportPkgName: obj.Pkg().Path(),
+				IsRef:         true,
+			}
+		}
+	}
+	switch t := types.Unalias(t).(type) {
+	case *types.Pointer:
+		return l.goTypeOf(t.Elem())
+	case *types.Slice:
+		goType := l.goTypeOf(t.Elem())
+		goType.IsArray = true
+		goType.ArrayDepth++
+		return goType
+	case *types.Array:
+		goType := l.goTypeOf(t.Elem())
+		goType.IsArray = true
+		goType.ArrayDepth++
+		return goType
+	case *types.Basic:
+		name := t.Name()
+		if name == "rune" {
+			name = "int32"
+		}
+		return &GoType{TypeName: name, IsBuiltin: isBuiltinType(name), NotSupport: !isBuiltinType(name)}
+	case *types.Interface, *types.TypeParam:
+		// the type parameters of an uninstantiated generic type are treated as interface{}
+		return &GoType{TypeName: "interface{}", IsBuiltin: true}
+	case *types.Map:
+		return mapType(l.goTypeOf(t.Key()), l.goTypeOf(t.Elem()))
+	case *types.Named:
+		obj := t.Obj()
+		_, isStruct := t.Underlying().(*types.Struct)
+		_, isBasic := t.Underlying().(*types.Basic)
+		// named types with constants are kept to describe the enum values,
+		// and the marshalers are kept to change the encoding of underlying type
+		marshaler, _ := typesMarshaler(t)
+		if !isStruct && !(isBasic && hasEnums(t)) && marshaler == "" || obj.Pkg() == nil {
+			return l.goTypeOf(t.Underlying())
+		}
+		goType := &GoType{
+			TypeName:      obj.Name(),
+			PkgName:       obj.Pkg().Name(),
+			ImportPkgName: obj.Pkg().Path(),
+			IsRef:         true,
+		}
+		for i := 0; i < t.TypeArgs().Len(); i++ {
+			arg := l.goTypeOf(t.TypeArgs().At(i))
+			goType.NotSupport = goType.NotSupport || arg.NotSupport
+			goType.TypeArgs = append(goType.TypeArgs, arg)
+		}
+		return goType
+	}
+	return &GoType{NotSupport: true}
+}
+
+// typeNameOf returns the type name of named types and aliases
+func typeNameOf(t types.Type) *types.TypeName {
+	switch t := t.(type) {
+	case *types.Alias:
+		return t.Obj()
+	case *types.Named:
+		return t.Obj()
+	}
+	return nil
+}
+
+// typeOf returns the type checked type of go type
+func (l *typesLoader) typeOf(goType *GoType) (types.Type, error) {
+	var t types.Type
+	switch {
+	case goType.IsMap:
+		key, err := l.typeOf(&GoType{TypeName: goType.MapKey, IsBuiltin: true})
+		if err != nil {
+			return nil, err
+		}
+		value, err := l.typeOf(goType.MapValue)
+		if err != nil {
+			return nil, err
+		}
+		t = types.NewMap(key, value)
+	case goType.TypeName == "interface{}":
+		t = types.NewInterfaceType(nil, nil)
+	case goType.TypeName == "float":
+		t = types.Typ[types.Float64]
+	case goType.IsBuiltin:
+		obj := types.Universe.Lookup(goType.TypeName)
+		if obj == nil {
+			return nil, fmt.Errorf("type %s not found", goType.TypeName)
+		}
+		t = obj.Type()
+	default:
+		pkg, err := l.packageOf(goType.ImportPkgName)
+		if err != nil {
+			return nil, err
+		}
+		obj := pkg.Types.Scope().Lookup(goType.TypeName)
+		if obj == nil {
+			return nil, fmt.Errorf("type %s not found", refID(goType))
+		}
+		t = obj.Type()
+		if len(goType.TypeArgs) > 0 {
+			var args []types.Type
+			for _, arg := range goType.TypeArgs {
+				argType, err := l.typeOf(arg)
+				if err != nil {
+					return nil, err
+				}
+				args = append(args, argType)
+			}
+			if t, err = types.Instantiate(nil, t, args, true); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for i := 0; i < goType.ArrayDepth; i++ {
+		t = types.NewSlice(t)
+	}
+	return t, nil
+}
@@ -2,27 +2,41 @@ package goloader
 
 import (
 	"fmt"
+	"go/parser"
+	"path"
 	"strings"
 )
 
 // PkgType go type and package location
+//
+// TypeArgs is the object id of type arguments if that is an instantiated generic type
 type PkgType struct {
 	Package  string
 	TypeName string
+	TypeArgs []string
 	fullPath string
 }
 
 func (p *PkgType) parse() error {
-	// github.com/a.b.c
-	i := strings.LastIndex(p.fullPath, ".")
+	// github.com/a.b.c[github.com/a.d]
+	name, args, err := splitTypeArgs(p.fullPath)
+	if err != nil {
+		return err
+	}
+	i := strings.LastIndex(name, ".")
 	if i == -1 {
 		return fmt.Errorf("PkgType: parse '%s' error format", p.fullPath)
 	}
-	p.Package = p.fullPath[:i]
-	p.TypeName = p.fullPath[i+1:]
+	p.Package = name[:i]
+	p.TypeName = name[i+1:]
+	p.TypeArgs = args
 	return nil
 }
 
+func (p *PkgType) String() string {
+	return p.fullPath
+}
+
 func newPkgType(fullPath string) (*PkgType, error) {
 	pt := &PkgType{fullPath: fullPath}
 	err := pt.parse()
@@ -32,20 +46,139 @@ func newPkgType(fullPath string) (*PkgType, error) {
 	return pt, nil
 }
 
-func replacePkg(fullPath string, imports map[string]string) string {
-	if isBuiltinType(fullPath) {
-		return fullPath
+// replacePkg replace the package name of type name by the import path
+//
+//	[]model.User => []github.com/x/model.User
+//	Resp[model.User] => github.com/x/api.Resp[github.com/x/model.User]
+func replacePkg(typeName string, imports map[string]string) (string, error) {
+	if isBuiltinType(typeName) {
+		return typeName, nil
+	}
+	expr, err := parser.ParseExpr(typeName)
+	if err != nil {
+		return "", fmt.Errorf("invalid type '%s'", typeName)
+	}
+	goType := baseType(expr)
+	resolveType(goType, imports)
+	if goType.NotSupport || goType.IsMap {
+		return "", fmt.Errorf("type %s is not support", typeName)
+	}
+	return typeID(goType), nil
+}
+
+// typeID returns the object id of go type
+func typeID(goType *GoType) string {
+	id := goType.TypeName
+	if goType.IsMap {
+		id = "map[" + goType.MapKey + "]" + typeID(goType.MapValue)
+	} else if !goType.IsBuiltin {
+		id = refID(goType)
+	}
+	return strings.Repeat("[]", goType.ArrayDepth) + id
+}
+
+// refID returns the object id of a struct type without array
+func refID(goType *GoType) string {
+	id := goType.ImportPkgName + "." + goType.TypeName
+	if len(goType.TypeArgs) == 0 {
+		return id
+	}
+	args := make([]string, 0, len(goType.TypeArgs))
+	for _, arg := range goType.TypeArgs {
+		args = append(args, typeID(arg))
+	}
+	return id + "[" + strings.Join(args, ",") + "]"
+}
+
+// parseTypeID parse the object id to go type,it's the reverse of typeID
+func parseTypeID(id string) (*GoType, error) {
+	depth := 0
+	for strings.HasPrefix(id, "[]") {
+		id = id[2:]
+		depth++
 	}
-	var i int
-	for i = 0; i < len(fullPath); i += 2 {
-		if fullPath[i] != '[' {
-			break
+	var goType *GoType
+	switch {
+	case isBuiltinType(id):
+		goType = &GoType{TypeName: id, IsBuiltin: true}
+	case strings.HasPrefix(id, "map["):
+		end := closeBracket(id, len("map"))
+		if end == -1 {
+			return nil, fmt.Errorf("invalid type id '%s'", id)
+		}
+		value, err := parseTypeID(id[end+1:])
+		if err != nil {
+			return nil, err
+		}
+		goType = &GoType{TypeName: "map", IsMap: true, MapKey: id[len("map["):end], MapValue: value}
+	default:
+		pt, err := newPkgType(id)
+		if err != nil {
+			return nil, err
+		}
+		goType = &GoType{
+			TypeName:      pt.TypeName,
+			PkgName:       path.Base(pt.Package),
+			ImportPkgName: pt.Package,
+			IsRef:         true,
+		}
+		for _, arg := range pt.TypeArgs {
+			argType, err := parseTypeID(arg)
+			if err != nil {
+				return nil, err
+			}
+			goType.TypeArgs = append(goType.TypeArgs, argType)
 		}
 	}
-	dot := strings.LastIndex(fullPath, ".")
-	if dot == -1 {
-		return fullPath[:i] + imports[""] + "." + fullPath[i:]
+	goType.ArrayDepth = depth
+	goType.IsArray = depth > 0
+	return goType, nil
+}
+
+// splitTypeArgs split the type arguments of a generic type
+//
+//	a.Pair[a.K,map[string]a.V] => a.Pair,[a.K map[string]a.V]
+func splitTypeArgs(id string) (string, []string, error) {
+	start := strings.Index(id, "[")
+	if start == -1 {
+		return id, nil, nil
+	}
+	if closeBracket(id, start) != len(id)-1 {
+		return "", nil, fmt.Errorf("invalid type id '%s'", id)
+	}
+	var args []string
+	depth := 0
+	last := start + 1
+	for i := start + 1; i < len(id)-1; i++ {
+		switch id[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				args = append(args, id[last:i])
+				last = i + 1
+			}
+		}
+	}
+	args = append(args, id[last:len(id)-1])
+	return id[:start], args, nil
+}
+
+// closeBracket returns the index of the bracket which close the bracket at start
+func closeBracket(s string, start int) int {
+	depth := 0
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
 	}
-	pkg := fullPath[i:dot]
-	return fullPath[:i] + imports[pkg] + fullPath[dot:]
+	return -1
 }
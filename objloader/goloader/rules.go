@@ -0,0 +1,44 @@
+package goloader
+
+import "github.com/thewinds/mkdoc"
+
+// addRules add the validation rules to the fields of object,the field types
+// must be loaded to know whether the bounds limit the value or the length
+func (g *GoLoader) addRules(obj *mkdoc.Object) {
+	if obj == nil {
+		return
+	}
+	for _, field := range obj.Fields {
+		goTag := mkdoc.FindExtension[*mkdoc.ExtensionGoTag](field.Extensions)
+		if goTag == nil || mkdoc.FindExtension[*mkdoc.ExtensionRules](field.Extensions) != nil {
+			continue
+		}
+		if rules := mkdoc.NewExtensionRules(goTag.Tag, g.isNumeric(field.Type)); rules != nil {
+			field.Extensions = append(field.Extensions, rules)
+		}
+	}
+}
+
+// isNumeric report whether the type is a number,the named types are resolved
+func (g *GoLoader) isNumeric(typ *mkdoc.ObjectType) bool {
+	// limit the depth of reference
+	for i := 0; i < 16; i++ {
+		if typ.IsRepeated || typ.MapKey != "" {
+			return false
+		}
+		if typ.Ref == "" {
+			switch typ.Name {
+			case "byte", "int", "int8", "int16", "int32", "int64",
+				"uint", "uint8", "uint16", "uint32", "uint64", "float", "float32", "float64":
+				return true
+			}
+			return false
+		}
+		obj := g.loadCache(typ.Ref)
+		if obj == nil {
+			return false
+		}
+		typ = obj.Type
+	}
+	return false
+}
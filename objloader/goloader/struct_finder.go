@@ -16,12 +16,25 @@ type GoStructField struct {
 	DocComment string
 	Tag        string
 	GoType     *GoType
+	Embedded   bool
 }
 
 // GoStructInfo some useful info of go struct
+//
+// Underlying is the underlying type if that is not a struct,
+// Enums are the constants of the type if the underlying type is builtin,
+// Directive is the type declared by `@mkdoc:type` in the doc comment,
+// Marshaler is the method which changes the json encoding,eg. MarshalJSON
+// Promoted report whether the Marshaler is promoted from an embedded field
 type GoStructInfo struct {
-	Name   string
-	Fields []*GoStructField
+	Name       string
+	Fields     []*GoStructField
+	IsStruct   bool
+	Underlying *GoType
+	Enums      []*mkdoc.EnumValue
+	Directive  string
+	Marshaler  string
+	Promoted   bool
 }
 
 var errGoStructNotFound = errors.New("go struct not found")
@@ -36,6 +49,7 @@ func newStructFinder(mod *mkdoc.GoModuleInfo) *StructFinder {
 
 type walkCtx struct {
 	structName string
+	typeArgs   []*GoType
 	pkg        *ast.Package
 	fileset    *token.FileSet
 	finder     *StructFinder
@@ -43,9 +57,12 @@ type walkCtx struct {
 	err        error
 }
 
-func (s *StructFinder) Find(pkgDir string, structName string) (*GoStructInfo, error) {
+// Find the struct in package dir,the type parameters of generic struct are
+// substituted by type args,or interface{} if type args are not given
+func (s *StructFinder) Find(pkgDir string, structName string, typeArgs ...*GoType) (*GoStructInfo, error) {
 	ctx := &walkCtx{
 		structName: structName,
+		typeArgs:   typeArgs,
 		finder:     s,
 		result:     new(GoStructInfo),
 	}
@@ -65,7 +82,7 @@ func (s *StructFinder) Find(pkgDir string, structName string) (*GoStructInfo, er
 		ast.Inspect(pkg, s.genWalkForStruct(ctx))
 	}
 	if ctx.err != nil {
-		return nil, err
+		return nil, ctx.err
 	}
 	if ctx.result.Name == "" {
 		return nil, errGoStructNotFound
@@ -87,11 +104,19 @@ func (s *StructFinder) genWalkForStruct(ctx *walkCtx) func(node ast.Node) bool {
 
 func (s *StructFinder) walkTypeSpec(spec *ast.TypeSpec, ctx *walkCtx) {
 	ctx.result.Name = spec.Name.Name
+	params, err := typeParams(spec, ctx.typeArgs)
+	if err != nil {
+		ctx.err = err
+		return
+	}
+	imports := mkdoc.GetFileImportsAtNode(spec, ctx.pkg, ctx.fileset, s.mod)
+	ctx.result.Directive = typeDirective(typeDoc(astFiles(ctx.pkg), func(ts *ast.TypeSpec) bool { return ts == spec }))
+	ctx.result.Marshaler = astMarshaler(ctx.pkg, spec.Name.Name)
 	switch t := spec.Type.(type) {
 	case *ast.StructType:
+		ctx.result.IsStruct = true
 		fields := t.Fields
 		for _, field := range fields.List {
-			name := field.Names[0].Name
 			var comment string
 
 			if field.Comment != nil && len(field.Comment.List) != 0 {
@@ -99,26 +124,49 @@ func (s *StructFinder) walkTypeSpec(spec *ast.TypeSpec, ctx *walkCtx) {
 			}
 
 			baseTyp := baseType(field.Type)
-			imports := mkdoc.GetFileImportsAtNode(spec, ctx.pkg, ctx.fileset, s.mod)
-			baseTyp.ImportPkgName = imports[baseTyp.PkgName]
-			baseTyp.IsBuiltin = isBuiltinType(baseTyp.TypeName)
-			structField := &GoStructField{
-				Name:       name,
-				Comment:    comment,
-				DocComment: field.Doc.Text(),
-				GoType:     baseTyp,
-			}
-			structField.DocComment = strings.TrimRight(structField.DocComment, "\n")
+			resolveType(baseTyp, imports)
+			baseTyp = substitute(baseTyp, params)
+			var tag string
 			if field.Tag != nil {
-				structField.Tag = field.Tag.Value
+				tag = field.Tag.Value
 			}
-			ctx.result.Fields = append(ctx.result.Fields, structField)
+			docComment := strings.TrimRight(field.Doc.Text(), "\n")
 
+			// embedded field is named by the type name
+			if len(field.Names) == 0 {
+				ctx.result.Fields = append(ctx.result.Fields, &GoStructField{
+					Name:       baseTyp.TypeName,
+					Comment:    comment,
+					DocComment: docComment,
+					Tag:        tag,
+					GoType:     baseTyp,
+					Embedded:   true,
+				})
+				continue
+			}
+			for _, name := range field.Names {
+				ctx.result.Fields = append(ctx.result.Fields, &GoStructField{
+					Name:       name.Name,
+					Comment:    comment,
+					DocComment: docComment,
+					Tag:        tag,
+					GoType:     baseTyp,
+				})
+			}
 		}
-	case *ast.Ident:
-		ctx.result.Name = t.Name
 	default:
-		fmt.Printf("WARNING: only support `type <TypeName> <StructName>` go syntax,plase check %s \n", ctx.result.Name)
+		// named non struct types and aliases are resolved to the underlying type
+		underlying := baseType(spec.Type)
+		resolveType(underlying, imports)
+		underlying = substitute(underlying, params)
+		if underlying.NotSupport {
+			fmt.Printf("WARNING: type %s is not support,please check it \n", ctx.result.Name)
+			return
+		}
+		ctx.result.Underlying = underlying
+		if underlying.IsBuiltin && !underlying.IsArray {
+			ctx.result.Enums = astEnums(ctx.pkg, spec.Name.Name)
+		}
 	}
 }
 
@@ -132,6 +180,10 @@ type GoType struct {
 	ImportPkgName string
 	NotSupport    bool
 	IsBuiltin     bool
+	IsMap         bool
+	MapKey        string
+	MapValue      *GoType
+	TypeArgs      []*GoType
 }
 
 // Location return the location info of go type
@@ -163,6 +215,111 @@ func baseType(x ast.Expr) *GoType {
 		return bt
 	case *ast.InterfaceType:
 		return &GoType{TypeName: "interface{}"}
+	case *ast.MapType:
+		return mapType(baseType(t.Key), baseType(t.Value))
+	case *ast.IndexExpr:
+		return instanceType(t.X, []ast.Expr{t.Index})
+	case *ast.IndexListExpr:
+		return instanceType(t.X, t.Indices)
 	}
 	return &GoType{NotSupport: true}
 }
+
+// instanceType returns the go type of an instantiated generic type
+func instanceType(x ast.Expr, indices []ast.Expr) *GoType {
+	bt := baseType(x)
+	if bt.IsArray || bt.IsMap {
+		return &GoType{NotSupport: true}
+	}
+	for _, index := range indices {
+		arg := baseType(index)
+		bt.NotSupport = bt.NotSupport || arg.NotSupport
+		bt.TypeArgs = append(bt.TypeArgs, arg)
+	}
+	return bt
+}
+
+// typeParams returns the type arguments of the type parameters
+func typeParams(spec *ast.TypeSpec, typeArgs []*GoType) (map[string]*GoType, error) {
+	if spec.TypeParams == nil {
+		if len(typeArgs) != 0 {
+			return nil, fmt.Errorf("type %s is not generic", spec.Name.Name)
+		}
+		return nil, nil
+	}
+	var names []string
+	for _, field := range spec.TypeParams.List {
+		for _, name := range field.Names {
+			names = append(names, name.Name)
+		}
+	}
+	if len(typeArgs) != 0 && len(typeArgs) != len(names) {
+		return nil, fmt.Errorf("type %s want %d type arguments,got %d", spec.Name.Name, len(names), len(typeArgs))
+	}
+	params := make(map[string]*GoType)
+	for i, name := range names {
+		if len(typeArgs) == 0 {
+			params[name] = &GoType{TypeName: "interface{}", IsBuiltin: true}
+			continue
+		}
+		params[name] = typeArgs[i]
+	}
+	return params, nil
+}
+
+// substitute replace the type parameters of go type by the type arguments
+func substitute(goType *GoType, params map[string]*GoType) *GoType {
+	if len(params) == 0 {
+		return goType
+	}
+	if goType.IsMap {
+		t := *goType
+		t.MapValue = substitute(goType.MapValue, params)
+		return &t
+	}
+	if arg := params[goType.TypeName]; arg != nil && goType.PkgName == "" && len(goType.TypeArgs) == 0 {
+		t := *arg
+		t.ArrayDepth += goType.ArrayDepth
+		t.IsArray = t.ArrayDepth > 0
+		return &t
+	}
+	if len(goType.TypeArgs) == 0 {
+		return goType
+	}
+	t := *goType
+	t.TypeArgs = nil
+	for _, arg := range goType.TypeArgs {
+		t.TypeArgs = append(t.TypeArgs, substitute(arg, params))
+	}
+	return &t
+}
+
+// mapType returns the go type of map,
+// json object keys are always string so the named key types are treated as string
+func mapType(key *GoType, value *GoType) *GoType {
+	keyName := "string"
+	if !key.IsArray && !key.IsMap && isBuiltinType(key.TypeName) && key.TypeName != "interface{}" {
+		keyName = key.TypeName
+	}
+	return &GoType{
+		TypeName:   "map",
+		IsMap:      true,
+		MapKey:     keyName,
+		MapValue:   value,
+		NotSupport: value.NotSupport,
+	}
+}
+
+// resolveType set the import path of the type,the map value type and the type arguments
+func resolveType(goType *GoType, imports map[string]string) {
+	if goType.IsMap {
+		resolveType(goType.MapValue, imports)
+		goType.NotSupport = goType.MapValue.NotSupport
+		return
+	}
+	goType.ImportPkgName = imports[goType.PkgName]
+	goType.IsBuiltin = isBuiltinType(goType.TypeName) && len(goType.TypeArgs) == 0
+	for _, arg := range goType.TypeArgs {
+		resolveType(arg, imports)
+	}
+}
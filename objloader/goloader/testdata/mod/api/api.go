@@ -0,0 +1,9 @@
+package api
+
+import (
+	. "example.com/mod/model"
+	m "example.com/mod/model"
+)
+
+var _ m.User
+var _ Profile
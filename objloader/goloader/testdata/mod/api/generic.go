@@ -0,0 +1,17 @@
+package api
+
+type Result[T any] struct {
+	Code int     `json:"code"`
+	Data T       `json:"data"`
+	List []T     `json:"list"`
+	Page Page[T] `json:"page"`
+}
+
+type Page[T any] struct {
+	Items map[string]T `json:"items"`
+}
+
+type Pair[K comparable, V any] struct {
+	Key   K  `json:"key"`
+	Value *V `json:"value"`
+}
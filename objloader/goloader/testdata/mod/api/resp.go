@@ -0,0 +1,8 @@
+package api
+
+import "example.com/mod/model"
+
+type Resp struct {
+	*model.BaseView
+	Data string `json:"data"`
+}
@@ -0,0 +1,35 @@
+package model
+
+// Level of user
+type Level int
+
+const (
+	// normal user
+	LevelNormal Level = iota + 1
+	// vip user
+	LevelVIP
+	LevelAdmin // administrator
+	levelMax
+)
+
+// Kind of account
+const KindPersonal Kind = "personal"
+
+type Kind string
+
+const (
+	KindTeam, KindOrg Kind = "team", "org"
+)
+
+type ID = string
+
+type Tags []string
+
+type Account struct {
+	ID     ID      `json:"id"`
+	Level  Level   `json:"level"`
+	Kind   Kind    `json:"kind"`
+	Tags   Tags    `json:"tags"`
+	Owner  Member  `json:"owner"`
+	Levels []Level `json:"levels"`
+}
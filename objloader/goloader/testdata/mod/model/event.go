@@ -0,0 +1,31 @@
+package model
+
+import (
+	"database/sql"
+	"encoding/json"
+	"math/big"
+	"time"
+)
+
+// Money is mapped to string by the config
+type Money struct {
+	units int64
+	nanos int32
+}
+
+// Event is encoded as the embedded time
+type Event struct {
+	time.Time
+}
+
+type Schedule struct {
+	Event    Event           `json:"event"`
+	EndAt    *time.Time      `json:"end_at"`
+	Duration time.Duration   `json:"duration"`
+	Remind   []time.Time     `json:"remind"`
+	Remark   sql.NullString  `json:"remark"`
+	Payload  json.RawMessage `json:"payload"`
+	Price    Money           `json:"price"`
+	Rate     *big.Float      `json:"rate"`
+	Deleted  sql.NullTime    `json:"deleted"`
+}
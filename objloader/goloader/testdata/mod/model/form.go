@@ -0,0 +1,17 @@
+package model
+
+type FormMeta struct {
+	Labels map[string]string `json:"labels"`
+}
+
+type CreateReq struct {
+	Name string `json:"name" binding:"required,min=2,max=32" example:"alice"`
+	Age  int    `json:"age,omitempty,string" validate:"min=1"`
+	// 邮箱
+	// @example alice@example.com
+	Email string   `json:"email,omitempty" validate:"required,email"`
+	Meta  FormMeta `json:",inline"`
+	Color string   `json:"color" validate:"oneof=red green 'light blue'"`
+	Level Level    `json:"level" validate:"gt=0,lte=3"`
+	Tags  []string `json:"tags" validate:"max=5,dive,min=1"`
+}
@@ -0,0 +1,7 @@
+package model
+
+type Group struct {
+	Members map[string]*User `json:"members"`
+	Scores  map[int64][]int  `json:"scores"`
+	Labels  []map[string]string
+}
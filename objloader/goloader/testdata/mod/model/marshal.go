@@ -0,0 +1,86 @@
+package model
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+// OrderStatus is encoded as string by MarshalText
+type OrderStatus struct {
+	code int
+}
+
+func (s OrderStatus) MarshalText() ([]byte, error) {
+	return []byte(strconv.Itoa(s.code)), nil
+}
+
+// Point is encoded as [x,y] which is unknown
+type Point struct {
+	X float64
+	Y float64
+}
+
+func (p *Point) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]float64{p.X, p.Y})
+}
+
+// Date is encoded as 2006-01-02
+// @mkdoc:type string(date)
+type Date struct {
+	Year  int
+	Month int
+	Day   int
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strconv.Itoa(d.Year) + "-" + strconv.Itoa(d.Month) + "-" + strconv.Itoa(d.Day))
+}
+
+type (
+	// Version is declared as int
+	//
+	// @mkdoc:type int
+	Version struct {
+		Major int
+		Minor int
+	}
+)
+
+// Amount is encoded by MarshalJSON which is unknown
+type Amount int64
+
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]int64{"cents": int64(a)})
+}
+
+// Grade is encoded as string by MarshalText
+type Grade int
+
+func (g Grade) MarshalText() ([]byte, error) {
+	return []byte("grade-" + strconv.Itoa(int(g))), nil
+}
+
+// Shipment is encoded by the promoted MarshalText
+type Shipment struct {
+	OrderStatus
+	Carrier string
+}
+
+// Anniversary is declared as the embedded date
+type Anniversary struct {
+	*Date
+	Note string
+}
+
+type Order struct {
+	Status      OrderStatus `json:"status"`
+	Location    *Point      `json:"location"`
+	Path        []Point     `json:"path"`
+	Birthday    Date        `json:"birthday"`
+	Version     Version     `json:"version"`
+	Profile     *Profile    `json:"profile"`
+	Shipment    Shipment    `json:"shipment"`
+	Anniversary Anniversary `json:"anniversary"`
+	Amount      Amount      `json:"amount"`
+	Grade       Grade       `json:"grade"`
+}
@@ -0,0 +1,9 @@
+package model
+
+import "sync"
+
+// Record embeds a type which is not in the module
+type Record struct {
+	sync.Mutex
+	Name string `json:"name"`
+}
@@ -0,0 +1,25 @@
+package model
+
+type User struct {
+	// user id
+	ID         int64  `json:"id"`
+	Name, Nick string // name and nick name
+	Tags       []string
+	Profile    *Profile `json:"profile"`
+	Status     Status
+	Base
+	password string
+}
+
+type Profile struct {
+	// age of user
+	Age int `json:"age"`
+}
+
+type Base struct {
+	CreatedAt int64 `json:"created_at"`
+}
+
+type Status int
+
+type Member = User
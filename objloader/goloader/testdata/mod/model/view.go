@@ -0,0 +1,38 @@
+package model
+
+type BaseView struct {
+	ID        int64 `json:"id"`
+	CreatedAt int64 `json:"created_at"`
+}
+
+type Meta struct {
+	Version int `json:"version"`
+}
+
+type OtherMeta struct {
+	Version int `json:"version"`
+	Source  string
+}
+
+type Extra struct {
+	Note string `json:"note"`
+}
+
+type UserView struct {
+	BaseView
+	*Meta
+	Extra `json:"extra"`
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+}
+
+// Conflict has two version fields at the same depth
+type Conflict struct {
+	Meta
+	OtherMeta
+}
+
+type Node struct {
+	*Node
+	Value int `json:"value"`
+}
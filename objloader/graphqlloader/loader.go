@@ -0,0 +1,12 @@
+package graphqlloader
+
+import (
+	"github.com/thewinds/mkdoc"
+	"github.com/thewinds/mkdoc/objloader/preloader"
+)
+
+// the objects are scanned from GraphQL SDL,
+// named types use the type name as id,eg. `User`
+func init() {
+	mkdoc.RegisterObjectLoader(preloader.NewLoader("graphql"))
+}
@@ -0,0 +1,12 @@
+package jsonloader
+
+import (
+	"github.com/thewinds/mkdoc"
+	"github.com/thewinds/mkdoc/objloader/preloader"
+)
+
+// the objects are inferred from json examples by scanners,
+// eg. the body examples of postman collections
+func init() {
+	mkdoc.RegisterObjectLoader(preloader.NewLoader("json"))
+}
@@ -0,0 +1,42 @@
+package openapiloader
+
+import (
+	"github.com/thewinds/mkdoc"
+	"github.com/thewinds/mkdoc/objloader/preloader"
+	"path/filepath"
+	"strings"
+)
+
+// the objects are scanned from OpenAPI / Swagger specs,
+// `$ref` are resolved to object id by scanner
+func init() {
+	mkdoc.RegisterObjectLoader(preloader.NewLoader("openapi"))
+}
+
+// ObjectID get the object id of a json reference in spec file
+// the id is made up of spec file path relative to root and schema name,
+// so the same file name in different directories won't conflict
+//
+//	#/components/schemas/Pet in petstore.yaml => petstore.Pet
+//	common.yaml#/definitions/Error in a/pet.yaml => a/common.Error
+func ObjectID(root string, fileName string, ref string) string {
+	i := strings.Index(ref, "#")
+	if i > 0 {
+		fileName = filepath.Join(filepath.Dir(fileName), ref[:i])
+	}
+	name := ref[strings.LastIndex(ref, "/")+1:]
+	name = strings.Replace(name, "~1", "/", -1)
+	name = strings.Replace(name, "~0", "~", -1)
+	return SpecName(root, fileName) + "." + name
+}
+
+// SpecName returns the spec file path relative to root without extension
+//
+//	/x/specs/a/common.yaml in /x/specs => a/common
+func SpecName(root string, fileName string) string {
+	rel, err := filepath.Rel(root, fileName)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		rel = filepath.Base(fileName)
+	}
+	return filepath.ToSlash(strings.TrimSuffix(rel, filepath.Ext(rel)))
+}
@@ -0,0 +1,96 @@
+package preloader
+
+import (
+	"errors"
+	"fmt"
+	"github.com/thewinds/mkdoc"
+	"sync"
+)
+
+// Loader load objects which are pre-registered by scanners,
+// eg. the objects inferred from postman examples or converted from OpenAPI specs
+// all the objects are added by scanner,the loader only look up them by id
+type Loader struct {
+	lang      string
+	config    *mkdoc.ObjectLoaderConfig
+	cached    map[string]*mkdoc.Object
+	initialed bool
+	once      sync.Once
+	mu        sync.Mutex
+}
+
+// NewLoader returns a loader of the language
+func NewLoader(lang string) *Loader {
+	return &Loader{lang: lang}
+}
+
+func (l *Loader) init(config *mkdoc.ObjectLoaderConfig) {
+	l.once.Do(func() {
+		l.config = config
+		l.cached = make(map[string]*mkdoc.Object)
+		l.initialed = true
+	})
+}
+
+func (l *Loader) Add(object *mkdoc.Object) error {
+	if !l.initialed {
+		return errors.New("loader not initialed")
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.cached[object.ID] = object
+	return nil
+}
+
+func (l *Loader) GetObjectId(ts mkdoc.TypeScope) (string, error) {
+	if !l.initialed {
+		return "", errors.New("loader not initialed")
+	}
+	return ts.TypeName, nil
+}
+
+func (l *Loader) LoadAll(tss []mkdoc.TypeScope) ([]*mkdoc.Object, error) {
+	if !l.initialed {
+		return nil, errors.New("loader not initialed")
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, ts := range tss {
+		id, err := l.GetObjectId(ts)
+		if err != nil {
+			return nil, err
+		}
+		if l.cached[id] == nil {
+			return nil, fmt.Errorf("type scope %v: type %s not found", ts, id)
+		}
+	}
+	var r []*mkdoc.Object
+	for _, object := range l.cached {
+		r = append(r, object)
+	}
+	return r, nil
+}
+
+func (l *Loader) Load(ts mkdoc.TypeScope) (*mkdoc.Object, error) {
+	if !l.initialed {
+		return nil, errors.New("loader not initialed")
+	}
+	if _, err := l.LoadAll([]mkdoc.TypeScope{ts}); err != nil {
+		return nil, err
+	}
+	id, err := l.GetObjectId(ts)
+	if err != nil {
+		return nil, err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.cached[id], nil
+}
+
+func (l *Loader) SetConfig(cfg *mkdoc.ObjectLoaderConfig) {
+	l.init(cfg)
+}
+
+func (l *Loader) Lang() string {
+	return l.lang
+}
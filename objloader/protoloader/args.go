@@ -0,0 +1,8 @@
+package protoloader
+
+const (
+	// ProtoPath directories of .proto files split by ',',use `path` if not set
+	ProtoPath = "proto_path"
+	// ProtoOrigName use the original field name as json name instead of lowerCamelCase
+	ProtoOrigName = "proto_orig_name"
+)
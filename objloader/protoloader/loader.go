@@ -0,0 +1,432 @@
+package protoloader
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/thewinds/mkdoc"
+	"github.com/thewinds/mkdoc/schema"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+func init() {
+	mkdoc.RegisterObjectLoader(new(Loader))
+}
+
+// Loader load objects from the messages defined in .proto files
+// type name is the full name of message,eg. `proto:user.v1.User`,
+// the package can be omitted if the message name is unique
+type Loader struct {
+	config   *mkdoc.ObjectLoaderConfig
+	cached   map[string]*mkdoc.Object
+	messages map[string]*Message
+	enums    map[string]*Enum
+	paths    []string
+	origName bool
+	// parsed .proto files and dirs
+	parsed    map[string]bool
+	initialed bool
+	once      sync.Once
+	mu        sync.Mutex
+}
+
+func (l *Loader) init(config *mkdoc.ObjectLoaderConfig) {
+	l.once.Do(func() {
+		l.config = config
+		path := config.Args[ProtoPath]
+		if path == "" {
+			path = config.Args["path"]
+		}
+		for _, p := range strings.Split(path, ",") {
+			if p = strings.TrimSpace(p); p != "" {
+				l.paths = append(l.paths, p)
+			}
+		}
+		l.origName = config.Args[ProtoOrigName] == "true"
+		l.cached = make(map[string]*mkdoc.Object)
+		for _, object := range builtinObjects() {
+			l.cached[object.ID] = object
+		}
+		l.initialed = true
+	})
+}
+
+// parse all the .proto files in proto paths and dirs
+func (l *Loader) parse(dirs ...string) error {
+	if l.parsed == nil {
+		l.parsed = make(map[string]bool)
+		l.messages = make(map[string]*Message)
+		l.enums = make(map[string]*Enum)
+	}
+	for _, dir := range append(l.paths, dirs...) {
+		absDir, err := filepath.Abs(dir)
+		if err != nil {
+			return err
+		}
+		if l.parsed[absDir] {
+			continue
+		}
+		l.parsed[absDir] = true
+		err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() || filepath.Ext(path) != ".proto" {
+				return nil
+			}
+			absPath, err := filepath.Abs(path)
+			if err != nil {
+				return err
+			}
+			if l.parsed[absPath] {
+				return nil
+			}
+			l.parsed[absPath] = true
+			f, err := ParseFile(path)
+			if err != nil {
+				return err
+			}
+			l.addMessages(f.Messages)
+			l.addEnums(f.Enums)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *Loader) addMessages(messages []*Message) {
+	for _, msg := range messages {
+		l.messages[msg.FullName] = msg
+		l.addMessages(msg.Messages)
+		l.addEnums(msg.Enums)
+	}
+}
+
+func (l *Loader) addEnums(enums []*Enum) {
+	for _, enum := range enums {
+		l.enums[enum.FullName] = enum
+	}
+}
+
+func (l *Loader) Add(object *mkdoc.Object) error {
+	if !l.initialed {
+		return errors.New("loader not initialed")
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.cached[object.ID] = object
+	return nil
+}
+
+func (l *Loader) GetObjectId(ts mkdoc.TypeScope) (string, error) {
+	if !l.initialed {
+		return "", errors.New("loader not initialed")
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.getObjectId(ts)
+}
+
+func (l *Loader) getObjectId(ts mkdoc.TypeScope) (string, error) {
+	if strings.HasPrefix(ts.TypeName, "@") {
+		return ts.TypeName, nil
+	}
+	// the dir of .proto file is also parsed if the api is scanned from it
+	var dirs []string
+	if filepath.Ext(ts.FileName) == ".proto" {
+		dirs = append(dirs, filepath.Dir(ts.FileName))
+	}
+	if err := l.parse(dirs...); err != nil {
+		return "", err
+	}
+	name := strings.TrimLeft(ts.TypeName, "[]")
+	arr := ts.TypeName[:len(ts.TypeName)-len(name)]
+	fullName, err := l.lookup(name)
+	if err != nil {
+		return "", fmt.Errorf("type scope %v: %v", ts, err)
+	}
+	return arr + fullName, nil
+}
+
+// lookup the full name of a message or enum,
+// returns the only one which has the same suffix if name is not a full name
+func (l *Loader) lookup(name string) (string, error) {
+	name = strings.TrimPrefix(name, ".")
+	if l.exists(name) {
+		return name, nil
+	}
+	var found []string
+	for fullName := range l.messages {
+		if strings.HasSuffix(fullName, "."+name) {
+			found = append(found, fullName)
+		}
+	}
+	for fullName := range l.enums {
+		if strings.HasSuffix(fullName, "."+name) {
+			found = append(found, fullName)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return "", fmt.Errorf("proto type %s not found", name)
+	case 1:
+		return found[0], nil
+	}
+	sort.Strings(found)
+	return "", fmt.Errorf("proto type %s is ambiguous: %s", name, strings.Join(found, ","))
+}
+
+func (l *Loader) exists(fullName string) bool {
+	if _, ok := wellKnownTypes[fullName]; ok {
+		return true
+	}
+	return l.messages[fullName] != nil || l.enums[fullName] != nil
+}
+
+// resolve type name referenced in scope,like protoc the innermost scope first
+func (l *Loader) resolve(scope, name string) string {
+	if strings.HasPrefix(name, ".") {
+		return name[1:]
+	}
+	for scope != "" {
+		if fullName := scope + "." + name; l.exists(fullName) {
+			return fullName
+		}
+		i := strings.LastIndex(scope, ".")
+		if i == -1 {
+			break
+		}
+		scope = scope[:i]
+	}
+	return name
+}
+
+func (l *Loader) LoadAll(tss []mkdoc.TypeScope) ([]*mkdoc.Object, error) {
+	if !l.initialed {
+		return nil, errors.New("loader not initialed")
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, ts := range tss {
+		if strings.HasPrefix(ts.TypeName, "@") {
+			if l.cached[ts.TypeName] == nil {
+				return nil, fmt.Errorf("type scope %v: type not found", ts)
+			}
+			continue
+		}
+		id, err := l.getObjectId(ts)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := l.loadType(strings.TrimLeft(id, "[]")); err != nil {
+			return nil, err
+		}
+		objs, err := mkdoc.CreateRootObject(id, l.loadCache)
+		if err != nil {
+			return nil, err
+		}
+		objs[len(objs)-1].ID = id
+		for _, obj := range objs {
+			l.cached[obj.ID] = obj
+		}
+	}
+	var r []*mkdoc.Object
+	for _, object := range l.cached {
+		r = append(r, object)
+	}
+	return r, nil
+}
+
+func (l *Loader) loadCache(id string) *mkdoc.Object {
+	return l.cached[id]
+}
+
+// loadType load the object of message,enum or well known type by full name
+func (l *Loader) loadType(fullName string) (*mkdoc.Object, error) {
+	if obj := l.cached[fullName]; obj != nil {
+		return obj, nil
+	}
+	if wkt, ok := wellKnownTypes[fullName]; ok {
+		obj := &mkdoc.Object{ID: fullName, Type: &mkdoc.ObjectType{Name: wkt.typ}, Loaded: true}
+		l.cached[fullName] = obj
+		return obj, nil
+	}
+	if enum := l.enums[fullName]; enum != nil {
+		obj := &mkdoc.Object{ID: fullName, Type: &mkdoc.ObjectType{Name: "string"}, Loaded: true}
+		l.cached[fullName] = obj
+		return obj, nil
+	}
+	msg := l.messages[fullName]
+	if msg == nil {
+		return nil, fmt.Errorf("proto type %s not found", fullName)
+	}
+	obj := &mkdoc.Object{
+		ID:     fullName,
+		Type:   &mkdoc.ObjectType{Name: "object"},
+		Fields: make([]*mkdoc.ObjectField, 0, len(msg.Fields)),
+		Loaded: true,
+	}
+	// cache before loading fields,the message may reference itself
+	l.cached[fullName] = obj
+	for _, f := range msg.Fields {
+		field, err := l.loadField(msg, f)
+		if err != nil {
+			return nil, err
+		}
+		obj.Fields = append(obj.Fields, field)
+	}
+	return obj, nil
+}
+
+func (l *Loader) loadField(msg *Message, f *Field) (*mkdoc.ObjectField, error) {
+	tagExt, err := goTag(l.jsonName(f))
+	if err != nil {
+		return nil, err
+	}
+	var (
+		typ  *mkdoc.ObjectType
+		hint string
+	)
+	if f.MapKey != "" {
+		typ, hint, err = l.mapType(msg, f)
+	} else {
+		typ, hint, err = l.fieldType(msg.FullName, f.Type)
+		if err == nil && f.Repeated {
+			typ = l.arrayType(typ)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("message %s field %s: %v", msg.FullName, f.Name, err)
+	}
+	var desc []string
+	if f.Comment != "" {
+		desc = append(desc, strings.Replace(f.Comment, "\n", " ", -1))
+	}
+	if hint != "" {
+		desc = append(desc, hint)
+	}
+	if f.Oneof != "" {
+		desc = append(desc, "oneof "+f.Oneof)
+	}
+	return &mkdoc.ObjectField{
+		Name:       f.Name,
+		Desc:       strings.Join(desc, "; "),
+		Type:       typ,
+		Extensions: []mkdoc.Extension{tagExt},
+	}, nil
+}
+
+// fieldType returns the type of a non-repeated field and a hint for description
+func (l *Loader) fieldType(scope, name string) (*mkdoc.ObjectType, string, error) {
+	if t, ok := scalarTypes[name]; ok {
+		return &mkdoc.ObjectType{Name: t}, "", nil
+	}
+	fullName := l.resolve(scope, name)
+	if wkt, ok := wellKnownTypes[fullName]; ok && wkt.typ != "object" {
+		return &mkdoc.ObjectType{Name: wkt.typ}, wkt.desc, nil
+	}
+	if enum := l.enums[fullName]; enum != nil {
+		var values []string
+		for _, v := range enum.Values {
+			values = append(values, v.Name)
+		}
+		return &mkdoc.ObjectType{Name: "string"}, "enum: " + strings.Join(values, ","), nil
+	}
+	obj, err := l.loadType(fullName)
+	if err != nil {
+		return nil, "", err
+	}
+	return &mkdoc.ObjectType{Name: "object", Ref: obj.ID}, "", nil
+}
+
+// arrayType create an array object of the element type
+func (l *Loader) arrayType(elem *mkdoc.ObjectType) *mkdoc.ObjectType {
+	leaf := elem.Ref
+	if leaf == "" {
+		leaf = elem.Name
+	}
+	objs := mkdoc.CreateArrayObjectByID(leaf, 1, l.loadCache)
+	for _, obj := range objs {
+		l.cached[obj.ID] = obj
+	}
+	return &mkdoc.ObjectType{Name: "object", Ref: objs[len(objs)-1].ID}
+}
+
+// mapType create a map object of the value type,
+// json object keys are always string but the key type is kept
+func (l *Loader) mapType(msg *Message, f *Field) (*mkdoc.ObjectType, string, error) {
+	valueType, hint, err := l.fieldType(msg.FullName, f.MapValue)
+	if err != nil {
+		return nil, "", err
+	}
+	key, ok := scalarTypes[f.MapKey]
+	if !ok {
+		return nil, "", fmt.Errorf("invalid map key type %s", f.MapKey)
+	}
+	obj := mkdoc.CreateMapObject(key, valueType)
+	l.cached[obj.ID] = obj
+	return &mkdoc.ObjectType{Name: "object", Ref: obj.ID}, hint, nil
+}
+
+// jsonName returns the json name of field like protojson,
+// lowerCamelCase is used unless `json_name` is set
+func (l *Loader) jsonName(f *Field) string {
+	if l.origName {
+		return f.Name
+	}
+	if f.JSONName != "" {
+		return f.JSONName
+	}
+	var sb strings.Builder
+	upper := false
+	for _, c := range f.Name {
+		if c == '_' {
+			upper = true
+			continue
+		}
+		if upper && c >= 'a' && c <= 'z' {
+			c -= 'a' - 'A'
+		}
+		upper = false
+		sb.WriteRune(c)
+	}
+	return sb.String()
+}
+
+func goTag(jsonName string) (mkdoc.Extension, error) {
+	return new(mkdoc.ExtensionGoTag).Parse(&schema.Extension{
+		Name: "go_tag",
+		Data: json.RawMessage(fmt.Sprintf("%q", fmt.Sprintf(`json:"%s"`, jsonName))),
+	})
+}
+
+func (l *Loader) Load(ts mkdoc.TypeScope) (*mkdoc.Object, error) {
+	if !l.initialed {
+		return nil, errors.New("loader not initialed")
+	}
+	if _, err := l.LoadAll([]mkdoc.TypeScope{ts}); err != nil {
+		return nil, err
+	}
+	id, err := l.GetObjectId(ts)
+	if err != nil {
+		return nil, err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.cached[id], nil
+}
+
+func (l *Loader) SetConfig(cfg *mkdoc.ObjectLoaderConfig) {
+	l.init(cfg)
+}
+
+func (l *Loader) Lang() string {
+	return "proto"
+}
@@ -0,0 +1,78 @@
+package protoloader
+
+import (
+	"github.com/thewinds/mkdoc"
+	"testing"
+)
+
+func TestLoader_LoadAll(t *testing.T) {
+	loader := new(Loader)
+	loader.SetConfig(&mkdoc.ObjectLoaderConfig{Config: mkdoc.Config{Args: map[string]string{"path": "testdata"}}})
+	objs, err := loader.LoadAll([]mkdoc.TypeScope{{TypeName: "User"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	objects := make(map[string]*mkdoc.Object)
+	for _, obj := range objs {
+		objects[obj.ID] = obj
+	}
+	user := objects["user.v1.User"]
+	if user == nil || len(user.Fields) != 9 {
+		t.Fatalf("unexpected user %+v", user)
+	}
+	tests := []struct {
+		json string
+		typ  string
+		desc string
+	}{
+		{"id", "int64", "用户ID"},
+		{"userName", "string", "用户名"},
+		{"emails", "object", ""},
+		{"status", "string", "enum: STATUS_UNKNOWN,STATUS_ACTIVE"},
+		{"projects", "object", ""},
+		{"created", "string", "date-time (RFC 3339)"},
+		{"friends", "object", ""},
+		{"phone", "string", "oneof contact"},
+		{"address", "object", "oneof contact"},
+	}
+	for i, tt := range tests {
+		field := user.Fields[i]
+		name := field.Extensions[0].(*mkdoc.ExtensionGoTag).Tag.GetValue("json")
+		if name != tt.json || field.Type.Name != tt.typ || field.Desc != tt.desc {
+			t.Errorf("field %d: want %v got %s %s %q", i, tt, name, field.Type.Name, field.Desc)
+		}
+	}
+	if friends := objects[user.Fields[6].Type.Ref]; friends == nil || !friends.Type.IsRepeated || friends.Type.Ref != "user.v1.User" {
+		t.Errorf("unexpected friends %+v", friends)
+	}
+	if address := objects["user.v1.User.Address"]; address == nil || address.Fields[0].Name != "city" {
+		t.Errorf("unexpected address %+v", address)
+	}
+	projects := objects[user.Fields[4].Type.Ref]
+	if projects == nil || projects.Type.MapKey != "string" || projects.Type.Ref != "user.v1.Project" {
+		t.Errorf("unexpected projects %+v", projects)
+	}
+}
+
+func TestParse(t *testing.T) {
+	f, err := ParseFile("testdata/user/v1/user.proto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Package != "user.v1" || len(f.Messages) != 3 || len(f.Imports) != 1 {
+		t.Fatalf("unexpected file %+v", f)
+	}
+	user := f.Messages[0]
+	if user.Comment != "User 用户" || len(user.Messages) != 1 || len(user.Enums) != 1 {
+		t.Errorf("unexpected message %+v", user)
+	}
+	if c := user.Messages[0].Comment; c != "地址\n多行注释" {
+		t.Errorf("unexpected comment %q", c)
+	}
+	if v := user.Enums[0].Values[1]; v.Name != "STATUS_ACTIVE" || v.Number != 1 || v.Comment != "正常" {
+		t.Errorf("unexpected enum value %+v", v)
+	}
+	if m := user.Fields[4]; m.MapKey != "string" || m.MapValue != "Project" {
+		t.Errorf("unexpected map field %+v", m)
+	}
+}
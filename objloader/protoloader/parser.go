@@ -0,0 +1,915 @@
+package protoloader
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+// ProtoFile the declarations of a .proto file
+type ProtoFile struct {
+	Name     string
+	Syntax   string
+	Package  string
+	Imports  []string
+	Messages []*Message
+	Enums    []*Enum
+	Services []*Service
+}
+
+// Message a proto message,FullName contains the package and parent messages
+type Message struct {
+	Name     string
+	FullName string
+	Comment  string
+	Fields   []*Field
+	Messages []*Message
+	Enums    []*Enum
+}
+
+// Field a message field,MapKey and MapValue are not empty if it's a map field
+type Field struct {
+	Name     string
+	Type     string
+	Number   int
+	Repeated bool
+	MapKey   string
+	MapValue string
+	Oneof    string
+	JSONName string
+	Comment  string
+}
+
+type Enum struct {
+	Name     string
+	FullName string
+	Comment  string
+	Values   []*EnumValue
+}
+
+type EnumValue struct {
+	Name    string
+	Number  int
+	Comment string
+}
+
+type Service struct {
+	Name     string
+	FullName string
+	Comment  string
+	RPCs     []*RPC
+}
+
+type RPC struct {
+	Name            string
+	Comment         string
+	InputType       string
+	OutputType      string
+	ClientStreaming bool
+	ServerStreaming bool
+	// HTTPRules the bindings of `google.api.http` option
+	HTTPRules []*HTTPRule
+}
+
+// HTTPRule a http binding of rpc,Path is the url template like `/v1/{name=users/*}`
+type HTTPRule struct {
+	Method string
+	Path   string
+	Body   string
+}
+
+// ParseFile parse a .proto file
+func ParseFile(fileName string) (*ProtoFile, error) {
+	b, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	f, err := Parse(string(b))
+	if err != nil {
+		return nil, fmt.Errorf("%s:%v", fileName, err)
+	}
+	f.Name = fileName
+	return f, nil
+}
+
+// Parse parse the source of .proto file
+func Parse(src string) (*ProtoFile, error) {
+	p := &parser{tokens: tokenize(src)}
+	f := new(ProtoFile)
+	if err := p.parseFile(f); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+type tokenKind int
+
+const (
+	tokIdent tokenKind = iota
+	tokString
+	tokNumber
+	tokSymbol
+	tokComment
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	line int
+}
+
+func tokenize(src string) []*token {
+	var tokens []*token
+	line := 1
+	i := 0
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case strings.HasPrefix(src[i:], "//"):
+			end := strings.IndexByte(src[i:], '\n')
+			if end == -1 {
+				end = len(src) - i
+			}
+			tokens = append(tokens, &token{tokComment, strings.TrimSpace(src[i+2 : i+end]), line})
+			i += end
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end == -1 {
+				end = len(src) - i - 2
+			}
+			text := src[i+2 : i+2+end]
+			var lines []string
+			for _, l := range strings.Split(text, "\n") {
+				l = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(l), "*"))
+				if l != "" {
+					lines = append(lines, l)
+				}
+			}
+			tokens = append(tokens, &token{tokComment, strings.Join(lines, "\n"), line})
+			line += strings.Count(text, "\n")
+			i += end + 4
+		case c == '"' || c == '\'':
+			j := i + 1
+			for j < len(src) && src[j] != c {
+				if src[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			raw := src[i : j+1]
+			if j >= len(src) {
+				raw = src[i:] + string(c)
+			}
+			text, err := strconv.Unquote(`"` + strings.Replace(raw[1:len(raw)-1], `"`, `\"`, -1) + `"`)
+			if err != nil {
+				text = raw[1 : len(raw)-1]
+			}
+			tokens = append(tokens, &token{tokString, text, line})
+			i = j + 1
+		case isIdentChar(c) || c == '.':
+			j := i
+			for j < len(src) && (isIdentChar(src[j]) || src[j] == '.' || (j > i && (src[j] == '-' || src[j] == '+') && (src[j-1] == 'e' || src[j-1] == 'E') && isDigit(src[i]))) {
+				j++
+			}
+			kind := tokIdent
+			if isDigit(c) || (c == '.' && j > i+1 && isDigit(src[i+1])) {
+				kind = tokNumber
+			}
+			tokens = append(tokens, &token{kind, src[i:j], line})
+			i = j
+		default:
+			tokens = append(tokens, &token{tokSymbol, string(c), line})
+			i++
+		}
+	}
+	return tokens
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || isDigit(c)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+type parser struct {
+	tokens []*token
+	pos    int
+	// comments before the current declaration
+	comments []string
+}
+
+// next returns the next non comment token,comments are collected
+func (p *parser) next() *token {
+	for p.pos < len(p.tokens) {
+		t := p.tokens[p.pos]
+		p.pos++
+		if t.kind == tokComment {
+			p.comments = append(p.comments, t.text)
+			continue
+		}
+		return t
+	}
+	return nil
+}
+
+// peek returns the next non comment token without consuming it,comments are collected
+func (p *parser) peek() *token {
+	for p.pos < len(p.tokens) {
+		t := p.tokens[p.pos]
+		if t.kind != tokComment {
+			return t
+		}
+		p.comments = append(p.comments, t.text)
+		p.pos++
+	}
+	return nil
+}
+
+// trailingComment returns the comment on the same line of the last token
+func (p *parser) trailingComment() string {
+	if p.pos == 0 || p.pos >= len(p.tokens) {
+		return ""
+	}
+	last := p.tokens[p.pos-1]
+	t := p.tokens[p.pos]
+	if t.kind == tokComment && t.line == last.line {
+		p.pos++
+		return t.text
+	}
+	return ""
+}
+
+// takeComment returns the leading comments and reset
+func (p *parser) takeComment() string {
+	c := strings.Join(p.comments, "\n")
+	p.comments = nil
+	return c
+}
+
+func (p *parser) expect(text string) error {
+	t := p.next()
+	if t == nil {
+		return fmt.Errorf("expect '%s' but got EOF", text)
+	}
+	if t.text != text {
+		return fmt.Errorf("%d: expect '%s' but got '%s'", t.line, text, t.text)
+	}
+	return nil
+}
+
+func (p *parser) ident() (string, error) {
+	t := p.next()
+	if t == nil {
+		return "", fmt.Errorf("expect identifier but got EOF")
+	}
+	if t.kind != tokIdent {
+		return "", fmt.Errorf("%d: expect identifier but got '%s'", t.line, t.text)
+	}
+	return t.text, nil
+}
+
+// skipStatement skip to the end of statement,blocks are skipped
+func (p *parser) skipStatement() error {
+	depth := 0
+	for {
+		t := p.next()
+		if t == nil {
+			if depth == 0 {
+				return nil
+			}
+			return fmt.Errorf("unexpected EOF")
+		}
+		if t.kind != tokSymbol {
+			continue
+		}
+		switch t.text {
+		case "{":
+			depth++
+		case "}":
+			depth--
+			if depth == 0 {
+				if pk := p.peek(); pk != nil && pk.text == ";" {
+					p.next()
+				}
+				return nil
+			}
+		case ";":
+			if depth == 0 {
+				return nil
+			}
+		}
+	}
+}
+
+func (p *parser) parseFile(f *ProtoFile) error {
+	for {
+		t := p.peek()
+		if t == nil {
+			return nil
+		}
+		switch t.text {
+		case "syntax", "edition":
+			p.next()
+			if err := p.expect("="); err != nil {
+				return err
+			}
+			f.Syntax = p.next().text
+			if err := p.expect(";"); err != nil {
+				return err
+			}
+		case "package":
+			p.next()
+			name, err := p.ident()
+			if err != nil {
+				return err
+			}
+			f.Package = name
+			if err := p.expect(";"); err != nil {
+				return err
+			}
+		case "import":
+			p.next()
+			t := p.next()
+			if t != nil && (t.text == "public" || t.text == "weak") {
+				t = p.next()
+			}
+			if t == nil {
+				return fmt.Errorf("unexpected EOF")
+			}
+			f.Imports = append(f.Imports, t.text)
+			if err := p.expect(";"); err != nil {
+				return err
+			}
+		case "message":
+			p.next()
+			msg, err := p.parseMessage(f.Package)
+			if err != nil {
+				return err
+			}
+			f.Messages = append(f.Messages, msg)
+		case "enum":
+			p.next()
+			enum, err := p.parseEnum(f.Package)
+			if err != nil {
+				return err
+			}
+			f.Enums = append(f.Enums, enum)
+		case "service":
+			p.next()
+			service, err := p.parseService(f.Package)
+			if err != nil {
+				return err
+			}
+			f.Services = append(f.Services, service)
+		case ";":
+			p.next()
+		default:
+			// option extend ...
+			if err := p.skipStatement(); err != nil {
+				return err
+			}
+		}
+		p.takeComment()
+	}
+}
+
+func joinName(scope, name string) string {
+	if scope == "" {
+		return name
+	}
+	return scope + "." + name
+}
+
+func (p *parser) parseMessage(scope string) (*Message, error) {
+	comment := p.takeComment()
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	msg := &Message{Name: name, FullName: joinName(scope, name), Comment: comment}
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	if err := p.parseMessageBody(msg, ""); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// parseMessageBody parse the declarations until '}'
+// oneof is the oneof name if parsing a oneof body
+func (p *parser) parseMessageBody(msg *Message, oneof string) error {
+	for {
+		t := p.peek()
+		if t == nil {
+			return fmt.Errorf("message %s: unexpected EOF", msg.FullName)
+		}
+		switch t.text {
+		case "}":
+			p.next()
+			p.takeComment()
+			return nil
+		case ";":
+			p.next()
+		case "message":
+			p.next()
+			sub, err := p.parseMessage(msg.FullName)
+			if err != nil {
+				return err
+			}
+			msg.Messages = append(msg.Messages, sub)
+		case "enum":
+			p.next()
+			enum, err := p.parseEnum(msg.FullName)
+			if err != nil {
+				return err
+			}
+			msg.Enums = append(msg.Enums, enum)
+		case "oneof":
+			p.next()
+			p.takeComment()
+			name, err := p.ident()
+			if err != nil {
+				return err
+			}
+			if err := p.expect("{"); err != nil {
+				return err
+			}
+			if err := p.parseMessageBody(msg, name); err != nil {
+				return err
+			}
+		case "option", "reserved", "extensions", "extend":
+			p.takeComment()
+			if err := p.skipStatement(); err != nil {
+				return err
+			}
+		default:
+			field, err := p.parseField()
+			if err != nil {
+				return fmt.Errorf("message %s: %v", msg.FullName, err)
+			}
+			field.Oneof = oneof
+			msg.Fields = append(msg.Fields, field)
+		}
+	}
+}
+
+// parseField parse field like:
+//
+//	repeated string names = 1 [json_name = "n"]; // comment
+//	map<string, Project> projects = 3;
+func (p *parser) parseField() (*Field, error) {
+	field := &Field{Comment: p.takeComment()}
+	t := p.next()
+	if t == nil {
+		return nil, fmt.Errorf("unexpected EOF")
+	}
+	switch t.text {
+	case "repeated":
+		field.Repeated = true
+		t = p.next()
+	case "optional", "required":
+		t = p.next()
+	}
+	if t == nil {
+		return nil, fmt.Errorf("unexpected EOF")
+	}
+	if t.text == "map" {
+		if err := p.expect("<"); err != nil {
+			return nil, err
+		}
+		key, err := p.ident()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+		value, err := p.ident()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(">"); err != nil {
+			return nil, err
+		}
+		field.MapKey, field.MapValue = key, value
+		field.Type = "map"
+	} else {
+		field.Type = t.text
+	}
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	field.Name = name
+	if err := p.expect("="); err != nil {
+		return nil, err
+	}
+	num := p.next()
+	if num != nil {
+		field.Number, _ = strconv.Atoi(num.text)
+	}
+	if pk := p.peek(); pk != nil && pk.text == "[" {
+		opts, err := p.parseFieldOptions()
+		if err != nil {
+			return nil, err
+		}
+		field.JSONName = opts["json_name"]
+	}
+	if err := p.expect(";"); err != nil {
+		return nil, err
+	}
+	if c := p.trailingComment(); c != "" && field.Comment == "" {
+		field.Comment = c
+	}
+	return field, nil
+}
+
+// parseFieldOptions parse [a = b, (c).d = "e"] and returns constant values
+func (p *parser) parseFieldOptions() (map[string]string, error) {
+	opts := make(map[string]string)
+	if err := p.expect("["); err != nil {
+		return nil, err
+	}
+	for {
+		var name strings.Builder
+		for {
+			t := p.next()
+			if t == nil {
+				return nil, fmt.Errorf("unexpected EOF")
+			}
+			if t.text == "=" {
+				break
+			}
+			name.WriteString(t.text)
+		}
+		t := p.peek()
+		if t != nil && t.text == "{" {
+			if err := p.skipStatement(); err != nil {
+				return nil, err
+			}
+		} else if t != nil {
+			p.next()
+			opts[name.String()] = t.text
+		}
+		t = p.next()
+		if t == nil {
+			return nil, fmt.Errorf("unexpected EOF")
+		}
+		if t.text == "]" {
+			return opts, nil
+		}
+		if t.text != "," {
+			return nil, fmt.Errorf("%d: unexpected '%s' in field options", t.line, t.text)
+		}
+	}
+}
+
+func (p *parser) parseEnum(scope string) (*Enum, error) {
+	comment := p.takeComment()
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	enum := &Enum{Name: name, FullName: joinName(scope, name), Comment: comment}
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		if t == nil {
+			return nil, fmt.Errorf("enum %s: unexpected EOF", enum.FullName)
+		}
+		switch t.text {
+		case "}":
+			p.next()
+			p.takeComment()
+			return enum, nil
+		case ";":
+			p.next()
+		case "option", "reserved":
+			p.takeComment()
+			if err := p.skipStatement(); err != nil {
+				return nil, err
+			}
+		default:
+			value := &EnumValue{Comment: p.takeComment()}
+			value.Name, err = p.ident()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("="); err != nil {
+				return nil, err
+			}
+			num := p.next()
+			if num != nil && num.text == "-" {
+				num = p.next()
+				if num != nil {
+					num.text = "-" + num.text
+				}
+			}
+			if num != nil {
+				value.Number, _ = strconv.Atoi(num.text)
+			}
+			if pk := p.peek(); pk != nil && pk.text == "[" {
+				if _, err := p.parseFieldOptions(); err != nil {
+					return nil, err
+				}
+			}
+			if err := p.expect(";"); err != nil {
+				return nil, err
+			}
+			if c := p.trailingComment(); c != "" && value.Comment == "" {
+				value.Comment = c
+			}
+			enum.Values = append(enum.Values, value)
+		}
+	}
+}
+
+func (p *parser) parseService(scope string) (*Service, error) {
+	comment := p.takeComment()
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	service := &Service{Name: name, FullName: joinName(scope, name), Comment: comment}
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		if t == nil {
+			return nil, fmt.Errorf("service %s: unexpected EOF", service.FullName)
+		}
+		switch t.text {
+		case "}":
+			p.next()
+			p.takeComment()
+			return service, nil
+		case ";":
+			p.next()
+		case "rpc":
+			p.next()
+			rpc, err := p.parseRPC()
+			if err != nil {
+				return nil, fmt.Errorf("service %s: %v", service.FullName, err)
+			}
+			service.RPCs = append(service.RPCs, rpc)
+		default:
+			p.takeComment()
+			if err := p.skipStatement(); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// parseRPC parse rpc like:
+//
+//	rpc GetUser(GetUserReq) returns (User) {
+//	  option (google.api.http) = { get: "/v1/users/{id}" };
+//	}
+func (p *parser) parseRPC() (*RPC, error) {
+	rpc := &RPC{Comment: p.takeComment()}
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	rpc.Name = name
+	rpc.InputType, rpc.ClientStreaming, err = p.parseRPCType()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect("returns"); err != nil {
+		return nil, err
+	}
+	rpc.OutputType, rpc.ServerStreaming, err = p.parseRPCType()
+	if err != nil {
+		return nil, err
+	}
+	t := p.next()
+	if t == nil {
+		return nil, fmt.Errorf("rpc %s: unexpected EOF", name)
+	}
+	if t.text == ";" {
+		if c := p.trailingComment(); c != "" && rpc.Comment == "" {
+			rpc.Comment = c
+		}
+		return rpc, nil
+	}
+	if t.text != "{" {
+		return nil, fmt.Errorf("%d: expect '{' but got '%s'", t.line, t.text)
+	}
+	for {
+		t := p.next()
+		if t == nil {
+			return nil, fmt.Errorf("rpc %s: unexpected EOF", name)
+		}
+		switch t.text {
+		case "}":
+			if pk := p.peek(); pk != nil && pk.text == ";" {
+				p.next()
+			}
+			p.takeComment()
+			return rpc, nil
+		case ";":
+		case "option":
+			optName, err := p.optionName()
+			if err != nil {
+				return nil, err
+			}
+			if optName != "(google.api.http)" {
+				if err := p.skipStatement(); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			if err := p.expect("{"); err != nil {
+				return nil, err
+			}
+			agg, err := p.parseAggregate()
+			if err != nil {
+				return nil, fmt.Errorf("rpc %s: %v", name, err)
+			}
+			rpc.HTTPRules = httpRules(agg)
+			if pk := p.peek(); pk != nil && pk.text == ";" {
+				p.next()
+			}
+		default:
+			return nil, fmt.Errorf("%d: unexpected '%s' in rpc %s", t.line, t.text, name)
+		}
+	}
+}
+
+// parseRPCType parse `([stream] Type)`
+func (p *parser) parseRPCType() (string, bool, error) {
+	if err := p.expect("("); err != nil {
+		return "", false, err
+	}
+	typ, err := p.ident()
+	if err != nil {
+		return "", false, err
+	}
+	stream := false
+	if typ == "stream" {
+		if pk := p.peek(); pk != nil && pk.kind == tokIdent {
+			stream = true
+			if typ, err = p.ident(); err != nil {
+				return "", false, err
+			}
+		}
+	}
+	if err := p.expect(")"); err != nil {
+		return "", false, err
+	}
+	return typ, stream, nil
+}
+
+// optionName read the option name and the following '='
+func (p *parser) optionName() (string, error) {
+	var name strings.Builder
+	for {
+		t := p.next()
+		if t == nil {
+			return "", fmt.Errorf("unexpected EOF")
+		}
+		if t.text == "=" {
+			return name.String(), nil
+		}
+		name.WriteString(t.text)
+	}
+}
+
+// aggregate is the text format value of an option,
+// values of a field are in order and a field may appear many times
+type aggregate struct {
+	names  []string
+	values []interface{}
+}
+
+func (agg *aggregate) get(name string) []interface{} {
+	var r []interface{}
+	for i, n := range agg.names {
+		if n == name {
+			r = append(r, agg.values[i])
+		}
+	}
+	return r
+}
+
+func (agg *aggregate) getString(name string) string {
+	for _, v := range agg.get(name) {
+		if s, ok := v.(string); ok {
+			return s
+		}
+	}
+	return ""
+}
+
+// parseAggregate parse the text format value after '{' until '}'
+//
+//	get: "/v1/users/{id}" body: "*" additional_bindings { post: "/v1/users" }
+func (p *parser) parseAggregate() (*aggregate, error) {
+	agg := new(aggregate)
+	for {
+		t := p.next()
+		if t == nil {
+			return nil, fmt.Errorf("unexpected EOF")
+		}
+		switch t.text {
+		case "}":
+			return agg, nil
+		case ",", ";":
+			continue
+		}
+		name := t.text
+		if pk := p.peek(); pk != nil && pk.text == ":" {
+			p.next()
+		}
+		values, err := p.parseAggregateValue()
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range values {
+			agg.names = append(agg.names, name)
+			agg.values = append(agg.values, v)
+		}
+	}
+}
+
+func (p *parser) parseAggregateValue() ([]interface{}, error) {
+	t := p.next()
+	if t == nil {
+		return nil, fmt.Errorf("unexpected EOF")
+	}
+	switch t.text {
+	case "{":
+		agg, err := p.parseAggregate()
+		if err != nil {
+			return nil, err
+		}
+		return []interface{}{agg}, nil
+	case "[":
+		var values []interface{}
+		for {
+			if pk := p.peek(); pk != nil && pk.text == "]" {
+				p.next()
+				return values, nil
+			}
+			v, err := p.parseAggregateValue()
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, v...)
+			if pk := p.peek(); pk != nil && pk.text == "," {
+				p.next()
+			}
+		}
+	}
+	value := t.text
+	// adjacent strings are concatenated
+	for t.kind == tokString {
+		pk := p.peek()
+		if pk == nil || pk.kind != tokString {
+			break
+		}
+		value += p.next().text
+	}
+	return []interface{}{value}, nil
+}
+
+// httpRules convert the value of `google.api.http` to http rules
+func httpRules(agg *aggregate) []*HTTPRule {
+	rule := &HTTPRule{Body: agg.getString("body")}
+	for _, method := range []string{"get", "put", "post", "delete", "patch"} {
+		if path := agg.getString(method); path != "" {
+			rule.Method = strings.ToUpper(method)
+			rule.Path = path
+		}
+	}
+	for _, v := range agg.get("custom") {
+		if custom, ok := v.(*aggregate); ok {
+			rule.Method = strings.ToUpper(custom.getString("kind"))
+			rule.Path = custom.getString("path")
+		}
+	}
+	var rules []*HTTPRule
+	if rule.Path != "" {
+		rules = append(rules, rule)
+	}
+	for _, v := range agg.get("additional_bindings") {
+		if binding, ok := v.(*aggregate); ok {
+			rules = append(rules, httpRules(binding)...)
+		}
+	}
+	return rules
+}
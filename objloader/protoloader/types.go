@@ -0,0 +1,66 @@
+package protoloader
+
+import "github.com/thewinds/mkdoc"
+
+// scalarTypes proto scalar type => mkdoc builtin type
+var scalarTypes = map[string]string{
+	"double":   "float64",
+	"float":    "float32",
+	"int32":    "int32",
+	"sint32":   "int32",
+	"sfixed32": "int32",
+	"int64":    "int64",
+	"sint64":   "int64",
+	"sfixed64": "int64",
+	"uint32":   "uint32",
+	"fixed32":  "uint32",
+	"uint64":   "uint64",
+	"fixed64":  "uint64",
+	"bool":     "bool",
+	"string":   "string",
+	"bytes":    "string",
+}
+
+type wellKnownType struct {
+	typ  string
+	desc string
+}
+
+// wellKnownTypes google.protobuf types which have special json mapping
+var wellKnownTypes = map[string]wellKnownType{
+	"google.protobuf.Timestamp":   {"string", "date-time (RFC 3339)"},
+	"google.protobuf.Duration":    {"string", "duration,eg. 1.5s"},
+	"google.protobuf.FieldMask":   {"string", "field paths split by ','"},
+	"google.protobuf.StringValue": {"string", ""},
+	"google.protobuf.BytesValue":  {"string", "base64 encoded"},
+	"google.protobuf.BoolValue":   {"bool", ""},
+	"google.protobuf.Int32Value":  {"int32", ""},
+	"google.protobuf.UInt32Value": {"uint32", ""},
+	"google.protobuf.Int64Value":  {"int64", ""},
+	"google.protobuf.UInt64Value": {"uint64", ""},
+	"google.protobuf.FloatValue":  {"float32", ""},
+	"google.protobuf.DoubleValue": {"float64", ""},
+	"google.protobuf.Struct":      {"interface{}", ""},
+	"google.protobuf.Value":       {"interface{}", ""},
+	"google.protobuf.ListValue":   {"interface{}", ""},
+	"google.protobuf.Any":         {"interface{}", ""},
+	"google.protobuf.Empty":       {"object", ""},
+}
+
+var builtinTypes = []string{
+	"string", "bool",
+	"int32", "int64", "uint32", "uint64",
+	"float32", "float64",
+	"interface{}"}
+
+func builtinObjects() []*mkdoc.Object {
+	var objects []*mkdoc.Object
+	for _, t := range builtinTypes {
+		objects = append(objects, &mkdoc.Object{
+			ID:     t,
+			Type:   &mkdoc.ObjectType{Name: t},
+			Loaded: true,
+		})
+	}
+	return objects
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/thewinds/mkdoc/schema"
 	"golang.org/x/sync/errgroup"
+	"os"
 	"strings"
 	"sync"
 )
@@ -16,6 +17,8 @@ type Project struct {
 	refObjects       map[LangObjectId]*Object
 	defaultLoaderCfg *ObjectLoaderConfig
 	muObj            sync.Mutex
+	errorCodes       []*ErrorCode
+	errorTypes       map[LangObjectId]bool
 }
 
 func NewProject(config *Config) (*Project, error) {
@@ -28,6 +31,7 @@ func NewProject(config *Config) (*Project, error) {
 		return nil, err
 	}
 	project.refObjects = make(map[LangObjectId]*Object)
+	project.errorTypes = make(map[LangObjectId]bool)
 	project.defaultLoaderCfg = &ObjectLoaderConfig{*project.Config}
 	//if config.BaseType != "" {
 	//	baseTypeObj := &Object{
@@ -103,15 +107,6 @@ func (project *Project) Objects() map[LangObjectId]*Object {
 	return project.refObjects
 }
 
-func (project *Project) parseSchemaExtension(ext *schema.Extension) (Extension, error) {
-	switch ext.Name {
-	case "go_tag":
-		return new(ExtensionGoTag).Parse(ext)
-	default:
-		return new(ExtensionUnknown).Parse(ext)
-	}
-}
-
 func (project *Project) parseSchemaObject(object *schema.Object) (*Object, error) {
 	obj := Object{
 		ID:         object.ID,
@@ -128,7 +123,7 @@ func (project *Project) parseSchemaObject(object *schema.Object) (*Object, error
 		}
 		for _, ext := range field.Extensions {
 			if ext != nil {
-				extParsed, err := project.parseSchemaExtension(ext)
+				extParsed, err := ParseExtension(ext)
 				if err != nil {
 					return nil, err
 				}
@@ -138,7 +133,7 @@ func (project *Project) parseSchemaObject(object *schema.Object) (*Object, error
 		obj.Fields = append(obj.Fields, objField)
 	}
 	for _, ext := range object.Extensions {
-		extParsed, err := project.parseSchemaExtension(ext)
+		extParsed, err := ParseExtension(ext)
 		if err != nil {
 			return nil, err
 		}
@@ -148,11 +143,9 @@ func (project *Project) parseSchemaObject(object *schema.Object) (*Object, error
 }
 
 func (project *Project) ParseSchemaAPI(api *schema.API) (*API, error) {
-	loader := GetObjectLoader(api.Language)
-	if loader == nil {
+	if GetObjectLoader(api.Language) == nil {
 		return nil, fmt.Errorf("object loader for language %s not found", api.Language)
 	}
-	loader.SetConfig(project.defaultLoaderCfg)
 	a := &API{
 		API:  *api,
 		Mime: &MimeType{In: api.MimeIn, Out: api.MimeOut},
@@ -163,28 +156,118 @@ func (project *Project) ParseSchemaAPI(api *schema.API) (*API, error) {
 	if len(a.Mime.Out) == 0 {
 		a.Mime.Out = project.Config.Mime.Out
 	}
+	var dropped []string
+	a.PathParams, dropped = pathParams(api.Path, api.PathParams)
+	for _, name := range dropped {
+		fmt.Fprintf(os.Stderr, "WARNING: api %s (%s): path param %s is not in path %s,it is dropped \n", api.Name, api.SourceFileName, name, api.Path)
+	}
+	a.Header = mergeParams(nil, api.Header)
+	var query schema.Params
+	if len(api.QueryType) > 0 {
+		lang, typ := TypeLang(api.Language, api.QueryType)
+		objId, err := project.getObjectId(lang, TypeScope{api.SourceFileName, typ})
+		if err != nil {
+			return nil, err
+		}
+		obj := project.GetLangObject(LangObjectId{Lang: lang, Id: objId})
+		if obj == nil {
+			return nil, fmt.Errorf("api %s: query type %s not found", api.Name, api.QueryType)
+		}
+		query = structParams(obj, func(id string) *Object {
+			return project.GetLangObject(LangObjectId{Lang: lang, Id: id})
+		})
+	}
+	a.Query = mergeParams(query, api.Query)
 
+	a.InLanguage, a.OutLanguage = api.Language, api.Language
 	if len(api.InType) > 0 {
-		objId, err := loader.GetObjectId(TypeScope{api.SourceFileName, api.InType})
+		lang, typ := TypeLang(api.Language, api.InType)
+		objId, err := project.getObjectId(lang, TypeScope{api.SourceFileName, typ})
 		if err != nil {
 			return nil, err
 		}
-		id := LangObjectId{Lang: api.Language, Id: objId}
 		a.InType = objId
-		a.InArgument = project.GetLangObject(id)
+		a.InLanguage = lang
+		a.InArgument = project.GetLangObject(LangObjectId{Lang: lang, Id: objId})
 	}
 	if len(api.OutType) > 0 {
-		objId, err := loader.GetObjectId(TypeScope{api.SourceFileName, api.OutType})
+		lang, typ := TypeLang(api.Language, api.OutType)
+		objId, err := project.getObjectId(lang, TypeScope{api.SourceFileName, typ})
 		if err != nil {
 			return nil, err
 		}
-		id := LangObjectId{Lang: api.Language, Id: objId}
 		a.OutType = objId
-		a.OutArgument = project.GetLangObject(id)
+		a.OutLanguage = lang
+		a.OutArgument = project.GetLangObject(LangObjectId{Lang: lang, Id: objId})
+	}
+	responses := api.Responses
+	if len(responses) == 0 && len(api.OutType) > 0 {
+		responses = []*schema.Response{{Status: "200", Mime: api.MimeOut, Type: api.OutType}}
+	}
+	for _, r := range responses {
+		resp := &Response{Status: r.Status, Desc: r.Desc, Mime: r.Mime, Language: api.Language}
+		if len(resp.Status) == 0 {
+			resp.Status = "200"
+		}
+		if len(resp.Mime) == 0 {
+			resp.Mime = a.Mime.Out
+		}
+		if len(r.Type) > 0 {
+			lang, typ := TypeLang(api.Language, r.Type)
+			objId, err := project.getObjectId(lang, TypeScope{api.SourceFileName, typ})
+			if err != nil {
+				return nil, err
+			}
+			resp.Language = lang
+			resp.Argument = project.GetLangObject(LangObjectId{Lang: lang, Id: objId})
+		}
+		a.Responses = append(a.Responses, resp)
+	}
+	// the out example is the example of success response
+	if len(api.OutExample) > 0 {
+		r := a.SuccessResponse()
+		if r == nil {
+			r = &Response{Status: "200", Mime: a.Mime.Out, Language: api.Language}
+			a.Responses = append([]*Response{r}, a.Responses...)
+		}
+		r.Example = api.OutExample
+	}
+	errCodes, err := project.apiErrorCodes(api)
+	if err != nil {
+		return nil, err
+	}
+	a.Errors = errCodes
+	// the success response is the out argument if the out type is not declared
+	if a.OutArgument == nil {
+		if r := a.SuccessResponse(); r != nil && r.Argument != nil {
+			a.OutArgument = r.Argument
+			a.OutLanguage = r.Language
+			a.Mime.Out = r.Mime
+		}
 	}
 	return a, nil
 }
 
+func (project *Project) getObjectId(lang string, ts TypeScope) (string, error) {
+	loader := GetObjectLoader(lang)
+	if loader == nil {
+		return "", fmt.Errorf("object loader for language %s not found", lang)
+	}
+	loader.SetConfig(project.defaultLoaderCfg)
+	return loader.GetObjectId(ts)
+}
+
+// TypeLang returns the language and type name of a type which may have a language prefix,
+// eg. `proto:user.v1.User` will be loaded by the proto loader,
+// lang is returned if there is no prefix of a registered loader
+func TypeLang(lang string, typeName string) (string, string) {
+	i := strings.Index(typeName, ":")
+	if i <= 0 || GetObjectLoader(typeName[:i]) == nil {
+		return lang, typeName
+	}
+	return typeName[:i], typeName[i+1:]
+}
+
 func (project *Project) LoadObjects(schemaDef *schema.Schema) error {
 	// load object from schema object define
 	for _, object := range schemaDef.Objects {
@@ -198,14 +281,40 @@ func (project *Project) LoadObjects(schemaDef *schema.Schema) error {
 	langTs := make(map[string][]TypeScope)
 	for _, api := range schemaDef.APIs {
 		if len(api.InType) > 0 {
-			ts := TypeScope{FileName: api.SourceFileName, TypeName: api.InType}
-			langTs[api.Language] = append(langTs[api.Language], ts)
+			lang, typ := TypeLang(api.Language, api.InType)
+			ts := TypeScope{FileName: api.SourceFileName, TypeName: typ}
+			langTs[lang] = append(langTs[lang], ts)
 		}
 		if len(api.OutType) > 0 {
-			ts := TypeScope{FileName: api.SourceFileName, TypeName: api.OutType}
-			langTs[api.Language] = append(langTs[api.Language], ts)
+			lang, typ := TypeLang(api.Language, api.OutType)
+			ts := TypeScope{FileName: api.SourceFileName, TypeName: typ}
+			langTs[lang] = append(langTs[lang], ts)
+		}
+		if len(api.QueryType) > 0 {
+			lang, typ := TypeLang(api.Language, api.QueryType)
+			ts := TypeScope{FileName: api.SourceFileName, TypeName: typ}
+			langTs[lang] = append(langTs[lang], ts)
+		}
+		for _, r := range api.Responses {
+			if len(r.Type) > 0 {
+				lang, typ := TypeLang(api.Language, r.Type)
+				ts := TypeScope{FileName: api.SourceFileName, TypeName: typ}
+				langTs[lang] = append(langTs[lang], ts)
+			}
+		}
+		if len(api.ErrorType) > 0 {
+			lang, typ := TypeLang(api.Language, api.ErrorType)
+			ts := TypeScope{FileName: api.SourceFileName, TypeName: typ}
+			langTs[lang] = append(langTs[lang], ts)
 		}
 	}
+	configErrors, err := project.Config.errorTypeScopes()
+	if err != nil {
+		return err
+	}
+	for _, e := range configErrors {
+		langTs[e.lang] = append(langTs[e.lang], e.ts)
+	}
 	for lang := range langTs {
 		if GetObjectLoader(lang) == nil {
 			return fmt.Errorf("object loader for language %s not found", lang)
@@ -213,6 +322,7 @@ func (project *Project) LoadObjects(schemaDef *schema.Schema) error {
 	}
 	eg := errgroup.Group{}
 	for lang, typeScopes := range langTs {
+		lang, typeScopes := lang, typeScopes
 		loader := GetObjectLoader(lang)
 		loader.SetConfig(project.defaultLoaderCfg)
 		for id, object := range project.Objects() {
@@ -234,5 +344,9 @@ func (project *Project) LoadObjects(schemaDef *schema.Schema) error {
 			return nil
 		})
 	}
-	return eg.Wait()
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+	// the error codes are loaded after the error types are loaded
+	return project.loadErrorCodes(configErrors, schemaDef.APIs)
 }
@@ -1,6 +1,7 @@
 package gofunc
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"github.com/thewinds/mkdoc"
@@ -8,8 +9,10 @@ import (
 	"go/token"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"unicode"
 )
 
 // DocAnnotation is a set of annotation command
@@ -19,10 +22,18 @@ import (
 	根据用户ID获取用户信息,如果用户不存在则返回null
 	@tag user,profile
 	@path /user/:uid/profile @method POST
-	@query uid 用户id
+	@param uid int 用户id
+	@query page int required default=1 页码
+	@query type view.ListReq
+	@header token string required 令牌
 	@in  type view.GetUserReq
 	@out type api.User
 	@out[json] type api.User
+	@out 404 type api.Error 用户不存在
+	@out 4xx fields {
+		code int    错误码
+		msg  string 错误信息
+	}
 	@out fields {
 		id   int    用户id
 		name string 用户名
@@ -37,10 +48,10 @@ var annotationRegexps map[string]*regexp.Regexp
 
 func init() {
 	annotationRegexps = map[string]*regexp.Regexp{
-		"in_go_type":       regexp.MustCompile(`(@in(\[\S*\])?\s+type\s+)([^\s]+)`),
-		"out_go_type":      regexp.MustCompile(`(@out(\[\S*\])?\s+type\s+)([^\s]+)`),
-		"in_fields_block":  regexp.MustCompile(`(@in(\[\S*\])?\s+fields\s+(\[\])?{\s+)((.|\s)+)}`),
-		"out_fields_block": regexp.MustCompile(`(@out(\[\S*\])?\s+fields\s+(\[\])?{\s+)((.|\s)+)}`),
+		"in_go_type":       regexp.MustCompile(`(@in(\[\S*\])?\s+type\s+)([^\s,]+(,[ \t]*[^\s,]+)*)`),
+		"out_go_type":      regexp.MustCompile(`(@out(\[\S*\])?\s+(?:(\d{3}|[1-5][xX]{2}|default)\s+)?type\s+)([^\s,]+(,[ \t]*[^\s,]+)*)(?:[ \t]+([^\n]*))?`),
+		"in_fields_block":  regexp.MustCompile(`(@in(\[\S*\])?\s+fields\s+(\[\])?{\s+)((.|\s)+?)}`),
+		"out_fields_block": regexp.MustCompile(`(@out(\[\S*\])?\s+(?:(\d{3}|[1-5][xX]{2}|default)\s+)?fields\s+(\[\])?{\s+)((.|\s)+?)}`),
 		"field":            regexp.MustCompile(`(\w+)\s+(\w+)\s*(.+)*`),
 	}
 }
@@ -48,8 +59,6 @@ func init() {
 func parseSimple(annotation DocAnnotation) (*schema.API, error) {
 	api := new(schema.API)
 	api.Language = "go"
-	api.Query = make(map[string]string)
-	api.Header = make(map[string]string)
 
 	lines := strings.Split(string(annotation), "\n")
 	lineFields := make([][]string, 0, len(lines))
@@ -107,20 +116,24 @@ func parseSimple(annotation DocAnnotation) (*schema.API, error) {
 			} else {
 				api.Tags = append(api.Tags, strings.TrimSpace(tagsStr))
 			}
+		case "@param", "@pathparam":
+			api.PathParams = append(api.PathParams, parseParam(fields[1:]))
 		case "@query":
-			name := fields[1]
-			comment := ""
-			if len(fields) >= 3 {
-				comment = fields[2]
+			if fieldNum == 3 && fields[1] == "type" && isQueryType(fields[2]) {
+				api.QueryType = typeName(fields[2])
+				continue
 			}
-			api.Query[name] = comment
+			api.Query = append(api.Query, parseParam(fields[1:]))
 		case "@header":
-			name := fields[1]
-			comment := ""
-			if len(fields) >= 3 {
-				comment = fields[2]
+			api.Header = append(api.Header, parseParam(fields[1:]))
+		case "@errors":
+			api.ErrorType = typeName(fields[1])
+		case "@error":
+			for _, code := range strings.Split(strings.Join(fields[1:], ","), ",") {
+				if code != "" {
+					api.Errors = append(api.Errors, code)
+				}
 			}
-			api.Header[name] = comment
 		case "@loc":
 			loc := strings.Split(fields[1], ":")
 			if len(loc) == 2 {
@@ -137,53 +150,190 @@ func parseSimple(annotation DocAnnotation) (*schema.API, error) {
 }
 
 func parseInOut(annotation DocAnnotation, api *schema.API) ([]*schema.Object, error) {
+	if err := parseExamples(annotation, api); err != nil {
+		return nil, err
+	}
 	var objects []*schema.Object
-	for command, re := range annotationRegexps {
-		matchGroups := re.FindAllStringSubmatch(string(annotation), -1)
-		for _, matchGroup := range matchGroups {
-			if len(matchGroup) > 0 {
-				switch command {
-				case "in_go_type":
-					api.MimeIn = rmBracket(matchGroup[2])
-					api.InType = matchGroup[3]
-				case "out_go_type":
-					api.MimeOut = rmBracket(matchGroup[2])
-					api.OutType = matchGroup[3]
-				case "in_fields_block":
-					api.MimeIn = rmBracket(matchGroup[2])
-					fieldStmts := matchGroup[4]
-					obj := &schema.Object{
-						ID:       mkdoc.RandObjectID("in"),
-						Type:     &schema.ObjectType{Name: "object"},
-						Fields:   parseToObjectFields(fieldStmts),
-						Language: "go",
-					}
-					if matchGroup[3] != "" {
-						obj.Type.IsRepeated = true
-					}
-					api.InType = obj.ID
-					objects = append(objects, obj)
-				case "out_fields_block":
-					api.MimeOut = rmBracket(matchGroup[2])
-					fieldStmts := matchGroup[4]
-					obj := &schema.Object{
-						ID:       mkdoc.RandObjectID("out"),
-						Type:     &schema.ObjectType{Name: "object"},
-						Fields:   parseToObjectFields(fieldStmts),
-						Language: "go",
-					}
-					if matchGroup[3] != "" {
-						obj.Type.IsRepeated = true
-					}
-					api.OutType = obj.ID
-					objects = append(objects, obj)
-				}
+	var responses []*schema.Response
+	for _, m := range inOutMatches(annotation) {
+		matchGroup := m.groups
+		switch m.command {
+		case "in_go_type":
+			api.MimeIn = rmBracket(matchGroup[2])
+			api.InType = typeName(matchGroup[3])
+		case "out_go_type":
+			responses = append(responses, &schema.Response{
+				Status: responseStatus(matchGroup[3]),
+				Desc:   strings.TrimSpace(matchGroup[6]),
+				Mime:   rmBracket(matchGroup[2]),
+				Type:   typeName(matchGroup[4]),
+			})
+		case "in_fields_block":
+			api.MimeIn = rmBracket(matchGroup[2])
+			fieldStmts := matchGroup[4]
+			obj := &schema.Object{
+				ID:       fieldsObjectID("in", api, fieldStmts),
+				Type:     &schema.ObjectType{Name: "object"},
+				Fields:   parseToObjectFields(fieldStmts),
+				Language: "go",
+			}
+			if matchGroup[3] != "" {
+				obj.Type.IsRepeated = true
+			}
+			api.InType = obj.ID
+			objects = append(objects, obj)
+		case "out_fields_block":
+			status := responseStatus(matchGroup[3])
+			direction := "out"
+			if status != "200" {
+				direction += "_" + status
+			}
+			fieldStmts := matchGroup[5]
+			obj := &schema.Object{
+				ID:       fieldsObjectID(direction, api, fieldStmts),
+				Type:     &schema.ObjectType{Name: "object"},
+				Fields:   parseToObjectFields(fieldStmts),
+				Language: "go",
 			}
+			if matchGroup[4] != "" {
+				obj.Type.IsRepeated = true
+			}
+			responses = append(responses, &schema.Response{
+				Status: status,
+				Mime:   rmBracket(matchGroup[2]),
+				Type:   obj.ID,
+			})
+			objects = append(objects, obj)
+		}
+	}
+	// the first 2xx response is the out type,
+	// the responses of the same status are in the order of declaration
+	sort.SliceStable(responses, func(i, j int) bool {
+		return responses[i].Status < responses[j].Status
+	})
+	api.Responses = responses
+	for _, r := range responses {
+		if strings.HasPrefix(r.Status, "2") {
+			api.MimeOut = r.Mime
+			api.OutType = r.Type
+			break
 		}
 	}
 	return objects, nil
 }
 
+type inOutMatch struct {
+	pos     int
+	command string
+	groups  []string
+}
+
+// inOutMatches returns the matches of @in and @out in the order of declaration,
+// so the last declared in type is used and the responses are stable
+func inOutMatches(annotation DocAnnotation) []*inOutMatch {
+	s := string(annotation)
+	var matches []*inOutMatch
+	for _, command := range []string{"in_go_type", "out_go_type", "in_fields_block", "out_fields_block"} {
+		for _, loc := range annotationRegexps[command].FindAllStringSubmatchIndex(s, -1) {
+			groups := make([]string, len(loc)/2)
+			for i := range groups {
+				if loc[2*i] >= 0 {
+					groups[i] = s[loc[2*i]:loc[2*i+1]]
+				}
+			}
+			matches = append(matches, &inOutMatch{pos: loc[0], command: command, groups: groups})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].pos < matches[j].pos
+	})
+	return matches
+}
+
+var reExample = regexp.MustCompile(`@example\s+(in|out)\s+`)
+
+// parseExamples parse the literal json bodies of `@example in|out`,
+// the body is ended at the end of the first json value
+func parseExamples(annotation DocAnnotation, api *schema.API) error {
+	s := string(annotation)
+	for _, loc := range reExample.FindAllStringSubmatchIndex(s, -1) {
+		var body json.RawMessage
+		if err := json.NewDecoder(strings.NewReader(s[loc[1]:])).Decode(&body); err != nil {
+			return fmt.Errorf("api %s: invalid example: %v", api.Name, err)
+		}
+		buf := bytes.NewBuffer(nil)
+		if err := json.Compact(buf, body); err != nil {
+			return fmt.Errorf("api %s: invalid example: %v", api.Name, err)
+		}
+		if s[loc[2]:loc[3]] == "in" {
+			api.InExample = buf.String()
+		} else {
+			api.OutExample = buf.String()
+		}
+	}
+	return nil
+}
+
+// responseStatus returns the status of `@out 404 type api.Error`,
+// the status is 200 if it is omitted
+func responseStatus(s string) string {
+	if s == "" {
+		return "200"
+	}
+	return strings.ToLower(s)
+}
+
+// parseParam parse the parameter declared like `@query uid int required default=1 用户ID`,
+// the type and the options are optional and string is used if the type is omitted,
+// the options are required,default=value and enum=a,b,c
+func parseParam(fields []string) *schema.Param {
+	param := &schema.Param{Name: fields[0]}
+	fields = fields[1:]
+	if len(fields) > 0 && isBuiltinType(fields[0]) {
+		param.Type = fields[0]
+		fields = fields[1:]
+	}
+	for ; len(fields) > 0; fields = fields[1:] {
+		opt := fields[0]
+		if opt == "required" {
+			param.Required = true
+		} else if strings.HasPrefix(opt, "default=") {
+			param.Default = strings.TrimPrefix(opt, "default=")
+		} else if strings.HasPrefix(opt, "enum=") {
+			param.Enum = strings.Split(strings.TrimPrefix(opt, "enum="), ",")
+		} else {
+			break
+		}
+	}
+	param.Desc = strings.Join(fields, " ")
+	return param
+}
+
+var reQueryType = regexp.MustCompile(`^[A-Za-z_]\w*(\.[A-Za-z_]\w*)?(\[.+\])?$`)
+
+// isQueryType report whether s is the struct type of `@query type view.ListReq`
+// rather than the description of a parameter named type,
+// the type must be qualified by package or exported
+func isQueryType(s string) bool {
+	if !reQueryType.MatchString(s) {
+		return false
+	}
+	return strings.Contains(s, ".") || unicode.IsUpper(rune(s[0]))
+}
+
+// fieldsObjectID returns the id of object declared by fields block,
+// it is derived from the api and fields so that the id is stable between runs
+func fieldsObjectID(direction string, api *schema.API, fieldStmts string) string {
+	return mkdoc.ObjectID(direction, api.Method, api.Path, api.Name, strings.Join(strings.Fields(fieldStmts), " "))
+}
+
+// typeName remove the spaces in type arguments of generic type
+//
+//	Pair[string, model.User] => Pair[string,model.User]
+func typeName(s string) string {
+	return strings.Join(strings.Fields(s), "")
+}
+
 func parseToObjectFields(fieldStmts string) []*schema.ObjectField {
 	fields := make([]*schema.ObjectField, 0)
 	for _, stmt := range strings.Split(fieldStmts, "\n") {
@@ -215,8 +365,13 @@ func rmBracket(s string) string {
 	return s[1 : len(s)-1]
 }
 
+// AppendMetaData append the api type and location,
+// the type is ignored if it's already set by `@type`
 func (annotation DocAnnotation) AppendMetaData(typ string, fp token.Position) DocAnnotation {
 	t := fmt.Sprintf("@type %s\n", typ)
+	if strings.Contains(string(annotation), "@type ") {
+		t = ""
+	}
 	absfp, _ := filepath.Abs(fp.Filename)
 	loc := fmt.Sprintf("@loc %s:%d\n", absfp, fp.Line)
 	return annotation + DocAnnotation(t+loc)
@@ -1,5 +1,150 @@
 package gofunc
 
+import (
+	"github.com/thewinds/mkdoc/schema"
+	"reflect"
+	"testing"
+)
+
+func TestParseInOut_GenericType(t *testing.T) {
+	tests := map[string][2]string{
+		"@in type Resp[model.User]\n@out[json] type []Resp[[]model.User]": {"Resp[model.User]", "[]Resp[[]model.User]"},
+		"@in type Pair[string, model.User] \n@out type model.User":        {"Pair[string,model.User]", "model.User"},
+	}
+	for annotation, want := range tests {
+		api := new(schema.API)
+		if _, err := parseInOut(DocAnnotation(annotation), api); err != nil {
+			t.Fatal(err)
+		}
+		if api.InType != want[0] || api.OutType != want[1] {
+			t.Errorf("parseInOut(%q) got %s %s, want %s %s", annotation, api.InType, api.OutType, want[0], want[1])
+		}
+	}
+}
+
+func TestParseInOut_ObjectID(t *testing.T) {
+	parse := func(annotation string) string {
+		api := &schema.API{Method: "POST", Path: "/user"}
+		if _, err := parseInOut(DocAnnotation(annotation), api); err != nil {
+			t.Fatal(err)
+		}
+		return api.InType
+	}
+	id := parse("@in fields {\n name string 名称\n}")
+	if again := parse("@in fields {\n name string 名称\n}"); again != id {
+		t.Errorf("id of the same fields got %s and %s", id, again)
+	}
+	if other := parse("@in fields {\n age int 年龄\n}"); other == id {
+		t.Errorf("id of different fields got the same %s", id)
+	}
+}
+
+func TestParseInOut_Responses(t *testing.T) {
+	api := &schema.API{Method: "GET", Path: "/user"}
+	objects, err := parseInOut(DocAnnotation("@out 404 type api.Error 用户不存在\n"+
+		"@out 4xx fields {\n code int 错误码\n}\n@out[json] type api.User\n"), api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(objects) != 1 {
+		t.Fatalf("objects got %d, want 1", len(objects))
+	}
+	want := []schema.Response{
+		{Status: "200", Mime: "json", Type: "api.User"},
+		{Status: "404", Desc: "用户不存在", Type: "api.Error"},
+		{Status: "4xx", Type: objects[0].ID},
+	}
+	if len(api.Responses) != len(want) {
+		t.Fatalf("responses got %d, want %d", len(api.Responses), len(want))
+	}
+	for i, r := range api.Responses {
+		if *r != want[i] {
+			t.Errorf("response %d got %+v, want %+v", i, *r, want[i])
+		}
+	}
+	if api.OutType != "api.User" || api.MimeOut != "json" {
+		t.Errorf("out type got %s[%s], want api.User[json]", api.OutType, api.MimeOut)
+	}
+}
+
+func TestParseInOut_DeclarationOrder(t *testing.T) {
+	// run several times since the order must not depend on the iteration of maps
+	for i := 0; i < 20; i++ {
+		api := &schema.API{Method: "GET", Path: "/user"}
+		objects, err := parseInOut(DocAnnotation("@out fields {\n id int ID\n}\n@out type api.User\n"), api)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(api.Responses) != 2 || api.Responses[0].Type != objects[0].ID || api.Responses[1].Type != "api.User" {
+			t.Fatalf("responses are not in the order of declaration")
+		}
+		if api.OutType != objects[0].ID {
+			t.Fatalf("out type got %s, want %s", api.OutType, objects[0].ID)
+		}
+	}
+}
+
+func TestParseInOut_Examples(t *testing.T) {
+	api := &schema.API{Method: "POST", Path: "/user"}
+	_, err := parseInOut(DocAnnotation("@example in {\n \"name\": \"alice\",\n \"tags\": [\"a\"]\n}\n"+
+		"@example out [{\"id\": 1}]\n@out type api.User\n"), api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"name":"alice","tags":["a"]}`; api.InExample != want {
+		t.Errorf("in example got %s, want %s", api.InExample, want)
+	}
+	if want := `[{"id":1}]`; api.OutExample != want {
+		t.Errorf("out example got %s, want %s", api.OutExample, want)
+	}
+	if _, err := parseInOut(DocAnnotation("@example in {\"name\": }"), api); err == nil {
+		t.Error("invalid example got no error")
+	}
+}
+
+func TestParseSimple_Params(t *testing.T) {
+	api, err := parseSimple("@path /user/:uid/:name\n@param uid int 用户 id\n@pathparam name 用户名\n" +
+		"@query page int required default=1 页码\n@query sort enum=asc,desc\n@query type view.ListReq\n@header token 令牌")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		got  schema.Params
+		want []schema.Param
+	}{
+		{api.PathParams, []schema.Param{{Name: "uid", Type: "int", Desc: "用户 id"}, {Name: "name", Desc: "用户名"}}},
+		{api.Query, []schema.Param{{Name: "page", Type: "int", Desc: "页码", Required: true, Default: "1"}, {Name: "sort", Enum: []string{"asc", "desc"}}}},
+		{api.Header, []schema.Param{{Name: "token", Desc: "令牌"}}},
+	}
+	for _, tt := range tests {
+		if len(tt.got) != len(tt.want) {
+			t.Errorf("params got %d, want %d", len(tt.got), len(tt.want))
+			continue
+		}
+		for i, p := range tt.got {
+			if !reflect.DeepEqual(*p, tt.want[i]) {
+				t.Errorf("param %d got %+v, want %+v", i, *p, tt.want[i])
+			}
+		}
+	}
+	if api.QueryType != "view.ListReq" {
+		t.Errorf("query type got %s, want view.ListReq", api.QueryType)
+	}
+}
+
+func TestParseSimple_Errors(t *testing.T) {
+	api, err := parseSimple("@path /user\n@errors errcode.Code\n@error 10023 10024,10025")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if api.ErrorType != "errcode.Code" {
+		t.Errorf("error type got %s, want errcode.Code", api.ErrorType)
+	}
+	if want := []string{"10023", "10024", "10025"}; !reflect.DeepEqual(api.Errors, want) {
+		t.Errorf("errors got %v, want %v", api.Errors, want)
+	}
+}
+
 /*
 import (
 	"encoding/json"
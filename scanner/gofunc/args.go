@@ -2,4 +2,5 @@ package gofunc
 
 const (
 	EnableGoModule = "enable_go_mod"
+	DiscoverRoutes = "discover_routes"
 )
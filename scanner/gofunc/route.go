@@ -0,0 +1,723 @@
+package gofunc
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// route is a handler registration found in the router code like:
+//
+//	r.GET("/user/:id", h.GetUser)
+//	r.Get("/user/{id}", GetUser)
+//	http.HandleFunc("/user", GetUser)
+//	r.HandleFunc("/user/{id}", GetUser).Methods("GET")
+type route struct {
+	method  string
+	path    string
+	pkg     string // package name of the handler,empty if the handler is a method value of unknown type
+	recv    string // receiver type name if the handler is a method value
+	handler string
+	pos     token.Position
+}
+
+// typeRef is a named type,eg. *handler.User => {handler User}
+type typeRef struct {
+	pkg  string
+	name string
+}
+
+var routeVerbs = map[string]string{
+	// gin,echo
+	"GET": "GET", "POST": "POST", "PUT": "PUT", "DELETE": "DELETE", "PATCH": "PATCH",
+	"HEAD": "HEAD", "OPTIONS": "OPTIONS", "CONNECT": "CONNECT", "TRACE": "TRACE",
+	// chi
+	"Get": "GET", "Post": "POST", "Put": "PUT", "Delete": "DELETE", "Patch": "PATCH",
+	"Head": "HEAD", "Options": "OPTIONS", "Connect": "CONNECT", "Trace": "TRACE",
+}
+
+type routeFunc struct {
+	decl    *ast.FuncDecl
+	pkg     string
+	imports map[string]string // local name => import path
+	echo    bool              // echo handler is the second argument
+}
+
+// routeScope is the state of a func body being walked
+type routeScope struct {
+	fn       *routeFunc
+	base     string              // prefix of the routers created in the func,set if the func is mounted
+	prefixes map[string]string   // router variable => path prefix
+	types    map[string]*typeRef // variable => type,used to find the receiver of method value handlers
+	routes   *[]*route
+}
+
+type routeFinder struct {
+	fileset  *token.FileSet
+	funcs    map[string][]*routeFunc // pkg.Name for functions and .Name for methods
+	vars     map[string]*typeRef     // pkg.Name => type of package level variables
+	handled  map[*ast.CallExpr]bool
+	grouped  map[*ast.FuncDecl]bool // called with a router that has prefix
+	visiting map[*ast.FuncDecl]bool
+}
+
+// findRoutes find the routes registered in packages,the routes are grouped by handler name.
+// Router groups are followed if the group is a variable,a chi sub router
+// or passed to another func of the packages.
+func findRoutes(fileset *token.FileSet, pkgs []*ast.Package) map[string][]*route {
+	f := &routeFinder{
+		fileset:  fileset,
+		funcs:    make(map[string][]*routeFunc),
+		vars:     make(map[string]*typeRef),
+		handled:  make(map[*ast.CallExpr]bool),
+		grouped:  make(map[*ast.FuncDecl]bool),
+		visiting: make(map[*ast.FuncDecl]bool),
+	}
+	var funcs []*routeFunc
+	var varSpecs []*ast.ValueSpec
+	var varFuncs []*routeFunc
+	for _, pkg := range pkgs {
+		fileNames := make([]string, 0, len(pkg.Files))
+		for name := range pkg.Files {
+			fileNames = append(fileNames, name)
+		}
+		sort.Strings(fileNames)
+		for _, name := range fileNames {
+			file := pkg.Files[name]
+			imports, echo := fileImports(file)
+			for _, decl := range file.Decls {
+				if gd, ok := decl.(*ast.GenDecl); ok && gd.Tok == token.VAR {
+					for _, spec := range gd.Specs {
+						varSpecs = append(varSpecs, spec.(*ast.ValueSpec))
+						varFuncs = append(varFuncs, &routeFunc{pkg: pkg.Name, imports: imports})
+					}
+				}
+				fd, ok := decl.(*ast.FuncDecl)
+				if !ok || fd.Body == nil {
+					continue
+				}
+				fn := &routeFunc{decl: fd, pkg: pkg.Name, imports: imports, echo: echo}
+				key := pkg.Name + "." + fd.Name.Name
+				if fd.Recv != nil {
+					key = "." + fd.Name.Name
+				}
+				f.funcs[key] = append(f.funcs[key], fn)
+				funcs = append(funcs, fn)
+			}
+		}
+	}
+
+	// the types of package level variables may be the results of funcs
+	for i, spec := range varSpecs {
+		types := make(map[string]*typeRef)
+		f.valueSpec(spec, &routeScope{fn: varFuncs[i], types: types})
+		for name, typ := range types {
+			f.vars[varFuncs[i].pkg+"."+name] = typ
+		}
+	}
+
+	found := make(map[*ast.FuncDecl][]*route)
+	for _, fn := range funcs {
+		found[fn.decl] = f.run(fn, "", nil)
+	}
+	routes := make(map[string][]*route)
+	seen := make(map[string]bool)
+	for _, fn := range funcs {
+		// the routes of a grouped func are found from the caller
+		if f.grouped[fn.decl] {
+			continue
+		}
+		for _, r := range found[fn.decl] {
+			key := strings.Join([]string{r.method, r.path, r.pkg, r.handler}, " ")
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			routes[r.handler] = append(routes[r.handler], r)
+		}
+	}
+	return routes
+}
+
+func (f *routeFinder) run(fn *routeFunc, base string, params map[string]string) []*route {
+	if f.visiting[fn.decl] {
+		return nil
+	}
+	f.visiting[fn.decl] = true
+	defer delete(f.visiting, fn.decl)
+	if params == nil {
+		params = make(map[string]string)
+	}
+	var routes []*route
+	s := &routeScope{fn: fn, base: base, prefixes: params, types: make(map[string]*typeRef), routes: &routes}
+	if fn.decl.Recv != nil {
+		f.paramTypes(fn.decl.Recv, s)
+	}
+	f.paramTypes(fn.decl.Type.Params, s)
+	f.walk(fn.decl.Body, s)
+	return routes
+}
+
+// paramTypes add the types of params to scope
+func (f *routeFinder) paramTypes(params *ast.FieldList, s *routeScope) {
+	if params == nil {
+		return
+	}
+	for _, field := range params.List {
+		typ := f.typeOf(field.Type, s)
+		for _, name := range field.Names {
+			if typ != nil {
+				s.types[name.Name] = typ
+			} else {
+				delete(s.types, name.Name)
+			}
+		}
+	}
+}
+
+// valueSpec add the types of variables declared by `var` to scope
+func (f *routeFinder) valueSpec(spec *ast.ValueSpec, s *routeScope) {
+	for i, id := range spec.Names {
+		var typ *typeRef
+		switch {
+		case spec.Type != nil:
+			typ = f.typeOf(spec.Type, s)
+		case len(spec.Names) == len(spec.Values):
+			typ = f.valueType(spec.Values[i], s)
+		case len(spec.Values) == 1 && i == 0:
+			// var h, err = NewUserHandler()
+			typ = f.valueType(spec.Values[0], s)
+		}
+		if typ != nil {
+			s.types[id.Name] = typ
+		}
+	}
+}
+
+// typeOf returns the named type of type expression,returns nil if it's not a named type
+func (f *routeFinder) typeOf(expr ast.Expr, s *routeScope) *typeRef {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return f.typeOf(e.X, s)
+	case *ast.StarExpr:
+		return f.typeOf(e.X, s)
+	case *ast.IndexExpr:
+		return f.typeOf(e.X, s)
+	case *ast.IndexListExpr:
+		return f.typeOf(e.X, s)
+	case *ast.Ident:
+		return &typeRef{pkg: s.fn.pkg, name: e.Name}
+	case *ast.SelectorExpr:
+		if id, ok := e.X.(*ast.Ident); ok {
+			if path, ok := s.fn.imports[id.Name]; ok {
+				return &typeRef{pkg: importName(path), name: e.Sel.Name}
+			}
+		}
+	}
+	return nil
+}
+
+// valueType returns the named type of value expression,
+// the value is a composite literal,new(T) or the result of a func in packages
+func (f *routeFinder) valueType(expr ast.Expr, s *routeScope) *typeRef {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return f.valueType(e.X, s)
+	case *ast.UnaryExpr:
+		return f.valueType(e.X, s)
+	case *ast.CompositeLit:
+		if e.Type != nil {
+			return f.typeOf(e.Type, s)
+		}
+	case *ast.Ident:
+		if typ, ok := s.types[e.Name]; ok {
+			return typ
+		}
+		return f.vars[s.fn.pkg+"."+e.Name]
+	case *ast.CallExpr:
+		if id, ok := e.Fun.(*ast.Ident); ok && id.Name == "new" && len(e.Args) == 1 {
+			return f.typeOf(e.Args[0], s)
+		}
+		fn := f.callee(e, s)
+		if fn == nil || fn.decl.Type.Results == nil || len(fn.decl.Type.Results.List) == 0 {
+			return nil
+		}
+		// the first result of constructor,eg. NewUserHandler() (*UserHandler, error)
+		result := fn.decl.Type.Results.List[0].Type
+		return f.typeOf(result, &routeScope{fn: fn})
+	}
+	return nil
+}
+
+func (f *routeFinder) walk(node ast.Node, s *routeScope) {
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			if len(n.Lhs) != len(n.Rhs) {
+				// h, err := NewUserHandler()
+				if id, ok := n.Lhs[0].(*ast.Ident); ok && len(n.Rhs) == 1 {
+					if typ := f.valueType(n.Rhs[0], s); typ != nil {
+						s.types[id.Name] = typ
+					}
+				}
+				return true
+			}
+			for i, lhs := range n.Lhs {
+				if id, ok := lhs.(*ast.Ident); ok {
+					if p, ok := f.prefix(n.Rhs[i], s); ok {
+						s.prefixes[id.Name] = p
+					}
+					if typ := f.valueType(n.Rhs[i], s); typ != nil {
+						s.types[id.Name] = typ
+					}
+				}
+			}
+		case *ast.ValueSpec:
+			f.valueSpec(n, s)
+			if len(n.Names) != len(n.Values) {
+				return true
+			}
+			for i, id := range n.Names {
+				if p, ok := f.prefix(n.Values[i], s); ok {
+					s.prefixes[id.Name] = p
+				}
+			}
+		case *ast.CallExpr:
+			return f.call(n, s)
+		}
+		return true
+	})
+}
+
+// prefix returns the path prefix of a router expression,
+// ok is false if the expression is not a known router group
+func (f *routeFinder) prefix(expr ast.Expr, s *routeScope) (p string, ok bool) {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return f.prefix(e.X, s)
+	case *ast.StarExpr:
+		return f.prefix(e.X, s)
+	case *ast.UnaryExpr:
+		return f.prefix(e.X, s)
+	case *ast.Ident:
+		p, ok = s.prefixes[e.Name]
+		return p, ok
+	case *ast.CallExpr:
+		sel, isSel := e.Fun.(*ast.SelectorExpr)
+		if !isSel {
+			return "", false
+		}
+		switch sel.Sel.Name {
+		case "Group", "Route", "PathPrefix":
+			// gin,echo: r.Group("/api") chi: r.Route("/api",fn) mux: r.PathPrefix("/api")
+			if path, ok := stringArg(e, 0); ok {
+				return joinPath(f.receiverPrefix(sel.X, s), path), true
+			}
+		case "Subrouter", "With":
+			return f.receiverPrefix(sel.X, s), true
+		}
+	}
+	return "", false
+}
+
+func (f *routeFinder) receiverPrefix(x ast.Expr, s *routeScope) string {
+	if p, ok := f.prefix(x, s); ok {
+		return p
+	}
+	return s.base
+}
+
+// call find the routes of a call expression,returns false if the children are walked
+func (f *routeFinder) call(n *ast.CallExpr, s *routeScope) bool {
+	sel, ok := n.Fun.(*ast.SelectorExpr)
+	if !ok {
+		if id, ok := n.Fun.(*ast.Ident); ok {
+			f.callFunc(f.lookup(s.fn.pkg+"."+id.Name), n.Args, s)
+		}
+		return true
+	}
+	if f.handled[n] {
+		return true
+	}
+	prefix := f.receiverPrefix(sel.X, s)
+	name := sel.Sel.Name
+	if method, ok := routeVerbs[name]; ok {
+		// gin: r.GET(path, middleware..., h) echo: e.GET(path, h, middleware...) chi: r.Get(path, h)
+		handler := len(n.Args) - 1
+		if s.fn.echo || name != strings.ToUpper(name) {
+			handler = 1
+		}
+		f.add(s, []string{method}, prefix, n, 0, handler)
+		return true
+	}
+	switch name {
+	case "Any":
+		handler := len(n.Args) - 1
+		if s.fn.echo {
+			handler = 1
+		}
+		f.add(s, nil, prefix, n, 0, handler)
+	case "Match":
+		// echo: e.Match(methods, path, h, middleware...) gin: r.Match(methods, path, handlers...)
+		if len(n.Args) < 3 {
+			return true
+		}
+		var methods []string
+		if lit, ok := n.Args[0].(*ast.CompositeLit); ok {
+			for _, elt := range lit.Elts {
+				methods = append(methods, methodValue(elt))
+			}
+		}
+		handler := len(n.Args) - 1
+		if s.fn.echo {
+			handler = 2
+		}
+		f.add(s, methods, prefix, n, 1, handler)
+	case "Method", "MethodFunc", "Add":
+		// chi: r.Method(method, path, h) echo: e.Add(method, path, h)
+		if len(n.Args) >= 3 {
+			f.add(s, []string{methodValue(n.Args[0])}, prefix, n, 1, 2)
+		}
+	case "Handle", "HandleFunc":
+		if len(n.Args) == 0 {
+			return true
+		}
+		if method := methodValue(n.Args[0]); method != "" && len(n.Args) >= 3 {
+			// gin: r.Handle(method, path, handlers...)
+			f.add(s, []string{method}, prefix, n, 1, len(n.Args)-1)
+		} else {
+			f.add(s, nil, prefix, n, 0, 1)
+		}
+	case "Methods":
+		// mux: r.HandleFunc(path, h).Methods("GET")
+		inner, ok := sel.X.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		innerSel, ok := inner.Fun.(*ast.SelectorExpr)
+		if !ok || (innerSel.Sel.Name != "HandleFunc" && innerSel.Sel.Name != "Handle") {
+			return true
+		}
+		var methods []string
+		for _, arg := range n.Args {
+			methods = append(methods, methodValue(arg))
+		}
+		f.handled[inner] = true
+		f.add(s, methods, f.receiverPrefix(innerSel.X, s), inner, 0, 1)
+	case "Route", "Group":
+		// chi: r.Route("/api", func(r chi.Router) {...}) r.Group(func(r chi.Router) {...})
+		if len(n.Args) == 0 {
+			return true
+		}
+		lit, ok := n.Args[len(n.Args)-1].(*ast.FuncLit)
+		if !ok {
+			return true
+		}
+		if len(n.Args) == 2 {
+			path, ok := stringArg(n, 0)
+			if !ok {
+				return true
+			}
+			prefix = joinPath(prefix, path)
+		}
+		f.funcLit(lit, prefix, s)
+		return false
+	case "Mount":
+		// chi: r.Mount("/admin", adminRouter())
+		path, ok := stringArg(n, 0)
+		if !ok || len(n.Args) < 2 {
+			return true
+		}
+		if call, ok := n.Args[1].(*ast.CallExpr); ok {
+			if fn := f.callee(call, s); fn != nil {
+				f.grouped[fn.decl] = true
+				*s.routes = append(*s.routes, f.run(fn, joinPath(prefix, path), nil)...)
+			}
+		}
+	default:
+		f.callFunc(f.callee(n, s), n.Args, s)
+	}
+	return true
+}
+
+// funcLit walk the body of a func literal,the first param is the router with prefix
+func (f *routeFinder) funcLit(lit *ast.FuncLit, prefix string, s *routeScope) {
+	prefixes := make(map[string]string)
+	for k, v := range s.prefixes {
+		prefixes[k] = v
+	}
+	if names := paramNames(lit.Type); len(names) > 0 {
+		prefixes[names[0]] = prefix
+	}
+	types := make(map[string]*typeRef)
+	for k, v := range s.types {
+		types[k] = v
+	}
+	scope := &routeScope{fn: s.fn, base: s.base, prefixes: prefixes, types: types, routes: s.routes}
+	f.paramTypes(lit.Type.Params, scope)
+	f.walk(lit.Body, scope)
+}
+
+// callFunc walk the func which is called with router groups
+func (f *routeFinder) callFunc(fn *routeFunc, args []ast.Expr, s *routeScope) {
+	if fn == nil {
+		return
+	}
+	names := paramNames(fn.decl.Type)
+	params := make(map[string]string)
+	grouped := s.base != ""
+	for i, arg := range args {
+		if i >= len(names) {
+			break
+		}
+		if p, ok := f.prefix(arg, s); ok {
+			params[names[i]] = p
+			grouped = grouped || p != ""
+		}
+	}
+	if !grouped {
+		return
+	}
+	f.grouped[fn.decl] = true
+	*s.routes = append(*s.routes, f.run(fn, s.base, params)...)
+}
+
+// callee returns the func declared in the packages of call
+func (f *routeFinder) callee(call *ast.CallExpr, s *routeScope) *routeFunc {
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		return f.lookup(s.fn.pkg + "." + fun.Name)
+	case *ast.SelectorExpr:
+		if id, ok := fun.X.(*ast.Ident); ok {
+			if path, ok := s.fn.imports[id.Name]; ok {
+				return f.lookup(importName(path) + "." + fun.Sel.Name)
+			}
+		}
+		return f.lookup("." + fun.Sel.Name)
+	}
+	return nil
+}
+
+// lookup returns the func if it's unique
+func (f *routeFinder) lookup(key string) *routeFunc {
+	if funcs := f.funcs[key]; len(funcs) == 1 {
+		return funcs[0]
+	}
+	return nil
+}
+
+// add the routes registered by call,the path and the handler are args of call
+func (f *routeFinder) add(s *routeScope, methods []string, prefix string, call *ast.CallExpr, pathArg, handlerArg int) {
+	if handlerArg <= pathArg || handlerArg >= len(call.Args) {
+		return
+	}
+	path, ok := stringArg(call, pathArg)
+	if !ok {
+		return
+	}
+	if len(methods) == 0 {
+		// pattern of net/http since go1.22: "GET /user/{id}"
+		if i := strings.Index(path, " "); i != -1 {
+			methods = []string{path[:i]}
+			path = strings.TrimSpace(path[i+1:])
+		}
+	}
+	// not a route,eg. http.Post(url, contentType, body)
+	if path != "" && !strings.HasPrefix(path, "/") {
+		return
+	}
+	pkg, recv, handler := f.handlerName(call.Args[handlerArg], s)
+	if handler == "" {
+		return
+	}
+	if len(methods) == 0 {
+		methods = []string{""}
+	}
+	for _, method := range methods {
+		*s.routes = append(*s.routes, &route{
+			method:  strings.ToUpper(method),
+			path:    joinPath(prefix, path),
+			pkg:     pkg,
+			recv:    recv,
+			handler: handler,
+			pos:     f.fileset.Position(call.Pos()),
+		})
+	}
+}
+
+// handlerName returns the package,receiver type and name of handler func,
+// the receiver is the type of method value like h.GetUser,
+// the package and receiver are empty if the type of method value is unknown
+func (f *routeFinder) handlerName(expr ast.Expr, s *routeScope) (pkg, recv, name string) {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return f.handlerName(e.X, s)
+	case *ast.UnaryExpr:
+		return f.handlerName(e.X, s)
+	case *ast.Ident:
+		return s.fn.pkg, "", e.Name
+	case *ast.SelectorExpr:
+		if id, ok := e.X.(*ast.Ident); ok {
+			if path, ok := s.fn.imports[id.Name]; ok {
+				return importName(path), "", e.Sel.Name
+			}
+		}
+		if typ := f.valueType(e.X, s); typ != nil {
+			return typ.pkg, typ.name, e.Sel.Name
+		}
+		return "", "", e.Sel.Name
+	case *ast.CallExpr:
+		// handler factory like h.GetUser()
+		if _, ok := e.Fun.(*ast.SelectorExpr); ok && len(e.Args) != 1 {
+			return f.handlerName(e.Fun, s)
+		}
+		// wrapper like http.HandlerFunc(GetUser)
+		if len(e.Args) == 1 {
+			return f.handlerName(e.Args[0], s)
+		}
+	}
+	return "", "", ""
+}
+
+func fileImports(file *ast.File) (imports map[string]string, echo bool) {
+	imports = make(map[string]string)
+	for _, spec := range file.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := importName(path)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		imports[name] = path
+		if strings.HasPrefix(path, "github.com/labstack/echo") {
+			echo = true
+		}
+	}
+	return imports, echo
+}
+
+var reMajorVersion = regexp.MustCompile(`^v[0-9]+$`)
+
+// importName returns the default package name of import path
+//
+//	github.com/labstack/echo/v4 => echo
+func importName(path string) string {
+	elems := strings.Split(path, "/")
+	name := elems[len(elems)-1]
+	if reMajorVersion.MatchString(name) && len(elems) > 1 {
+		name = elems[len(elems)-2]
+	}
+	return name
+}
+
+func paramNames(typ *ast.FuncType) []string {
+	var names []string
+	if typ.Params == nil {
+		return names
+	}
+	for _, field := range typ.Params.List {
+		if len(field.Names) == 0 {
+			names = append(names, "")
+		}
+		for _, name := range field.Names {
+			names = append(names, name.Name)
+		}
+	}
+	return names
+}
+
+func stringArg(call *ast.CallExpr, i int) (string, bool) {
+	if i >= len(call.Args) {
+		return "", false
+	}
+	lit, ok := call.Args[i].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	s, err := strconv.Unquote(lit.Value)
+	return s, err == nil
+}
+
+// methodValue returns the http method of "GET" or http.MethodGet
+func methodValue(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if s, err := strconv.Unquote(e.Value); err == nil && routeVerbs[strings.ToUpper(s)] != "" {
+			return strings.ToUpper(s)
+		}
+	case *ast.SelectorExpr:
+		if strings.HasPrefix(e.Sel.Name, "Method") {
+			return routeVerbs[strings.ToUpper(strings.TrimPrefix(e.Sel.Name, "Method"))]
+		}
+	}
+	return ""
+}
+
+func joinPath(prefix, path string) string {
+	if path == "" {
+		return prefix
+	}
+	if prefix == "" {
+		return path
+	}
+	return strings.TrimSuffix(prefix, "/") + "/" + strings.TrimPrefix(path, "/")
+}
+
+var rePathParam = regexp.MustCompile(`[:*](\w+)|{(\w+)(:[^}]*)?}`)
+
+// samePath report whether the paths are the same route,
+// the path params of different router are treated as the same
+//
+//	/user/:id == /user/{id} == /user/{id:[0-9]+}
+func samePath(a, b string) bool {
+	normalize := func(path string) string {
+		path = rePathParam.ReplaceAllString(path, "{$1$2}")
+		if path != "/" {
+			path = strings.TrimSuffix(path, "/")
+		}
+		return path
+	}
+	return normalize(a) == normalize(b)
+}
+
+// applyRoutes attach the path and method of routes to annotation,
+// an annotation is copied for each route if the handler is registered more than once.
+// The explicit `@path` is kept,a warning is printed if it's not match the router.
+func applyRoutes(annotation DocAnnotation, handler string, routes []*route) []DocAnnotation {
+	if len(routes) == 0 {
+		return []DocAnnotation{annotation}
+	}
+	api, _ := parseSimple(annotation)
+	if api.Path != "" {
+		for _, r := range routes {
+			if samePath(r.path, api.Path) && (api.Method == "" || r.method == "" || strings.EqualFold(api.Method, r.method)) {
+				return []DocAnnotation{annotation}
+			}
+		}
+		var registered []string
+		for _, r := range routes {
+			registered = append(registered, fmt.Sprintf("%s %s(%s)", r.method, r.path, r.pos))
+		}
+		fmt.Printf("warning: %s: @path %s @method %s is not match the router: %s\n",
+			handler, api.Path, api.Method, strings.Join(registered, ", "))
+		return []DocAnnotation{annotation}
+	}
+	if !strings.HasSuffix(string(annotation), "\n") {
+		annotation += "\n"
+	}
+	annotations := make([]DocAnnotation, 0, len(routes))
+	for _, r := range routes {
+		cmd := "@path " + r.path
+		if r.method != "" && api.Method == "" {
+			cmd += " @method " + r.method
+		}
+		annotations = append(annotations, annotation+DocAnnotation(cmd+"\n"))
+	}
+	return annotations
+}
@@ -0,0 +1,109 @@
+package gofunc
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestFindRoutes(t *testing.T) {
+	fileset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fileset, "testdata/router", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	routes := findRoutes(fileset, []*ast.Package{pkgs["router"]})
+	got := make(map[string][]string)
+	for handler, handlerRoutes := range routes {
+		for _, r := range handlerRoutes {
+			got[handler] = append(got[handler], strings.TrimSpace(r.method+" "+r.path))
+		}
+		sort.Strings(got[handler])
+	}
+	want := map[string][]string{
+		"Ping":       {"GET /ping"},
+		"GetUser":    {"GET /api/v1/user/:id", "GET /chi/user/{id}", "GET /echo/user/:id", "GET /me", "GET /mux/user/{id:[0-9]+}", "GET /posts/:id/user", "GET /std/user/{id}", "GET /users/:id", "HEAD /mux/user/{id:[0-9]+}"},
+		"UpdateUser": {"PUT /api/v1/user/:id"},
+		"CreatePost": {"POST /api/post"},
+		"DeleteUser": {"DELETE /chi/user/{id}"},
+		"CreateUser": {"POST /admin/user"},
+		"Health":     {"/health"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("findRoutes() got %v, want %v", got, want)
+	}
+}
+
+func TestMatchRoutes(t *testing.T) {
+	fileset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fileset, "testdata/router", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	routes := findRoutes(fileset, []*ast.Package{pkgs["router"]})["GetUser"]
+	// h.GetUser in testdata/router/gin.go is a method value of unknown type
+	tests := []struct {
+		pkg, recv string
+		want      []string
+	}{
+		{"router", "", []string{"GET /chi/user/{id}", "GET /echo/user/:id", "GET /mux/user/{id:[0-9]+}", "GET /std/user/{id}", "HEAD /mux/user/{id:[0-9]+}"}},
+		{"router", "UserHandler", []string{"GET /api/v1/user/:id", "GET /me", "GET /users/:id"}},
+		{"router", "PostHandler", []string{"GET /api/v1/user/:id", "GET /posts/:id/user"}},
+		{"other", "", nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, r := range matchRoutes(routes, tt.pkg, tt.recv) {
+			got = append(got, r.method+" "+r.path)
+		}
+		sort.Strings(got)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("matchRoutes(%s, %s) got %v, want %v", tt.pkg, tt.recv, got, tt.want)
+		}
+	}
+}
+
+func TestApplyRoutes(t *testing.T) {
+	routes := []*route{{method: "GET", path: "/user/:id"}, {method: "POST", path: "/user"}}
+	annotation := DocAnnotation("@doc 获取用户\n@tag user\n")
+	got := applyRoutes(annotation, "GetUser", routes)
+	want := []DocAnnotation{
+		"@doc 获取用户\n@tag user\n@path /user/:id @method GET\n",
+		"@doc 获取用户\n@tag user\n@path /user @method POST\n",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("applyRoutes() got %q, want %q", got, want)
+	}
+
+	// the explicit path is kept
+	for _, annotation := range []DocAnnotation{
+		"@doc 获取用户\n@path /user/{id} @method get\n",
+		"@doc 获取用户\n@path /users @method get\n",
+	} {
+		got := applyRoutes(annotation, "GetUser", routes)
+		if !reflect.DeepEqual(got, []DocAnnotation{annotation}) {
+			t.Errorf("applyRoutes() got %q, want %q", got, annotation)
+		}
+	}
+}
+
+func TestSamePath(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"/user/:id", "/user/{id}", true},
+		{"/user/{id:[0-9]+}/", "/user/:id", true},
+		{"/files/*path", "/files/{path}", true},
+		{"/user/:id", "/user/:id/profile", false},
+	}
+	for _, tt := range tests {
+		if got := samePath(tt.a, tt.b); got != tt.want {
+			t.Errorf("samePath(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -3,6 +3,8 @@ package gofunc
 import (
 	"github.com/thewinds/mkdoc"
 	"go/ast"
+	"go/token"
+	"sort"
 	"strings"
 )
 
@@ -11,15 +13,19 @@ func init() {
 }
 
 type Scanner struct {
-	enableGoMod bool
-	filterTag   string
-	pkg         string
+	enableGoMod    bool
+	discoverRoutes bool
+	filterTag      string
+	pkg            string
 }
 
 func (s *Scanner) Scan(config mkdoc.DocScanConfig) (*mkdoc.DocScanResult, error) {
 	if config.Args[EnableGoModule] == "true" {
 		s.enableGoMod = true
 	}
+	if config.Args[DiscoverRoutes] == "true" {
+		s.discoverRoutes = true
+	}
 	s.filterTag = config.Args["_filter_tag"]
 	if len(config.Args["pkg"]) > 0 {
 		s.pkg = config.Args["pkg"]
@@ -64,6 +70,10 @@ func (s *Scanner) Scan(config mkdoc.DocScanConfig) (*mkdoc.DocScanResult, error)
 func (s *Scanner) scanAnnotations() ([]DocAnnotation, error) {
 	annotations := make([]DocAnnotation, 0)
 	dirs := mkdoc.GetScanDirs(s.pkg, s.enableGoMod, nil)
+	routes, err := s.scanRoutes(dirs)
+	if err != nil {
+		return nil, err
+	}
 	for _, dir := range dirs {
 
 		pkgs, fileset, err := mkdoc.ParseDir(dir)
@@ -75,8 +85,11 @@ func (s *Scanner) scanAnnotations() ([]DocAnnotation, error) {
 			ast.Inspect(v, func(node ast.Node) bool {
 				if funcNode, ok := node.(*ast.FuncDecl); ok {
 					if annotation := GetAnnotationFromComment(funcNode.Doc.Text()); annotation != "" {
-						annotation = annotation.AppendMetaData("http", fileset.Position(funcNode.Doc.Pos()))
-						annotations = append(annotations, annotation)
+						handlerRoutes := matchRoutes(routes[funcNode.Name.Name], v.Name, recvName(funcNode))
+						for _, annotation := range applyRoutes(annotation, funcNode.Name.Name, handlerRoutes) {
+							annotation = annotation.AppendMetaData("http", fileset.Position(funcNode.Doc.Pos()))
+							annotations = append(annotations, annotation)
+						}
 					}
 				}
 				return true
@@ -86,6 +99,79 @@ func (s *Scanner) scanAnnotations() ([]DocAnnotation, error) {
 	return annotations, nil
 }
 
+// scanRoutes find the routes registered to gin,echo,chi,gorilla/mux and net/http routers
+func (s *Scanner) scanRoutes(dirs []string) (map[string][]*route, error) {
+	if !s.discoverRoutes {
+		return nil, nil
+	}
+	var pkgs []*ast.Package
+	var fileset *token.FileSet
+	for _, dir := range dirs {
+		dirPkgs, fs, err := mkdoc.ParseDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		fileset = fs
+		names := make([]string, 0, len(dirPkgs))
+		for name := range dirPkgs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			pkgs = append(pkgs, dirPkgs[name])
+		}
+	}
+	return findRoutes(fileset, pkgs), nil
+}
+
+// matchRoutes returns the routes of the handler in package pkg,
+// recv is the receiver type name if the handler is a method.
+// The method values of unknown type match all the methods of the name.
+func matchRoutes(routes []*route, pkg string, recv string) []*route {
+	var matched []*route
+	for _, r := range routes {
+		var ok bool
+		switch {
+		case r.recv != "":
+			ok = r.recv == recv && r.pkg == pkg
+		case r.pkg == "":
+			ok = recv != ""
+		default:
+			ok = recv == "" && r.pkg == pkg
+		}
+		if ok {
+			matched = append(matched, r)
+		}
+	}
+	return matched
+}
+
+// recvName returns the receiver type name of method,empty if it's a function
+//
+//	func (h *UserHandler[T]) GetUser() => UserHandler
+func recvName(fd *ast.FuncDecl) string {
+	if fd.Recv == nil || len(fd.Recv.List) == 0 {
+		return ""
+	}
+	typ := fd.Recv.List[0].Type
+	for {
+		switch t := typ.(type) {
+		case *ast.StarExpr:
+			typ = t.X
+		case *ast.ParenExpr:
+			typ = t.X
+		case *ast.IndexExpr:
+			typ = t.X
+		case *ast.IndexListExpr:
+			typ = t.X
+		case *ast.Ident:
+			return t.Name
+		default:
+			return ""
+		}
+	}
+}
+
 func (s *Scanner) Name() string {
 	return "gofunc"
 }
@@ -0,0 +1,9 @@
+package router
+
+import "github.com/labstack/echo/v4"
+
+func Echo() {
+	e := echo.New()
+	g := e.Group("/echo")
+	g.GET("/user/:id", GetUser, middleware)
+}
@@ -0,0 +1,47 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-chi/chi"
+	"github.com/gorilla/mux"
+)
+
+func Gin() {
+	r := gin.Default()
+	r.GET("/ping", Ping)
+	api := r.Group("/api")
+	v1 := api.Group("/v1")
+	v1.GET("/user/:id", auth, h.GetUser)
+	v1.Handle("PUT", "/user/:id", UpdateUser)
+	registerPost(api.Group("/post"))
+}
+
+func registerPost(g *gin.RouterGroup) {
+	g.POST("", CreatePost)
+}
+
+func Chi() {
+	r := chi.NewRouter()
+	r.Route("/chi", func(r chi.Router) {
+		r.Get("/user/{id}", GetUser)
+		r.Method(http.MethodDelete, "/user/{id}", http.HandlerFunc(DeleteUser))
+	})
+	r.Mount("/admin", adminRouter())
+}
+
+func adminRouter() chi.Router {
+	r := chi.NewRouter()
+	r.Post("/user", CreateUser)
+	return r
+}
+
+func Std() {
+	http.HandleFunc("/health", Health)
+	http.HandleFunc("GET /std/user/{id}", GetUser)
+	r := mux.NewRouter()
+	s := r.PathPrefix("/mux").Subrouter()
+	s.HandleFunc("/user/{id:[0-9]+}", GetUser).Methods("GET", http.MethodHead)
+	http.Post("http://localhost/user", "application/json", nil)
+}
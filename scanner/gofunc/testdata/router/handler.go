@@ -0,0 +1,29 @@
+package router
+
+import "github.com/gin-gonic/gin"
+
+type UserHandler struct{}
+
+func (h *UserHandler) GetUser(c *gin.Context) {}
+
+// Routes register the routes of the receiver
+func (h *UserHandler) Routes(r *gin.Engine) {
+	r.GET("/me", h.GetUser)
+}
+
+type PostHandler struct{}
+
+func NewPostHandler() (*PostHandler, error) {
+	return &PostHandler{}, nil
+}
+
+func (h PostHandler) GetUser(c *gin.Context) {}
+
+var posts, _ = NewPostHandler()
+
+func Methods() {
+	r := gin.Default()
+	users := &UserHandler{}
+	r.GET("/users/:id", users.GetUser)
+	r.GET("/posts/:id/user", posts.GetUser)
+}
@@ -0,0 +1,285 @@
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/thewinds/mkdoc"
+	"github.com/thewinds/mkdoc/schema"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func init() {
+	mkdoc.RegisterDocScanner(new(Scanner))
+}
+
+// objects are loaded by the graphql loader
+const lang = "graphql"
+
+var builtinScalars = map[string]string{
+	"Int":     "int32",
+	"Float":   "float64",
+	"String":  "string",
+	"Boolean": "bool",
+	"ID":      "string",
+}
+
+var operations = []string{"query", "mutation", "subscription"}
+
+type Scanner struct {
+	filterTag string
+	path      string
+	endpoint  string
+}
+
+func (s *Scanner) Scan(config mkdoc.DocScanConfig) (*mkdoc.DocScanResult, error) {
+	s.filterTag = config.Args["_filter_tag"]
+	s.path = config.Args["path"]
+	s.endpoint = config.Args["endpoint"]
+	if s.endpoint == "" {
+		s.endpoint = "/graphql"
+	}
+	c := &converter{
+		types: make(map[string]*typeDef),
+		roots: map[string]string{"query": "Query", "mutation": "Mutation", "subscription": "Subscription"},
+	}
+	err := filepath.Walk(s.path, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		ext := filepath.Ext(path)
+		if info.IsDir() || (ext != ".graphql" && ext != ".graphqls" && ext != ".gql") {
+			return nil
+		}
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		doc, err := parseSDL(string(b))
+		if err != nil {
+			return fmt.Errorf("graphql: %s:%v", path, err)
+		}
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return err
+		}
+		c.merge(doc, absPath)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := c.check(); err != nil {
+		return nil, fmt.Errorf("graphql: %v", err)
+	}
+	r := new(mkdoc.DocScanResult)
+	for _, name := range c.order {
+		if !c.isRoot(name) {
+			c.objects = append(c.objects, c.convertTypeDef(c.types[name]))
+		}
+	}
+	for _, op := range operations {
+		root := c.types[c.roots[op]]
+		if root == nil {
+			continue
+		}
+		for _, field := range root.fields {
+			api := c.convertOperation(op, field)
+			api.Path = s.endpoint
+			if s.matchTag(api) {
+				r.APIs = append(r.APIs, api)
+			}
+		}
+	}
+	r.Objects = c.objects
+	return r, nil
+}
+
+func (s *Scanner) matchTag(api *schema.API) bool {
+	if len(s.filterTag) == 0 {
+		return true
+	}
+	for _, tag := range api.Tags {
+		if tag == strings.TrimSpace(s.filterTag) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Scanner) Name() string {
+	return "graphql"
+}
+
+func (s *Scanner) Help() string {
+	return "scan doc from GraphQL SDL files"
+}
+
+type converter struct {
+	types   map[string]*typeDef
+	order   []string
+	roots   map[string]string
+	objects []*schema.Object
+}
+
+// merge the definitions of a document,type extensions are merged to the type
+func (c *converter) merge(doc *document, fileName string) {
+	for op, name := range doc.roots {
+		c.roots[op] = name
+	}
+	for _, def := range doc.types {
+		for _, field := range def.fields {
+			field.fileName = fileName
+		}
+		exist := c.types[def.name]
+		if exist == nil {
+			c.types[def.name] = def
+			c.order = append(c.order, def.name)
+			continue
+		}
+		exist.fields = append(exist.fields, def.fields...)
+		exist.values = append(exist.values, def.values...)
+		if !def.extend {
+			exist.kind = def.kind
+			exist.desc = def.desc
+		}
+	}
+}
+
+// check all the referenced types are defined
+func (c *converter) check() error {
+	var checkFields func(owner string, fields []*fieldDef) error
+	checkFields = func(owner string, fields []*fieldDef) error {
+		for _, field := range fields {
+			name := field.typ.namedType()
+			if builtinScalars[name] == "" && c.types[name] == nil {
+				return fmt.Errorf("%s.%s: type %s is not defined", owner, field.name, name)
+			}
+			if err := checkFields(owner+"."+field.name, field.args); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, name := range c.order {
+		if err := checkFields(name, c.types[name].fields); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *converter) isRoot(name string) bool {
+	for _, root := range c.roots {
+		if root == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *converter) convertTypeDef(def *typeDef) *schema.Object {
+	obj := &schema.Object{ID: def.name, Language: lang}
+	ext := &mkdoc.ExtensionGraphQL{Kind: def.kind}
+	switch def.kind {
+	case "enum":
+		obj.Type = &schema.ObjectType{Name: "string"}
+		ext.Values = def.values
+	case "union":
+		obj.Type = &schema.ObjectType{Name: "interface{}"}
+		ext.Values = def.values
+	case "scalar":
+		// custom scalars are mostly serialized as string,eg. DateTime
+		obj.Type = &schema.ObjectType{Name: "string"}
+	default:
+		obj.Type = &schema.ObjectType{Name: "object"}
+		obj.Fields = c.convertFields(def.name, def.fields)
+	}
+	obj.Extensions = []*schema.Extension{graphqlExtension(ext)}
+	return obj
+}
+
+// convertOperation convert a field of root type to api,
+// the arguments and the return type are converted to anonymous objects
+func (c *converter) convertOperation(op string, field *fieldDef) *schema.API {
+	base := filepath.Base(field.fileName)
+	api := &schema.API{
+		Name:           field.name,
+		Desc:           field.desc,
+		Method:         op,
+		Type:           "graphql",
+		Tags:           []string{strings.TrimSuffix(base, filepath.Ext(base))},
+		MimeIn:         "graphql",
+		MimeOut:        "json",
+		SourceFileName: field.fileName,
+		Language:       lang,
+	}
+	idPrefix := op + "_" + field.name
+	if len(field.args) > 0 {
+		args := &schema.Object{
+			ID:       fmt.Sprintf("@graphql_%s_args", idPrefix),
+			Type:     &schema.ObjectType{Name: "object"},
+			Language: lang,
+			Fields:   c.convertFields(idPrefix, field.args),
+		}
+		c.objects = append(c.objects, args)
+		api.InType = args.ID
+	}
+	out := &schema.Object{
+		ID:         fmt.Sprintf("@graphql_%s_out", idPrefix),
+		Type:       c.convertType(idPrefix, field.typ),
+		Language:   lang,
+		Extensions: []*schema.Extension{graphqlExtension(&mkdoc.ExtensionGraphQL{Type: field.typ.String()})},
+	}
+	c.objects = append(c.objects, out)
+	api.OutType = out.ID
+	return api
+}
+
+func (c *converter) convertFields(owner string, fields []*fieldDef) []*schema.ObjectField {
+	objFields := make([]*schema.ObjectField, 0, len(fields))
+	for _, field := range fields {
+		ext := &mkdoc.ExtensionGraphQL{Type: field.typ.String(), Default: field.defaultValue}
+		typ := c.convertType(owner+"_"+field.name, field.typ)
+		objFields = append(objFields, newField(field.name, field.desc, typ, ext))
+	}
+	return objFields
+}
+
+// convertType convert type reference,lists are converted to array objects
+func (c *converter) convertType(idPrefix string, t *typeRef) *schema.ObjectType {
+	if t.elem != nil {
+		elem := c.convertType(idPrefix+"_elem", t.elem)
+		arr := &schema.Object{
+			ID:       fmt.Sprintf("@graphql_%s_arr", idPrefix),
+			Type:     &schema.ObjectType{Name: elem.Name, Ref: elem.Ref, IsRepeated: true},
+			Language: lang,
+		}
+		c.objects = append(c.objects, arr)
+		return &schema.ObjectType{Name: "object", Ref: arr.ID}
+	}
+	if scalar, ok := builtinScalars[t.name]; ok {
+		return &schema.ObjectType{Name: scalar}
+	}
+	return &schema.ObjectType{Name: "object", Ref: t.name}
+}
+
+func newField(name, desc string, typ *schema.ObjectType, ext *mkdoc.ExtensionGraphQL) *schema.ObjectField {
+	tagExt := &schema.Extension{
+		Name: "go_tag",
+		Data: json.RawMessage(fmt.Sprintf("%q", fmt.Sprintf(`json:"%s"`, name))),
+	}
+	return &schema.ObjectField{
+		Name:       name,
+		Desc:       desc,
+		Type:       typ,
+		Extensions: []*schema.Extension{tagExt, graphqlExtension(ext)},
+	}
+}
+
+func graphqlExtension(ext *mkdoc.ExtensionGraphQL) *schema.Extension {
+	s, _ := ext.Schema()
+	return s
+}
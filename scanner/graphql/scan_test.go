@@ -0,0 +1,76 @@
+package graphql
+
+import (
+	"encoding/json"
+	"github.com/thewinds/mkdoc"
+	"github.com/thewinds/mkdoc/schema"
+	"testing"
+)
+
+func TestScanner_Scan(t *testing.T) {
+	r, err := new(Scanner).Scan(mkdoc.DocScanConfig{Args: map[string]string{"path": "testdata"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name, method, tag, in, out string
+	}{
+		// post.graphql is walked before user.graphql
+		{"post", "query", "post", "@graphql_query_post_args", "@graphql_query_post_out"},
+		{"user", "query", "user", "@graphql_query_user_args", "@graphql_query_user_out"},
+		{"users", "query", "user", "@graphql_query_users_args", "@graphql_query_users_out"},
+		{"now", "query", "user", "", "@graphql_query_now_out"},
+		{"createPost", "mutation", "post", "@graphql_mutation_createPost_args", "@graphql_mutation_createPost_out"},
+	}
+	if len(r.APIs) != len(tests) {
+		t.Fatalf("want %d apis got %d", len(tests), len(r.APIs))
+	}
+	for i, tt := range tests {
+		api := r.APIs[i]
+		if api.Name != tt.name || api.Method != tt.method || api.Tags[0] != tt.tag || api.InType != tt.in ||
+			api.OutType != tt.out || api.Type != "graphql" || api.Path != "/graphql" {
+			t.Errorf("api %d: want %v got %+v", i, tt, api)
+		}
+	}
+	objects := make(map[string]*schema.Object)
+	for _, obj := range r.Objects {
+		objects[obj.ID] = obj
+	}
+	graphqlExt := func(exts []*schema.Extension) *mkdoc.ExtensionGraphQL {
+		for _, ext := range exts {
+			if ext.Name == "graphql" {
+				e := new(mkdoc.ExtensionGraphQL)
+				json.Unmarshal(ext.Data, e)
+				return e
+			}
+		}
+		return nil
+	}
+
+	user := objects["User"]
+	if user == nil || len(user.Fields) != 6 || user.Fields[1].Desc != "用户名" {
+		t.Fatalf("unexpected user %+v", user)
+	}
+	if ext := graphqlExt(user.Fields[3].Extensions); ext == nil || ext.Type != "[User!]!" {
+		t.Errorf("unexpected friends ext %+v", ext)
+	}
+	if friends := objects[user.Fields[3].Type.Ref]; friends == nil || !friends.Type.IsRepeated || friends.Type.Ref != "User" {
+		t.Errorf("unexpected friends %+v", friends)
+	}
+	if ext := graphqlExt(objects["Role"].Extensions); ext == nil || ext.Kind != "enum" || len(ext.Values) != 2 {
+		t.Errorf("unexpected role ext %+v", ext)
+	}
+	if ext := graphqlExt(objects["SearchResult"].Extensions); ext == nil || ext.Kind != "union" || len(ext.Values) != 2 {
+		t.Errorf("unexpected union ext %+v", ext)
+	}
+	args := objects["@graphql_query_users_args"]
+	if ext := graphqlExt(args.Fields[1].Extensions); ext == nil || ext.Type != "Int" || ext.Default != "20" {
+		t.Errorf("unexpected args ext %+v", ext)
+	}
+	if out := objects["@graphql_query_user_out"]; out.Type.Ref != "User" {
+		t.Errorf("unexpected out %+v", out)
+	}
+	if objects["Query"] != nil {
+		t.Errorf("root type should not be an object")
+	}
+}
@@ -0,0 +1,538 @@
+package graphql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// document the type system definitions of GraphQL SDL files
+type document struct {
+	types []*typeDef
+	// operation => root type name,eg. query => Query
+	roots map[string]string
+}
+
+type typeDef struct {
+	kind   string // type input interface enum union scalar
+	name   string
+	desc   string
+	fields []*fieldDef
+	// enum values or union member types
+	values []string
+	extend bool
+}
+
+type fieldDef struct {
+	name         string
+	desc         string
+	typ          *typeRef
+	args         []*fieldDef
+	defaultValue string
+	// the file which defines the field
+	fileName string
+}
+
+// typeRef is a named type or a list type,
+//
+//	[User!]! => {elem: {name: User, nonNull: true}, nonNull: true}
+type typeRef struct {
+	name    string
+	elem    *typeRef
+	nonNull bool
+}
+
+func (t *typeRef) String() string {
+	s := t.name
+	if t.elem != nil {
+		s = "[" + t.elem.String() + "]"
+	}
+	if t.nonNull {
+		s += "!"
+	}
+	return s
+}
+
+// namedType returns the innermost named type
+func (t *typeRef) namedType() string {
+	if t.elem != nil {
+		return t.elem.namedType()
+	}
+	return t.name
+}
+
+type tokenKind int
+
+const (
+	tokName tokenKind = iota
+	tokString
+	tokNumber
+	tokPunct
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	line int
+}
+
+func tokenize(src string) ([]*token, error) {
+	var tokens []*token
+	src = strings.TrimPrefix(src, "\ufeff")
+	line := 1
+	i := 0
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r' || c == ',':
+			i++
+		case c == '#':
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(src[i:], `"""`):
+			end := strings.Index(src[i+3:], `"""`)
+			if end == -1 {
+				return nil, fmt.Errorf("%d: unterminated block string", line)
+			}
+			text := src[i+3 : i+3+end]
+			tokens = append(tokens, &token{tokString, blockString(text), line})
+			line += strings.Count(text, "\n")
+			i += end + 6
+		case c == '"':
+			j := i + 1
+			var sb strings.Builder
+			for j < len(src) && src[j] != '"' {
+				if src[j] == '\\' && j+1 < len(src) {
+					j++
+					switch src[j] {
+					case 'n':
+						sb.WriteByte('\n')
+					case 't':
+						sb.WriteByte('\t')
+					default:
+						sb.WriteByte(src[j])
+					}
+				} else {
+					sb.WriteByte(src[j])
+				}
+				j++
+			}
+			tokens = append(tokens, &token{tokString, sb.String(), line})
+			i = j + 1
+		case strings.HasPrefix(src[i:], "..."):
+			tokens = append(tokens, &token{tokPunct, "...", line})
+			i += 3
+		case isNameStart(c):
+			j := i
+			for j < len(src) && (isNameStart(src[j]) || isDigit(src[j])) {
+				j++
+			}
+			tokens = append(tokens, &token{tokName, src[i:j], line})
+			i = j
+		case isDigit(c) || c == '-':
+			j := i + 1
+			for j < len(src) && (isDigit(src[j]) || strings.IndexByte(".eE+-", src[j]) != -1) {
+				j++
+			}
+			tokens = append(tokens, &token{tokNumber, src[i:j], line})
+			i = j
+		default:
+			tokens = append(tokens, &token{tokPunct, string(c), line})
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+// blockString remove the common indentation and blank leading/trailing lines
+func blockString(s string) string {
+	lines := strings.Split(strings.Replace(s, `\"""`, `"""`, -1), "\n")
+	indent := -1
+	for i, l := range lines {
+		if i == 0 || strings.TrimSpace(l) == "" {
+			continue
+		}
+		n := len(l) - len(strings.TrimLeft(l, " \t"))
+		if indent == -1 || n < indent {
+			indent = n
+		}
+	}
+	for i := 1; i < len(lines) && indent > 0; i++ {
+		if len(lines[i]) >= indent {
+			lines[i] = lines[i][indent:]
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+type parser struct {
+	tokens []*token
+	pos    int
+}
+
+// parseSDL parse type system definitions,
+// executable definitions and directive definitions are skipped
+func parseSDL(src string) (*document, error) {
+	tokens, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	doc := &document{roots: make(map[string]string)}
+	for p.peek() != nil {
+		if err := p.parseDefinition(doc); err != nil {
+			return nil, err
+		}
+	}
+	return doc, nil
+}
+
+func (p *parser) peek() *token {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return nil
+}
+
+func (p *parser) next() *token {
+	t := p.peek()
+	if t != nil {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) is(text string) bool {
+	t := p.peek()
+	return t != nil && t.kind != tokString && t.text == text
+}
+
+func (p *parser) expect(text string) error {
+	t := p.next()
+	if t == nil {
+		return fmt.Errorf("expect '%s' but got EOF", text)
+	}
+	if t.text != text || t.kind == tokString {
+		return fmt.Errorf("%d: expect '%s' but got '%s'", t.line, text, t.text)
+	}
+	return nil
+}
+
+func (p *parser) name() (string, error) {
+	t := p.next()
+	if t == nil {
+		return "", fmt.Errorf("expect name but got EOF")
+	}
+	if t.kind != tokName {
+		return "", fmt.Errorf("%d: expect name but got '%s'", t.line, t.text)
+	}
+	return t.text, nil
+}
+
+// description returns the string before definitions
+func (p *parser) description() string {
+	if t := p.peek(); t != nil && t.kind == tokString {
+		p.next()
+		return t.text
+	}
+	return ""
+}
+
+func (p *parser) parseDefinition(doc *document) error {
+	desc := p.description()
+	extend := false
+	if p.is("extend") {
+		p.next()
+		extend = true
+	}
+	t := p.next()
+	if t == nil {
+		return fmt.Errorf("unexpected EOF")
+	}
+	switch t.text {
+	case "schema":
+		if err := p.skipDirectives(); err != nil {
+			return err
+		}
+		if err := p.expect("{"); err != nil {
+			return err
+		}
+		for !p.is("}") {
+			op, err := p.name()
+			if err != nil {
+				return err
+			}
+			if err := p.expect(":"); err != nil {
+				return err
+			}
+			if doc.roots[op], err = p.name(); err != nil {
+				return err
+			}
+		}
+		p.next()
+		return nil
+	case "type", "interface", "input":
+		def := &typeDef{kind: t.text, desc: desc, extend: extend}
+		var err error
+		if def.name, err = p.name(); err != nil {
+			return err
+		}
+		if p.is("implements") {
+			p.next()
+			// interfaces are not kept
+			for p.is("&") || (p.peek() != nil && p.peek().kind == tokName) {
+				p.next()
+			}
+		}
+		if err := p.skipDirectives(); err != nil {
+			return err
+		}
+		if p.is("{") {
+			if def.fields, err = p.parseFields("}"); err != nil {
+				return fmt.Errorf("%s %s: %v", def.kind, def.name, err)
+			}
+		}
+		doc.types = append(doc.types, def)
+		return nil
+	case "enum":
+		def := &typeDef{kind: "enum", desc: desc, extend: extend}
+		var err error
+		if def.name, err = p.name(); err != nil {
+			return err
+		}
+		if err := p.skipDirectives(); err != nil {
+			return err
+		}
+		if p.is("{") {
+			p.next()
+			for !p.is("}") {
+				p.description()
+				value, err := p.name()
+				if err != nil {
+					return fmt.Errorf("enum %s: %v", def.name, err)
+				}
+				def.values = append(def.values, value)
+				if err := p.skipDirectives(); err != nil {
+					return err
+				}
+			}
+			p.next()
+		}
+		doc.types = append(doc.types, def)
+		return nil
+	case "union":
+		def := &typeDef{kind: "union", desc: desc, extend: extend}
+		var err error
+		if def.name, err = p.name(); err != nil {
+			return err
+		}
+		if err := p.skipDirectives(); err != nil {
+			return err
+		}
+		if p.is("=") {
+			p.next()
+			if p.is("|") {
+				p.next()
+			}
+			for {
+				member, err := p.name()
+				if err != nil {
+					return fmt.Errorf("union %s: %v", def.name, err)
+				}
+				def.values = append(def.values, member)
+				if !p.is("|") {
+					break
+				}
+				p.next()
+			}
+		}
+		doc.types = append(doc.types, def)
+		return nil
+	case "scalar":
+		def := &typeDef{kind: "scalar", desc: desc, extend: extend}
+		var err error
+		if def.name, err = p.name(); err != nil {
+			return err
+		}
+		doc.types = append(doc.types, def)
+		return p.skipDirectives()
+	case "directive":
+		// directive @name(args) repeatable on A | B
+		if err := p.expect("@"); err != nil {
+			return err
+		}
+		if _, err := p.name(); err != nil {
+			return err
+		}
+		if p.is("(") {
+			if _, err := p.parseFields(")"); err != nil {
+				return err
+			}
+		}
+		if p.is("repeatable") {
+			p.next()
+		}
+		if err := p.expect("on"); err != nil {
+			return err
+		}
+		for p.is("|") || isLocation(p.peek()) {
+			p.next()
+		}
+		return nil
+	case "query", "mutation", "subscription", "fragment", "{":
+		return fmt.Errorf("%d: executable definition is not supported in SDL", t.line)
+	}
+	return fmt.Errorf("%d: unexpected '%s'", t.line, t.text)
+}
+
+// isLocation report whether t is a directive location,eg. FIELD_DEFINITION
+func isLocation(t *token) bool {
+	return t != nil && t.kind == tokName && strings.ToUpper(t.text) == t.text
+}
+
+// parseFields parse fields or arguments after the open bracket until end
+func (p *parser) parseFields(end string) ([]*fieldDef, error) {
+	p.next()
+	var fields []*fieldDef
+	for !p.is(end) {
+		if p.peek() == nil {
+			return nil, fmt.Errorf("unexpected EOF")
+		}
+		field := &fieldDef{desc: p.description()}
+		var err error
+		if field.name, err = p.name(); err != nil {
+			return nil, err
+		}
+		if p.is("(") {
+			if field.args, err = p.parseFields(")"); err != nil {
+				return nil, fmt.Errorf("field %s: %v", field.name, err)
+			}
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		if field.typ, err = p.parseType(); err != nil {
+			return nil, err
+		}
+		if p.is("=") {
+			p.next()
+			if field.defaultValue, err = p.parseValue(); err != nil {
+				return nil, err
+			}
+		}
+		if err := p.skipDirectives(); err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+	}
+	p.next()
+	return fields, nil
+}
+
+func (p *parser) parseType() (*typeRef, error) {
+	t := new(typeRef)
+	if p.is("[") {
+		p.next()
+		elem, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+		t.elem = elem
+	} else {
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		t.name = name
+	}
+	if p.is("!") {
+		p.next()
+		t.nonNull = true
+	}
+	return t, nil
+}
+
+// parseValue returns the source text of a value literal
+func (p *parser) parseValue() (string, error) {
+	t := p.next()
+	if t == nil {
+		return "", fmt.Errorf("unexpected EOF")
+	}
+	switch {
+	case t.kind == tokString:
+		return fmt.Sprintf("%q", t.text), nil
+	case t.text == "$":
+		name, err := p.name()
+		return "$" + name, err
+	case t.text == "[":
+		var items []string
+		for !p.is("]") {
+			v, err := p.parseValue()
+			if err != nil {
+				return "", err
+			}
+			items = append(items, v)
+		}
+		p.next()
+		return "[" + strings.Join(items, ", ") + "]", nil
+	case t.text == "{":
+		var items []string
+		for !p.is("}") {
+			name, err := p.name()
+			if err != nil {
+				return "", err
+			}
+			if err := p.expect(":"); err != nil {
+				return "", err
+			}
+			v, err := p.parseValue()
+			if err != nil {
+				return "", err
+			}
+			items = append(items, name+": "+v)
+		}
+		p.next()
+		return "{" + strings.Join(items, ", ") + "}", nil
+	}
+	return t.text, nil
+}
+
+// skipDirectives skip directives like @deprecated(reason: "xx")
+func (p *parser) skipDirectives() error {
+	for p.is("@") {
+		p.next()
+		if _, err := p.name(); err != nil {
+			return err
+		}
+		if p.is("(") {
+			p.next()
+			for !p.is(")") {
+				if _, err := p.name(); err != nil {
+					return err
+				}
+				if err := p.expect(":"); err != nil {
+					return err
+				}
+				if _, err := p.parseValue(); err != nil {
+					return err
+				}
+			}
+			p.next()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,666 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/go-yaml/yaml"
+	"github.com/thewinds/mkdoc"
+	"github.com/thewinds/mkdoc/objloader/openapiloader"
+	"github.com/thewinds/mkdoc/schema"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+func init() {
+	mkdoc.RegisterDocScanner(new(Scanner))
+}
+
+const lang = "openapi"
+
+type Scanner struct {
+	filterTag string
+	path      string
+}
+
+func (s *Scanner) Scan(config mkdoc.DocScanConfig) (*mkdoc.DocScanResult, error) {
+	s.filterTag = config.Args["_filter_tag"]
+	s.path = config.Args["path"]
+	root, err := specRoot(s.path)
+	if err != nil {
+		return nil, err
+	}
+	r := new(mkdoc.DocScanResult)
+	// the converted files and the files of external refs
+	loaded := make(map[string]bool)
+	var refFiles []string
+	err = filepath.Walk(s.path, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		ext := filepath.Ext(path)
+		if ext != ".yaml" && ext != ".yml" && ext != ".json" {
+			return nil
+		}
+		sp, err := readSpec(path)
+		if err != nil {
+			// the yaml or json files other than specs,eg. conf.yaml
+			fmt.Printf("WARNING: openapi: %s is skipped: %v \n", path, err)
+			return nil
+		}
+		if sp == nil {
+			return nil
+		}
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return err
+		}
+		c := &converter{spec: sp, root: root, fileName: absPath}
+		apis, err := c.convert()
+		if err != nil {
+			return fmt.Errorf("openapi: %s %v", path, err)
+		}
+		for _, api := range apis {
+			if s.matchTag(api) {
+				r.APIs = append(r.APIs, api)
+			}
+		}
+		r.Objects = append(r.Objects, c.objects...)
+		loaded[absPath] = true
+		refFiles = append(refFiles, c.refFiles...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	// the schemas of external refs are loaded even if the files are not specs
+	for i := 0; i < len(refFiles); i++ {
+		fileName := refFiles[i]
+		if loaded[fileName] {
+			continue
+		}
+		loaded[fileName] = true
+		sp, err := readRefFile(fileName)
+		if err != nil {
+			return nil, fmt.Errorf("openapi: %s %v", fileName, err)
+		}
+		c := &converter{spec: sp, root: root, fileName: fileName}
+		if err := c.convertDefinitions(); err != nil {
+			return nil, fmt.Errorf("openapi: %s %v", fileName, err)
+		}
+		r.Objects = append(r.Objects, c.objects...)
+		refFiles = append(refFiles, c.refFiles...)
+	}
+	return r, nil
+}
+
+// specRoot returns the absolute directory which object ids are relative to
+func specRoot(path string) (string, error) {
+	root, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	info, err := os.Stat(root)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		root = filepath.Dir(root)
+	}
+	return root, nil
+}
+
+func (s *Scanner) matchTag(api *schema.API) bool {
+	if len(s.filterTag) == 0 {
+		return true
+	}
+	for _, tag := range api.Tags {
+		if tag == strings.TrimSpace(s.filterTag) {
+			return true
+		}
+	}
+	return false
+}
+
+// readSpec read a OpenAPI 3 or Swagger 2 file
+// returns nil if the file is not a spec
+func readSpec(path string) (*spec, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Contains(b, []byte("openapi")) && !bytes.Contains(b, []byte("swagger")) {
+		return nil, nil
+	}
+	sp := new(spec)
+	if err := unmarshal(path, b, sp); err != nil {
+		return nil, err
+	}
+	if sp.OpenAPI == "" && sp.Swagger == "" {
+		return nil, nil
+	}
+	return sp, nil
+}
+
+// readRefFile read a file of external refs,
+// the schemas are the definitions,components or the top level keys of file
+func readRefFile(path string) (*spec, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	sp := new(spec)
+	if err := unmarshal(path, b, sp); err != nil {
+		return nil, err
+	}
+	if sp.Definitions == nil && (sp.Components == nil || sp.Components.Schemas == nil) {
+		sp.Definitions = new(schemas)
+		if err := unmarshal(path, b, sp.Definitions); err != nil {
+			return nil, err
+		}
+	}
+	return sp, nil
+}
+
+// unmarshal the json or yaml file by the extension
+func unmarshal(path string, b []byte, v interface{}) error {
+	if filepath.Ext(path) == ".json" {
+		return json.Unmarshal(b, v)
+	}
+	return yaml.Unmarshal(b, v)
+}
+
+func (s *Scanner) Name() string {
+	return "openapi"
+}
+
+func (s *Scanner) Help() string {
+	return "scan doc from OpenAPI 3 / Swagger 2 yaml or json files"
+}
+
+// converter convert a spec to mkdoc schema
+type converter struct {
+	spec     *spec
+	root     string
+	fileName string
+	objects  []*schema.Object
+	anonNo   int
+	// refFiles are the absolute file names of external refs
+	refFiles []string
+}
+
+func (c *converter) convert() ([]*schema.API, error) {
+	if err := c.convertDefinitions(); err != nil {
+		return nil, err
+	}
+	var apis []*schema.API
+	if c.spec.Paths == nil {
+		return nil, nil
+	}
+	for _, path := range c.spec.Paths.keys {
+		item := c.spec.Paths.m[path]
+		if item == nil {
+			continue
+		}
+		methods, ops := item.operations()
+		for i, op := range ops {
+			api, err := c.convertOperation(path, methods[i], item, op)
+			if err != nil {
+				return nil, err
+			}
+			apis = append(apis, api)
+		}
+	}
+	return apis, nil
+}
+
+func (c *converter) convertDefinitions() error {
+	defs := c.spec.Definitions
+	prefix := "#/definitions/"
+	if c.spec.Components != nil && c.spec.Components.Schemas != nil {
+		defs = c.spec.Components.Schemas
+		prefix = "#/components/schemas/"
+	}
+	if defs == nil {
+		return nil
+	}
+	for _, name := range defs.keys {
+		s := defs.m[name]
+		if s == nil {
+			continue
+		}
+		obj, err := c.convertObject(openapiloader.ObjectID(c.root, c.fileName, prefix+name), s)
+		if err != nil {
+			return err
+		}
+		c.objects = append(c.objects, obj)
+	}
+	return nil
+}
+
+func (c *converter) convertOperation(path, method string, item *pathItem, op *operation) (*schema.API, error) {
+	api := &schema.API{
+		Name:           op.Summary,
+		Desc:           op.Description,
+		Path:           path,
+		Method:         method,
+		Type:           "http",
+		Tags:           op.Tags,
+		SourceFileName: c.fileName,
+		Language:       lang,
+	}
+	if api.Name == "" {
+		api.Name = op.OperationID
+	}
+	if api.Name == "" {
+		api.Name = fmt.Sprintf("%s %s", method, path)
+	}
+	if len(api.Tags) == 0 {
+		base := filepath.Base(c.fileName)
+		api.Tags = []string{strings.TrimSuffix(base, filepath.Ext(base))}
+	}
+
+	var formFields []*schema.ObjectField
+	params := append(append([]*parameter{}, item.Parameters...), op.Parameters...)
+	for _, p := range params {
+		p = c.resolveParameter(p)
+		if p == nil {
+			continue
+		}
+		switch p.In {
+		case "path":
+			api.PathParams = append(api.PathParams, convertParam(p))
+		case "query":
+			api.Query = append(api.Query, convertParam(p))
+		case "header":
+			api.Header = append(api.Header, convertParam(p))
+		case "body":
+			typ, err := c.rootType("in", p.Schema)
+			if err != nil {
+				return nil, err
+			}
+			api.InType = typ
+			api.MimeIn = mimeOf(firstOf(op.Consumes, c.spec.Consumes))
+		case "formData":
+			s := p.Schema
+			if s == nil {
+				s = &schemaObj{Type: p.Type, Format: p.Format, Items: p.Items}
+			}
+			field, err := c.convertField(p.Name, p.Description, s)
+			if err != nil {
+				return nil, err
+			}
+			formFields = append(formFields, field)
+		}
+	}
+	if len(formFields) > 0 {
+		obj := &schema.Object{
+			ID:       c.anonID("in"),
+			Type:     &schema.ObjectType{Name: "object"},
+			Language: lang,
+			Fields:   formFields,
+		}
+		c.objects = append(c.objects, obj)
+		api.InType = obj.ID
+		api.MimeIn = "form"
+	}
+
+	if body := c.resolveRequestBody(op.RequestBody); body != nil {
+		contentType, media := pickContent(body.Content)
+		if media != nil && media.Schema != nil {
+			typ, err := c.rootType("in", media.Schema)
+			if err != nil {
+				return nil, err
+			}
+			api.InType = typ
+			api.MimeIn = mimeOf(contentType)
+		}
+	}
+
+	codes := make([]string, 0, len(op.Responses))
+	for code := range op.Responses {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	success := pickResponse(op.Responses)
+	for _, code := range codes {
+		resp := c.resolveResponse(op.Responses[code])
+		if resp == nil {
+			continue
+		}
+		s := resp.Schema
+		contentType := firstOf(op.Produces, c.spec.Produces)
+		if len(resp.Content) > 0 {
+			var media *mediaType
+			contentType, media = pickContent(resp.Content)
+			s = nil
+			if media != nil {
+				s = media.Schema
+			}
+		}
+		r := &schema.Response{Status: strings.ToLower(code), Desc: resp.Description}
+		if s != nil {
+			typ, err := c.rootType("out", s)
+			if err != nil {
+				return nil, err
+			}
+			r.Type = typ
+			r.Mime = mimeOf(contentType)
+			if op.Responses[code] == success {
+				api.OutType = typ
+				api.MimeOut = r.Mime
+			}
+		}
+		api.Responses = append(api.Responses, r)
+	}
+	return api, nil
+}
+
+// rootType returns the type name of api in/out
+func (c *converter) rootType(direction string, s *schemaObj) (string, error) {
+	if s.Ref != "" {
+		return c.refID(s.Ref), nil
+	}
+	obj, err := c.convertObject(c.anonID(direction), s)
+	if err != nil {
+		return "", err
+	}
+	c.objects = append(c.objects, obj)
+	return obj.ID, nil
+}
+
+// convertObject convert a schema to object with the id
+func (c *converter) convertObject(id string, s *schemaObj) (*schema.Object, error) {
+	s = c.mergeAllOf(s)
+	obj := &schema.Object{ID: id, Language: lang}
+	if s.Ref != "" {
+		typ, err := c.convertType(s)
+		if err != nil {
+			return nil, err
+		}
+		obj.Type = typ
+		return obj, nil
+	}
+	if s.typeName() == "array" {
+		items := s.Items
+		if items == nil {
+			items = &schemaObj{}
+		}
+		elem, err := c.convertType(items)
+		if err != nil {
+			return nil, err
+		}
+		obj.Type = &schema.ObjectType{Name: elem.Name, Ref: elem.Ref, IsRepeated: true}
+		return obj, nil
+	}
+	if s.typeName() != "object" && s.Properties == nil {
+		obj.Type = &schema.ObjectType{Name: primitive(s)}
+		return obj, nil
+	}
+	obj.Type = &schema.ObjectType{Name: "object"}
+	if s.Properties != nil {
+		for _, name := range s.Properties.keys {
+			prop := s.Properties.m[name]
+			if prop == nil {
+				continue
+			}
+			field, err := c.convertField(name, prop.Description, prop)
+			if err != nil {
+				return nil, err
+			}
+			obj.Fields = append(obj.Fields, field)
+		}
+	}
+	return obj, nil
+}
+
+func (c *converter) convertField(name, desc string, s *schemaObj) (*schema.ObjectField, error) {
+	typ, err := c.convertType(s)
+	if err != nil {
+		return nil, err
+	}
+	if desc == "" {
+		desc = s.Title
+	}
+	ext := &schema.Extension{
+		Name: "go_tag",
+		Data: json.RawMessage(fmt.Sprintf("%q", fmt.Sprintf(`json:"%s"`, name))),
+	}
+	return &schema.ObjectField{
+		Name:       name,
+		Desc:       desc,
+		Type:       typ,
+		Extensions: []*schema.Extension{ext},
+	}, nil
+}
+
+// convertType get the object type of a field schema
+// arrays and inline objects will be converted to anonymous objects
+func (c *converter) convertType(s *schemaObj) (*schema.ObjectType, error) {
+	if s.Ref != "" {
+		return &schema.ObjectType{Name: "object", Ref: c.refID(s.Ref)}, nil
+	}
+	s = c.mergeAllOf(s)
+	switch {
+	case s.typeName() == "array":
+		arr, err := c.convertObject(c.anonID("arr"), s)
+		if err != nil {
+			return nil, err
+		}
+		c.objects = append(c.objects, arr)
+		return &schema.ObjectType{Name: "object", Ref: arr.ID}, nil
+	case s.Properties != nil:
+		obj, err := c.convertObject(c.anonID("obj"), s)
+		if err != nil {
+			return nil, err
+		}
+		c.objects = append(c.objects, obj)
+		return &schema.ObjectType{Name: "object", Ref: obj.ID}, nil
+	}
+	return &schema.ObjectType{Name: primitive(s)}, nil
+}
+
+// refID returns the object id of schema ref,the file of external ref is recorded to be loaded
+func (c *converter) refID(ref string) string {
+	if i := strings.Index(ref, "#"); i > 0 {
+		c.refFiles = append(c.refFiles, filepath.Join(filepath.Dir(c.fileName), ref[:i]))
+	}
+	return openapiloader.ObjectID(c.root, c.fileName, ref)
+}
+
+// mergeAllOf merge the properties of allOf schemas into one schema
+func (c *converter) mergeAllOf(s *schemaObj) *schemaObj {
+	if len(s.AllOf) == 0 {
+		return s
+	}
+	merged := &schemaObj{
+		Type:        "object",
+		Description: s.Description,
+		Title:       s.Title,
+		Properties:  &schemas{m: make(map[string]*schemaObj)},
+	}
+	parts := append(append([]*schemaObj{}, s.AllOf...), &schemaObj{Properties: s.Properties})
+	for _, part := range parts {
+		if part == nil {
+			continue
+		}
+		part = c.mergeAllOf(c.resolveSchema(part))
+		if part.Properties == nil {
+			continue
+		}
+		for _, name := range part.Properties.keys {
+			if _, exist := merged.Properties.m[name]; !exist {
+				merged.Properties.keys = append(merged.Properties.keys, name)
+			}
+			merged.Properties.m[name] = part.Properties.m[name]
+		}
+	}
+	return merged
+}
+
+// resolveSchema resolve local `$ref` of schema
+func (c *converter) resolveSchema(s *schemaObj) *schemaObj {
+	const (
+		oas3Prefix     = "#/components/schemas/"
+		swagger2Prefix = "#/definitions/"
+	)
+	var r *schemaObj
+	switch {
+	case strings.HasPrefix(s.Ref, oas3Prefix) && c.spec.Components != nil:
+		r = c.spec.Components.Schemas.get(s.Ref[len(oas3Prefix):])
+	case strings.HasPrefix(s.Ref, swagger2Prefix):
+		r = c.spec.Definitions.get(s.Ref[len(swagger2Prefix):])
+	}
+	if r == nil {
+		return s
+	}
+	return r
+}
+
+func (c *converter) resolveParameter(p *parameter) *parameter {
+	if p == nil || p.Ref == "" {
+		return p
+	}
+	name := p.Ref[strings.LastIndex(p.Ref, "/")+1:]
+	if c.spec.Components != nil && c.spec.Components.Parameters[name] != nil {
+		return c.spec.Components.Parameters[name]
+	}
+	return c.spec.Parameters[name]
+}
+
+func (c *converter) resolveRequestBody(b *requestBody) *requestBody {
+	if b == nil || b.Ref == "" {
+		return b
+	}
+	if c.spec.Components == nil {
+		return nil
+	}
+	return c.spec.Components.RequestBodies[b.Ref[strings.LastIndex(b.Ref, "/")+1:]]
+}
+
+func (c *converter) resolveResponse(r *response) *response {
+	if r == nil || r.Ref == "" {
+		return r
+	}
+	if c.spec.Components == nil {
+		return nil
+	}
+	return c.spec.Components.Responses[r.Ref[strings.LastIndex(r.Ref, "/")+1:]]
+}
+
+func (c *converter) anonID(direction string) string {
+	c.anonNo++
+	return fmt.Sprintf("@openapi_%s_%s_#%d", openapiloader.SpecName(c.root, c.fileName), direction, c.anonNo)
+}
+
+// convertParam convert the path,query or header parameter
+func convertParam(p *parameter) *schema.Param {
+	s := p.Schema
+	if s == nil {
+		s = &schemaObj{Type: p.Type, Format: p.Format, Enum: p.Enum, Default: p.Default}
+	}
+	param := &schema.Param{Name: p.Name, Type: primitive(s), Desc: p.Description, Required: p.Required}
+	if param.Type == "interface{}" {
+		param.Type = "string"
+	}
+	if s.Default != nil {
+		param.Default = fmt.Sprint(s.Default)
+	}
+	for _, v := range s.Enum {
+		param.Enum = append(param.Enum, fmt.Sprint(v))
+	}
+	return param
+}
+
+// pickResponse pick the success response
+// 200 > 201 > other 2xx > default
+func pickResponse(responses map[string]*response) *response {
+	if r := responses["200"]; r != nil {
+		return r
+	}
+	if r := responses["201"]; r != nil {
+		return r
+	}
+	codes := make([]string, 0, len(responses))
+	for code := range responses {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		if strings.HasPrefix(code, "2") {
+			return responses[code]
+		}
+	}
+	return responses["default"]
+}
+
+// pickContent prefer json content
+func pickContent(content map[string]*mediaType) (string, *mediaType) {
+	if len(content) == 0 {
+		return "", nil
+	}
+	types := make([]string, 0, len(content))
+	for t := range content {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	for _, t := range types {
+		if strings.Contains(t, "json") {
+			return t, content[t]
+		}
+	}
+	return types[0], content[types[0]]
+}
+
+func firstOf(lists ...[]string) string {
+	for _, list := range lists {
+		if len(list) > 0 {
+			return list[0]
+		}
+	}
+	return ""
+}
+
+// mimeOf convert http content type to mkdoc mime type
+func mimeOf(contentType string) string {
+	switch {
+	case contentType == "":
+		return ""
+	case strings.Contains(contentType, "json"):
+		return "json"
+	case strings.Contains(contentType, "form"):
+		return "form"
+	case strings.Contains(contentType, "xml"):
+		return "xml"
+	}
+	return contentType
+}
+
+func primitive(s *schemaObj) string {
+	switch s.typeName() {
+	case "string":
+		return "string"
+	case "boolean":
+		return "bool"
+	case "integer":
+		switch s.Format {
+		case "int32":
+			return "int32"
+		case "int64":
+			return "int64"
+		}
+		return "int"
+	case "number":
+		if s.Format == "float" {
+			return "float32"
+		}
+		return "float64"
+	}
+	return "interface{}"
+}
@@ -0,0 +1,89 @@
+package openapi
+
+import (
+	"github.com/thewinds/mkdoc"
+	"github.com/thewinds/mkdoc/objloader/openapiloader"
+	"github.com/thewinds/mkdoc/schema"
+	"testing"
+)
+
+func TestScanner_Scan(t *testing.T) {
+	r, err := new(Scanner).Scan(mkdoc.DocScanConfig{Args: map[string]string{"path": "testdata"}})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(r.APIs) != 3 {
+		t.Errorf("want 3 apis got %d", len(r.APIs))
+		return
+	}
+	objects := make(map[string]*schema.Object)
+	for _, obj := range r.Objects {
+		objects[obj.ID] = obj
+	}
+
+	// legacy.json is walked before petstore.yaml
+	login := r.APIs[0]
+	if login.Name != "login" || login.MimeIn != "form" || login.OutType != "legacy.Token" {
+		t.Errorf("unexpected api %+v", login)
+	}
+	if in := objects[login.InType]; in == nil || len(in.Fields) != 2 || in.Fields[0].Name != "name" {
+		t.Errorf("unexpected form object %+v", in)
+	}
+
+	list, create := r.APIs[1], r.APIs[2]
+	if list.Method != "GET" || list.Header.Get("token") == nil || list.Header.Get("token").Desc != "jwt token" {
+		t.Errorf("unexpected api %+v", list)
+	}
+	if limit := list.Query.Get("limit"); limit == nil || limit.Desc != "max items" || limit.Type != "int" {
+		t.Errorf("unexpected query param %+v", limit)
+	}
+	if out := objects[list.OutType]; out == nil || !out.Type.IsRepeated || out.Type.Ref != "petstore.Pet" {
+		t.Errorf("unexpected out object %+v", out)
+	}
+	if create.InType != "petstore.Pet" || create.MimeIn != "json" || create.OutType != "petstore.Pet" {
+		t.Errorf("unexpected api %+v", create)
+	}
+
+	owner := objects["petstore.Owner"]
+	if owner == nil || len(owner.Fields) != 2 || owner.Fields[0].Name != "id" || owner.Fields[1].Name != "pets" {
+		t.Errorf("allOf not merged %+v", owner)
+		return
+	}
+	if pets := objects[owner.Fields[1].Type.Ref]; pets == nil || pets.Type.Ref != "petstore.Pet" {
+		t.Errorf("unexpected array object %+v", pets)
+	}
+
+	// shared/errors.yaml is not a spec but the schemas of refs are loaded
+	var errType string
+	for _, r := range create.Responses {
+		if r.Status == "400" {
+			errType = r.Type
+		}
+	}
+	if errType != "shared/errors.Error" {
+		t.Errorf("unexpected error response type %s", errType)
+	}
+	errObj := objects["shared/errors.Error"]
+	if errObj == nil || len(errObj.Fields) != 3 || errObj.Fields[2].Type.Ref != "shared/errors.Detail" || objects["shared/errors.Detail"] == nil {
+		t.Errorf("external ref not loaded %+v", errObj)
+	}
+}
+
+func TestObjectID(t *testing.T) {
+	tests := []struct {
+		fileName string
+		ref      string
+		want     string
+	}{
+		{"/specs/petstore.yaml", "#/components/schemas/Pet", "petstore.Pet"},
+		{"/specs/a/pet.yaml", "common.yaml#/definitions/Error", "a/common.Error"},
+		{"/specs/b/pet.yaml", "common.yaml#/definitions/Error", "b/common.Error"},
+		{"/specs/b/pet.yaml", "../common.yaml#/definitions/Error", "common.Error"},
+	}
+	for _, tt := range tests {
+		if got := openapiloader.ObjectID("/specs", tt.fileName, tt.ref); got != tt.want {
+			t.Errorf("ObjectID(%s, %s) got %s want %s", tt.fileName, tt.ref, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/go-yaml/yaml"
+)
+
+// spec is the subset of OpenAPI 3 and Swagger 2 document which mkdoc cares about
+type spec struct {
+	OpenAPI     string                `json:"openapi" yaml:"openapi"`
+	Swagger     string                `json:"swagger" yaml:"swagger"`
+	Info        *specInfo             `json:"info" yaml:"info"`
+	Paths       *pathItems            `json:"paths" yaml:"paths"`
+	Consumes    []string              `json:"consumes" yaml:"consumes"`
+	Produces    []string              `json:"produces" yaml:"produces"`
+	Definitions *schemas              `json:"definitions" yaml:"definitions"`
+	Parameters  map[string]*parameter `json:"parameters" yaml:"parameters"`
+	Components  *components           `json:"components" yaml:"components"`
+}
+
+type specInfo struct {
+	Title       string `json:"title" yaml:"title"`
+	Description string `json:"description" yaml:"description"`
+}
+
+type components struct {
+	Schemas       *schemas                `json:"schemas" yaml:"schemas"`
+	Parameters    map[string]*parameter   `json:"parameters" yaml:"parameters"`
+	RequestBodies map[string]*requestBody `json:"requestBodies" yaml:"requestBodies"`
+	Responses     map[string]*response    `json:"responses" yaml:"responses"`
+}
+
+type pathItem struct {
+	Get        *operation   `json:"get" yaml:"get"`
+	Put        *operation   `json:"put" yaml:"put"`
+	Post       *operation   `json:"post" yaml:"post"`
+	Delete     *operation   `json:"delete" yaml:"delete"`
+	Options    *operation   `json:"options" yaml:"options"`
+	Head       *operation   `json:"head" yaml:"head"`
+	Patch      *operation   `json:"patch" yaml:"patch"`
+	Trace      *operation   `json:"trace" yaml:"trace"`
+	Parameters []*parameter `json:"parameters" yaml:"parameters"`
+}
+
+// operations returns method and operation pairs in a stable order
+func (p *pathItem) operations() ([]string, []*operation) {
+	methods := []string{"GET", "PUT", "POST", "DELETE", "OPTIONS", "HEAD", "PATCH", "TRACE"}
+	ops := []*operation{p.Get, p.Put, p.Post, p.Delete, p.Options, p.Head, p.Patch, p.Trace}
+	var rm []string
+	var rops []*operation
+	for i, op := range ops {
+		if op != nil {
+			rm = append(rm, methods[i])
+			rops = append(rops, op)
+		}
+	}
+	return rm, rops
+}
+
+type operation struct {
+	Tags        []string             `json:"tags" yaml:"tags"`
+	Summary     string               `json:"summary" yaml:"summary"`
+	Description string               `json:"description" yaml:"description"`
+	OperationID string               `json:"operationId" yaml:"operationId"`
+	Consumes    []string             `json:"consumes" yaml:"consumes"`
+	Produces    []string             `json:"produces" yaml:"produces"`
+	Parameters  []*parameter         `json:"parameters" yaml:"parameters"`
+	RequestBody *requestBody         `json:"requestBody" yaml:"requestBody"`
+	Responses   map[string]*response `json:"responses" yaml:"responses"`
+}
+
+type parameter struct {
+	Ref         string        `json:"$ref" yaml:"$ref"`
+	Name        string        `json:"name" yaml:"name"`
+	In          string        `json:"in" yaml:"in"`
+	Description string        `json:"description" yaml:"description"`
+	Required    bool          `json:"required" yaml:"required"`
+	Schema      *schemaObj    `json:"schema" yaml:"schema"`
+	Type        interface{}   `json:"type" yaml:"type"`
+	Format      string        `json:"format" yaml:"format"`
+	Items       *schemaObj    `json:"items" yaml:"items"`
+	Enum        []interface{} `json:"enum" yaml:"enum"`
+	Default     interface{}   `json:"default" yaml:"default"`
+}
+
+type requestBody struct {
+	Ref         string                `json:"$ref" yaml:"$ref"`
+	Description string                `json:"description" yaml:"description"`
+	Content     map[string]*mediaType `json:"content" yaml:"content"`
+}
+
+type response struct {
+	Ref         string                `json:"$ref" yaml:"$ref"`
+	Description string                `json:"description" yaml:"description"`
+	Content     map[string]*mediaType `json:"content" yaml:"content"`
+	Schema      *schemaObj            `json:"schema" yaml:"schema"`
+}
+
+type mediaType struct {
+	Schema *schemaObj `json:"schema" yaml:"schema"`
+}
+
+type schemaObj struct {
+	Ref         string        `json:"$ref" yaml:"$ref"`
+	Type        interface{}   `json:"type" yaml:"type"`
+	Format      string        `json:"format" yaml:"format"`
+	Description string        `json:"description" yaml:"description"`
+	Title       string        `json:"title" yaml:"title"`
+	Items       *schemaObj    `json:"items" yaml:"items"`
+	Properties  *schemas      `json:"properties" yaml:"properties"`
+	AllOf       []*schemaObj  `json:"allOf" yaml:"allOf"`
+	Enum        []interface{} `json:"enum" yaml:"enum"`
+	Default     interface{}   `json:"default" yaml:"default"`
+}
+
+// typeName returns the type name,the type of OpenAPI 3.1 may be a list
+// eg. [string,"null"]
+func (s *schemaObj) typeName() string {
+	switch t := s.Type.(type) {
+	case string:
+		return t
+	case []interface{}:
+		for _, v := range t {
+			if name, ok := v.(string); ok && name != "null" {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// schemas is a ordered schema map which keeps the define order
+type schemas struct {
+	keys []string
+	m    map[string]*schemaObj
+}
+
+func (s *schemas) get(name string) *schemaObj {
+	if s == nil {
+		return nil
+	}
+	return s.m[name]
+}
+
+func (s *schemas) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var ms yaml.MapSlice
+	if err := unmarshal(&ms); err != nil {
+		return err
+	}
+	if err := unmarshal(&s.m); err != nil {
+		return err
+	}
+	for _, item := range ms {
+		if k, ok := item.Key.(string); ok {
+			s.keys = append(s.keys, k)
+		}
+	}
+	return nil
+}
+
+func (s *schemas) UnmarshalJSON(b []byte) error {
+	keys, err := jsonObjectKeys(b)
+	if err != nil {
+		return err
+	}
+	s.keys = keys
+	return json.Unmarshal(b, &s.m)
+}
+
+// pathItems is a ordered path map which keeps the define order
+type pathItems struct {
+	keys []string
+	m    map[string]*pathItem
+}
+
+func (p *pathItems) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var ms yaml.MapSlice
+	if err := unmarshal(&ms); err != nil {
+		return err
+	}
+	if err := unmarshal(&p.m); err != nil {
+		return err
+	}
+	for _, item := range ms {
+		if k, ok := item.Key.(string); ok {
+			p.keys = append(p.keys, k)
+		}
+	}
+	return nil
+}
+
+func (p *pathItems) UnmarshalJSON(b []byte) error {
+	keys, err := jsonObjectKeys(b)
+	if err != nil {
+		return err
+	}
+	p.keys = keys
+	return json.Unmarshal(b, &p.m)
+}
+
+// jsonObjectKeys returns the keys of json object in order
+func jsonObjectKeys(b []byte) ([]string, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	var keys []string
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, t.(string))
+		var skip json.RawMessage
+		if err := dec.Decode(&skip); err != nil {
+			return nil, err
+		}
+	}
+	return keys, nil
+}
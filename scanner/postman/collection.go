@@ -0,0 +1,165 @@
+package postman
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+)
+
+// collection is the subset of Postman Collection v2.0/v2.1 which mkdoc cares about
+type collection struct {
+	Info *struct {
+		PostmanID   string      `json:"_postman_id"`
+		Name        string      `json:"name"`
+		Description description `json:"description"`
+		Schema      string      `json:"schema"`
+	} `json:"info"`
+	Item []*item `json:"item"`
+}
+
+// item is a folder if Item is not empty,otherwise it's a request
+type item struct {
+	Name        string      `json:"name"`
+	Description description `json:"description"`
+	Item        []*item     `json:"item"`
+	Request     *request    `json:"request"`
+	Response    []*response `json:"response"`
+}
+
+type request struct {
+	Method      string      `json:"method"`
+	Header      []*keyValue `json:"header"`
+	Body        *body       `json:"body"`
+	URL         *reqURL     `json:"url"`
+	Description description `json:"description"`
+}
+
+// UnmarshalJSON request may be a url string
+func (r *request) UnmarshalJSON(b []byte) error {
+	var raw string
+	if err := json.Unmarshal(b, &raw); err == nil {
+		r.Method = "GET"
+		r.URL = &reqURL{Raw: raw}
+		return nil
+	}
+	type alias request
+	return json.Unmarshal(b, (*alias)(r))
+}
+
+type keyValue struct {
+	Key         string      `json:"key"`
+	Value       string      `json:"value"`
+	Description description `json:"description"`
+	Disabled    bool        `json:"disabled"`
+	Type        string      `json:"type"`
+}
+
+type body struct {
+	Mode       string      `json:"mode"`
+	Raw        string      `json:"raw"`
+	FormData   []*keyValue `json:"formdata"`
+	URLEncoded []*keyValue `json:"urlencoded"`
+}
+
+type response struct {
+	Name string `json:"name"`
+	Code int    `json:"code"`
+	Body string `json:"body"`
+}
+
+type reqURL struct {
+	Raw      string        `json:"raw"`
+	Path     []interface{} `json:"path"`
+	Query    []*keyValue   `json:"query"`
+	Variable []*keyValue   `json:"variable"`
+}
+
+// UnmarshalJSON url may be a string
+func (u *reqURL) UnmarshalJSON(b []byte) error {
+	var raw string
+	if err := json.Unmarshal(b, &raw); err == nil {
+		u.Raw = raw
+		return nil
+	}
+	type alias reqURL
+	return json.Unmarshal(b, (*alias)(u))
+}
+
+// path returns the url path without host
+//
+//	{{base_url}}/user/:id?a=1 => /user/:id
+func (u *reqURL) path() string {
+	if len(u.Path) > 0 {
+		var segs []string
+		for _, seg := range u.Path {
+			switch s := seg.(type) {
+			case string:
+				segs = append(segs, s)
+			case map[string]interface{}:
+				if v, ok := s["value"].(string); ok {
+					segs = append(segs, v)
+				}
+			}
+		}
+		return "/" + strings.Join(segs, "/")
+	}
+	raw := u.Raw
+	if i := strings.Index(raw, "?"); i != -1 {
+		raw = raw[:i]
+	}
+	if strings.HasPrefix(raw, "{{") {
+		if i := strings.Index(raw, "}}"); i != -1 {
+			raw = raw[i+2:]
+		}
+	} else if parsed, err := url.Parse(raw); err == nil && parsed.Host != "" {
+		raw = parsed.Path
+	}
+	if !strings.HasPrefix(raw, "/") {
+		raw = "/" + raw
+	}
+	return raw
+}
+
+// query returns the query params,query in raw url will be used
+// if the structured query is not defined
+func (u *reqURL) query() []*keyValue {
+	if len(u.Query) > 0 {
+		return u.Query
+	}
+	i := strings.Index(u.Raw, "?")
+	if i == -1 {
+		return nil
+	}
+	var r []*keyValue
+	for _, kv := range strings.Split(u.Raw[i+1:], "&") {
+		if kv == "" {
+			continue
+		}
+		pair := strings.SplitN(kv, "=", 2)
+		p := &keyValue{Key: pair[0]}
+		if len(pair) == 2 {
+			p.Value = pair[1]
+		}
+		r = append(r, p)
+	}
+	return r
+}
+
+// description may be a string or {"content":"...","type":"text/markdown"}
+type description string
+
+func (d *description) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		*d = description(s)
+		return nil
+	}
+	var obj struct {
+		Content string `json:"content"`
+	}
+	if err := json.Unmarshal(b, &obj); err != nil {
+		return err
+	}
+	*d = description(obj.Content)
+	return nil
+}
@@ -0,0 +1,164 @@
+package postman
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/thewinds/mkdoc/schema"
+	"strings"
+)
+
+// inferrer infer object field trees from json examples
+type inferrer struct {
+	idPrefix string
+	no       int
+	objects  []*schema.Object
+}
+
+// infer the root object of a json example
+// returns nil if the example is not a valid json
+func (inf *inferrer) infer(direction string, example string) *schema.Object {
+	example = strings.TrimSpace(example)
+	if example == "" {
+		return nil
+	}
+	dec := json.NewDecoder(strings.NewReader(example))
+	dec.UseNumber()
+	v, err := decodeOrdered(dec)
+	if err != nil {
+		return nil
+	}
+	obj := &schema.Object{ID: inf.newID(direction), Language: lang}
+	switch t := v.(type) {
+	case *orderedObject:
+		obj.Type = &schema.ObjectType{Name: "object"}
+		obj.Fields = inf.inferFields(t)
+	case []interface{}:
+		elem := inf.inferType(firstElem(t))
+		obj.Type = &schema.ObjectType{Name: elem.Name, Ref: elem.Ref, IsRepeated: true}
+	default:
+		obj.Type = inf.inferType(v)
+	}
+	inf.objects = append(inf.objects, obj)
+	return obj
+}
+
+func (inf *inferrer) inferFields(o *orderedObject) []*schema.ObjectField {
+	fields := make([]*schema.ObjectField, 0, len(o.keys))
+	for i, key := range o.keys {
+		fields = append(fields, newField(key, "", inf.inferType(o.values[i])))
+	}
+	return fields
+}
+
+func (inf *inferrer) inferType(v interface{}) *schema.ObjectType {
+	switch t := v.(type) {
+	case string:
+		return &schema.ObjectType{Name: "string"}
+	case bool:
+		return &schema.ObjectType{Name: "bool"}
+	case json.Number:
+		if _, err := t.Int64(); err == nil {
+			return &schema.ObjectType{Name: "int64"}
+		}
+		return &schema.ObjectType{Name: "float64"}
+	case *orderedObject:
+		obj := &schema.Object{
+			ID:       inf.newID("obj"),
+			Type:     &schema.ObjectType{Name: "object"},
+			Language: lang,
+			Fields:   inf.inferFields(t),
+		}
+		inf.objects = append(inf.objects, obj)
+		return &schema.ObjectType{Name: "object", Ref: obj.ID}
+	case []interface{}:
+		elem := inf.inferType(firstElem(t))
+		arr := &schema.Object{
+			ID:       inf.newID("arr"),
+			Type:     &schema.ObjectType{Name: elem.Name, Ref: elem.Ref, IsRepeated: true},
+			Language: lang,
+		}
+		inf.objects = append(inf.objects, arr)
+		return &schema.ObjectType{Name: "object", Ref: arr.ID}
+	}
+	// null
+	return &schema.ObjectType{Name: "interface{}"}
+}
+
+func (inf *inferrer) newID(kind string) string {
+	inf.no++
+	return fmt.Sprintf("@postman_%s_%s_#%d", inf.idPrefix, kind, inf.no)
+}
+
+func newField(name, desc string, typ *schema.ObjectType) *schema.ObjectField {
+	ext := &schema.Extension{
+		Name: "go_tag",
+		Data: json.RawMessage(fmt.Sprintf("%q", fmt.Sprintf(`json:"%s"`, name))),
+	}
+	return &schema.ObjectField{
+		Name:       name,
+		Desc:       desc,
+		Type:       typ,
+		Extensions: []*schema.Extension{ext},
+	}
+}
+
+func firstElem(arr []interface{}) interface{} {
+	if len(arr) == 0 {
+		return nil
+	}
+	return arr[0]
+}
+
+// orderedObject is a json object which keeps the key order
+type orderedObject struct {
+	keys   []string
+	values []interface{}
+}
+
+// decodeOrdered decode a json value,objects are decoded to *orderedObject
+func decodeOrdered(dec *json.Decoder) (interface{}, error) {
+	t, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	delim, ok := t.(json.Delim)
+	if !ok {
+		return t, nil
+	}
+	switch delim {
+	case '{':
+		o := new(orderedObject)
+		for dec.More() {
+			k, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			v, err := decodeOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+			o.keys = append(o.keys, k.(string))
+			o.values = append(o.values, v)
+		}
+		_, err = dec.Token()
+		return o, err
+	case '[':
+		arr := make([]interface{}, 0)
+		for dec.More() {
+			v, err := decodeOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, v)
+		}
+		_, err = dec.Token()
+		return arr, err
+	}
+	return nil, fmt.Errorf("unexpected delim %v", delim)
+}
+
+// isJSON report whether s looks like a json object or array
+func isJSON(s string) bool {
+	s = strings.TrimSpace(s)
+	return strings.HasPrefix(s, "{") || strings.HasPrefix(s, "[")
+}
@@ -0,0 +1,234 @@
+package postman
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/thewinds/mkdoc"
+	"github.com/thewinds/mkdoc/schema"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func init() {
+	mkdoc.RegisterDocScanner(new(Scanner))
+}
+
+// objects inferred from examples are loaded by the json loader
+const lang = "json"
+
+type Scanner struct {
+	filterTag string
+	path      string
+}
+
+func (s *Scanner) Scan(config mkdoc.DocScanConfig) (*mkdoc.DocScanResult, error) {
+	s.filterTag = config.Args["_filter_tag"]
+	s.path = config.Args["path"]
+	r := new(mkdoc.DocScanResult)
+	err := filepath.Walk(s.path, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || filepath.Ext(path) != ".json" {
+			return nil
+		}
+		coll, err := readCollection(path)
+		if err != nil {
+			return fmt.Errorf("postman: %s %v", path, err)
+		}
+		if coll == nil {
+			return nil
+		}
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return err
+		}
+		c := &converter{
+			fileName: absPath,
+			inferrer: &inferrer{idPrefix: idPrefix(s.path, path)},
+		}
+		var rootTags []string
+		if coll.Info.Name != "" {
+			rootTags = []string{coll.Info.Name}
+		}
+		c.convertItems(coll.Item, nil, rootTags)
+		for _, api := range c.apis {
+			if s.matchTag(api) {
+				r.APIs = append(r.APIs, api)
+			}
+		}
+		r.Objects = append(r.Objects, c.inferrer.objects...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// idPrefix returns the prefix of inferred object ids made up of the relative path of collection,
+// so the collections of the same name in different directories won't conflict
+//
+//	a/users.postman_collection.json => a_users
+func idPrefix(root string, path string) string {
+	name := filepath.Base(path)
+	name = strings.SplitN(name, ".", 2)[0]
+	rel, err := filepath.Rel(root, filepath.Dir(path))
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return name
+	}
+	return strings.Replace(filepath.ToSlash(rel), "/", "_", -1) + "_" + name
+}
+
+func (s *Scanner) matchTag(api *schema.API) bool {
+	if len(s.filterTag) == 0 {
+		return true
+	}
+	for _, tag := range api.Tags {
+		if tag == strings.TrimSpace(s.filterTag) {
+			return true
+		}
+	}
+	return false
+}
+
+// readCollection read a postman collection file
+// returns nil if the file is not a collection
+func readCollection(path string) (*collection, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.Contains(string(b), "getpostman.com") && !strings.Contains(string(b), "_postman_id") {
+		return nil, nil
+	}
+	coll := new(collection)
+	if err := json.Unmarshal(b, coll); err != nil {
+		return nil, err
+	}
+	if coll.Info == nil {
+		return nil, nil
+	}
+	return coll, nil
+}
+
+func (s *Scanner) Name() string {
+	return "postman"
+}
+
+func (s *Scanner) Help() string {
+	return "scan doc from postman collection json files"
+}
+
+type converter struct {
+	fileName string
+	inferrer *inferrer
+	apis     []*schema.API
+}
+
+// convertItems convert requests in items recursively,
+// folder names are used as tags,rootTags is used if request is not in any folder
+func (c *converter) convertItems(items []*item, folders []string, rootTags []string) {
+	for _, it := range items {
+		if it.Request == nil {
+			c.convertItems(it.Item, append(append([]string{}, folders...), it.Name), rootTags)
+			continue
+		}
+		tags := folders
+		if len(tags) == 0 {
+			tags = rootTags
+		}
+		c.apis = append(c.apis, c.convertRequest(it, tags))
+	}
+}
+
+func (c *converter) convertRequest(it *item, tags []string) *schema.API {
+	req := it.Request
+	api := &schema.API{
+		Name:           it.Name,
+		Desc:           string(req.Description),
+		Method:         strings.ToUpper(req.Method),
+		Type:           "http",
+		Tags:           tags,
+		SourceFileName: c.fileName,
+		Language:       lang,
+	}
+	if api.Desc == "" {
+		api.Desc = string(it.Description)
+	}
+	if api.Method == "" {
+		api.Method = "GET"
+	}
+	if req.URL != nil {
+		api.Path = req.URL.path()
+		for _, v := range req.URL.Variable {
+			api.PathParams = append(api.PathParams, &schema.Param{Name: v.Key, Desc: string(v.Description)})
+		}
+		for _, q := range req.URL.query() {
+			if !q.Disabled {
+				api.Query = append(api.Query, &schema.Param{Name: q.Key, Desc: string(q.Description), Default: q.Value})
+			}
+		}
+	}
+	for _, h := range req.Header {
+		if h.Disabled || strings.EqualFold(h.Key, "Content-Type") {
+			continue
+		}
+		api.Header = append(api.Header, &schema.Param{Name: h.Key, Desc: string(h.Description)})
+	}
+
+	if req.Body != nil {
+		switch req.Body.Mode {
+		case "raw":
+			if isJSON(req.Body.Raw) {
+				if obj := c.inferrer.infer("in", req.Body.Raw); obj != nil {
+					api.InType = obj.ID
+					api.MimeIn = "json"
+				}
+			}
+		case "formdata", "urlencoded":
+			params := req.Body.FormData
+			if req.Body.Mode == "urlencoded" {
+				params = req.Body.URLEncoded
+			}
+			obj := &schema.Object{
+				ID:       c.inferrer.newID("in"),
+				Type:     &schema.ObjectType{Name: "object"},
+				Language: lang,
+			}
+			for _, p := range params {
+				if p.Disabled {
+					continue
+				}
+				obj.Fields = append(obj.Fields, newField(p.Key, string(p.Description), &schema.ObjectType{Name: "string"}))
+			}
+			c.inferrer.objects = append(c.inferrer.objects, obj)
+			api.InType = obj.ID
+			api.MimeIn = "form"
+		}
+	}
+
+	if resp := pickResponse(it.Response); resp != nil && isJSON(resp.Body) {
+		if obj := c.inferrer.infer("out", resp.Body); obj != nil {
+			api.OutType = obj.ID
+			api.MimeOut = "json"
+		}
+	}
+	return api
+}
+
+// pickResponse pick the first 2xx example response
+// the first response will be used if there is no 2xx response
+func pickResponse(responses []*response) *response {
+	for _, resp := range responses {
+		if resp.Code >= 200 && resp.Code < 300 {
+			return resp
+		}
+	}
+	if len(responses) > 0 {
+		return responses[0]
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package postman
+
+import (
+	"github.com/thewinds/mkdoc"
+	"github.com/thewinds/mkdoc/schema"
+	"testing"
+)
+
+func TestScanner_Scan(t *testing.T) {
+	r, err := new(Scanner).Scan(mkdoc.DocScanConfig{Args: map[string]string{"path": "testdata"}})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(r.APIs) != 2 {
+		t.Errorf("want 2 apis got %d", len(r.APIs))
+		return
+	}
+	objects := make(map[string]*schema.Object)
+	for _, obj := range r.Objects {
+		objects[obj.ID] = obj
+	}
+
+	create := r.APIs[0]
+	if create.Path != "/api/user" || create.Method != "POST" || create.Desc != "create a user" ||
+		create.Tags[0] != "user" || create.Query.Get("from").Desc != "source" || create.Header.Get("token").Desc != "jwt token" {
+		t.Errorf("unexpected api %+v", create)
+	}
+	if create.Header.Get("Content-Type") != nil {
+		t.Errorf("content type should not be a header param")
+	}
+	in := objects[create.InType]
+	if in == nil || create.MimeIn != "json" || len(in.Fields) != 4 {
+		t.Errorf("unexpected in object %+v", in)
+		return
+	}
+	wantTypes := []string{"string", "int64", "object", "object"}
+	for i, field := range in.Fields {
+		if field.Type.Name != wantTypes[i] {
+			t.Errorf("field %s want type %s got %s", field.Name, wantTypes[i], field.Type.Name)
+		}
+	}
+	if tags := objects[in.Fields[2].Type.Ref]; tags == nil || !tags.Type.IsRepeated || tags.Type.Name != "string" {
+		t.Errorf("unexpected array object %+v", tags)
+	}
+	out := objects[create.OutType]
+	if out == nil || !out.Type.IsRepeated || objects[out.Type.Ref] == nil {
+		t.Errorf("unexpected out object %+v", out)
+	}
+
+	login := r.APIs[1]
+	if login.Path != "/api/login" || login.MimeIn != "form" || login.Tags[0] != "user service" {
+		t.Errorf("unexpected api %+v", login)
+	}
+	if form := objects[login.InType]; form == nil || form.Fields[0].Desc != "user name" {
+		t.Errorf("unexpected form object %+v", form)
+	}
+}
+
+func TestIdPrefix(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"testdata/user.postman_collection.json", "user"},
+		{"testdata/a/user.postman_collection.json", "a_user"},
+		{"testdata/b/c/user.postman_collection.json", "b_c_user"},
+	}
+	for _, tt := range tests {
+		if got := idPrefix("testdata", tt.path); got != tt.want {
+			t.Errorf("idPrefix(%s) got %s want %s", tt.path, got, tt.want)
+		}
+	}
+}
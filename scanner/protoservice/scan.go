@@ -0,0 +1,142 @@
+package protoservice
+
+import (
+	"fmt"
+	"github.com/thewinds/mkdoc"
+	"github.com/thewinds/mkdoc/objloader/protoloader"
+	"github.com/thewinds/mkdoc/schema"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+func init() {
+	mkdoc.RegisterDocScanner(new(Scanner))
+}
+
+// messages are loaded by the proto loader
+const lang = "proto"
+
+type Scanner struct {
+	filterTag string
+	path      string
+}
+
+func (s *Scanner) Scan(config mkdoc.DocScanConfig) (*mkdoc.DocScanResult, error) {
+	s.filterTag = config.Args["_filter_tag"]
+	s.path = config.Args["path"]
+	r := new(mkdoc.DocScanResult)
+	err := filepath.Walk(s.path, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || filepath.Ext(path) != ".proto" {
+			return nil
+		}
+		f, err := protoloader.ParseFile(path)
+		if err != nil {
+			return fmt.Errorf("protoservice: %v", err)
+		}
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return err
+		}
+		for _, service := range f.Services {
+			for _, rpc := range service.RPCs {
+				for _, api := range convertRPC(f, service, rpc, absPath) {
+					if s.matchTag(api) {
+						r.APIs = append(r.APIs, api)
+					}
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (s *Scanner) matchTag(api *schema.API) bool {
+	if len(s.filterTag) == 0 {
+		return true
+	}
+	for _, tag := range api.Tags {
+		if tag == strings.TrimSpace(s.filterTag) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Scanner) Name() string {
+	return "protoservice"
+}
+
+func (s *Scanner) Help() string {
+	return "scan doc from services in .proto files"
+}
+
+// convertRPC convert a rpc to a grpc api,
+// every `google.api.http` binding is converted to a http api as well
+func convertRPC(f *protoloader.ProtoFile, service *protoloader.Service, rpc *protoloader.RPC, fileName string) []*schema.API {
+	desc := rpc.Comment
+	if rpc.ClientStreaming {
+		desc = strings.TrimSpace(desc + "\n\nclient streaming")
+	}
+	if rpc.ServerStreaming {
+		desc = strings.TrimSpace(desc + "\n\nserver streaming")
+	}
+	newAPI := func() *schema.API {
+		return &schema.API{
+			Name:           rpc.Name,
+			Desc:           desc,
+			Tags:           []string{service.Name},
+			InType:         typeName(f.Package, rpc.InputType),
+			OutType:        typeName(f.Package, rpc.OutputType),
+			MimeOut:        "json",
+			SourceFileName: fileName,
+			Language:       lang,
+		}
+	}
+	api := newAPI()
+	api.Type = "grpc"
+	api.Method = "POST"
+	api.Path = fmt.Sprintf("/%s/%s", service.FullName, rpc.Name)
+	api.MimeIn = "json"
+	apis := []*schema.API{api}
+	for _, rule := range rpc.HTTPRules {
+		api := newAPI()
+		api.Type = "http"
+		api.Method = rule.Method
+		api.Path = pathTemplate(rule.Path)
+		if rule.Body != "" {
+			api.MimeIn = "json"
+		}
+		apis = append(apis, api)
+	}
+	return apis
+}
+
+// typeName returns the full name of message,
+// types without package are in the package of the file
+func typeName(pkg, typ string) string {
+	if strings.HasPrefix(typ, ".") {
+		return typ[1:]
+	}
+	if pkg != "" && !strings.Contains(typ, ".") {
+		return pkg + "." + typ
+	}
+	return typ
+}
+
+var pathVarPattern = regexp.MustCompile(`\{([^}=]+)=[^}]*\}`)
+
+// pathTemplate remove the segments pattern of path variables
+//
+//	/v1/{name=users/*}/books => /v1/{name}/books
+func pathTemplate(path string) string {
+	return pathVarPattern.ReplaceAllString(path, "{$1}")
+}
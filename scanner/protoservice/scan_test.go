@@ -0,0 +1,40 @@
+package protoservice
+
+import (
+	"github.com/thewinds/mkdoc"
+	"testing"
+)
+
+func TestScanner_Scan(t *testing.T) {
+	r, err := new(Scanner).Scan(mkdoc.DocScanConfig{Args: map[string]string{"path": "testdata"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name, typ, method, path, in, out, mimeIn string
+	}{
+		{"GetBook", "grpc", "POST", "/library.v1.BookService/GetBook", "library.v1.GetBookRequest", "library.v1.Book", "json"},
+		{"GetBook", "http", "GET", "/v1/{name}", "library.v1.GetBookRequest", "library.v1.Book", ""},
+		{"CreateBook", "grpc", "POST", "/library.v1.BookService/CreateBook", "library.v1.CreateBookRequest", "library.v1.Book", "json"},
+		{"CreateBook", "http", "POST", "/v1/{parent}/books", "library.v1.CreateBookRequest", "library.v1.Book", "json"},
+		{"CreateBook", "http", "PUT", "/v1/books", "library.v1.CreateBookRequest", "library.v1.Book", "json"},
+		{"DeleteBook", "grpc", "POST", "/library.v1.BookService/DeleteBook", "library.v1.DeleteBookRequest", "google.protobuf.Empty", "json"},
+		{"WatchBooks", "grpc", "POST", "/library.v1.BookService/WatchBooks", "library.v1.WatchRequest", "library.v1.Book", "json"},
+	}
+	if len(r.APIs) != len(tests) {
+		t.Fatalf("want %d apis got %d", len(tests), len(r.APIs))
+	}
+	for i, tt := range tests {
+		api := r.APIs[i]
+		if api.Name != tt.name || api.Type != tt.typ || api.Method != tt.method || api.Path != tt.path ||
+			api.InType != tt.in || api.OutType != tt.out || api.MimeIn != tt.mimeIn || api.Tags[0] != "BookService" {
+			t.Errorf("api %d: want %v got %+v", i, tt, api)
+		}
+	}
+	if desc := r.APIs[0].Desc; desc != "GetBook 获取图书" {
+		t.Errorf("unexpected desc %q", desc)
+	}
+	if desc := r.APIs[6].Desc; desc != "client streaming\n\nserver streaming" {
+		t.Errorf("unexpected desc %q", desc)
+	}
+}
@@ -1,21 +1,90 @@
 package schema
 
+import (
+	"encoding/json"
+	"sort"
+)
+
 type API struct {
-	Name           string            `json:"name"`
-	Desc           string            `json:"desc"`
-	Path           string            `json:"path"`
-	Method         string            `json:"method"` // post get delete patch ; query mutation
-	Type           string            `json:"type"`   // echo_handle graphql
-	Tags           []string          `json:"tags"`
-	Query          map[string]string `json:"query"`
-	Header         map[string]string `json:"header"`
-	InType         string            `json:"in_type"`
-	OutType        string            `json:"out_type"`
-	MimeIn         string            `json:"mime_in"`
-	MimeOut        string            `json:"mime_out"`
-	Source         string            `json:"src"`
-	SourceFileName string            `json:"src_file_name"`
-	SourceLineNum  int               `json:"src_line_num"`
-	Language       string            `json:"lang"`
-	Disables       []string          `json:"disables"`
+	Name           string      `json:"name"`
+	Desc           string      `json:"desc"`
+	Path           string      `json:"path"`
+	Method         string      `json:"method"` // post get delete patch ; query mutation
+	Type           string      `json:"type"`   // echo_handle graphql
+	Tags           []string    `json:"tags"`
+	PathParams     Params      `json:"path_params"`
+	Query          Params      `json:"query"`
+	QueryType      string      `json:"query_type"`
+	Header         Params      `json:"header"`
+	InType         string      `json:"in_type"`
+	OutType        string      `json:"out_type"`
+	Responses      []*Response `json:"responses"`
+	ErrorType      string      `json:"error_type"`
+	Errors         []string    `json:"errors"`
+	InExample      string      `json:"in_example"`
+	OutExample     string      `json:"out_example"`
+	MimeIn         string      `json:"mime_in"`
+	MimeOut        string      `json:"mime_out"`
+	Source         string      `json:"src"`
+	SourceFileName string      `json:"src_file_name"`
+	SourceLineNum  int         `json:"src_line_num"`
+	Language       string      `json:"lang"`
+	Disables       []string    `json:"disables"`
+}
+
+// Param is a parameter of api,Type is a builtin type,
+// Default and Enum are the default value and the allowed values
+type Param struct {
+	Name     string   `json:"name"`
+	Type     string   `json:"type"`
+	Desc     string   `json:"desc"`
+	Required bool     `json:"required,omitempty"`
+	Default  string   `json:"default,omitempty"`
+	Enum     []string `json:"enum,omitempty"`
+}
+
+// Params is the ordered parameters of api
+type Params []*Param
+
+// Get returns the parameter by name,returns nil if not found
+func (p Params) Get(name string) *Param {
+	for _, param := range p {
+		if param.Name == name {
+			return param
+		}
+	}
+	return nil
+}
+
+// UnmarshalJSON the params may be a map of name and description in the old versions,
+// they are sorted by name
+func (p *Params) UnmarshalJSON(b []byte) error {
+	var list []*Param
+	if err := json.Unmarshal(b, &list); err == nil {
+		*p = list
+		return nil
+	}
+	var m map[string]string
+	if err := json.Unmarshal(b, &m); err != nil {
+		return err
+	}
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	*p = make(Params, 0, len(names))
+	for _, name := range names {
+		*p = append(*p, &Param{Name: name, Desc: m[name]})
+	}
+	return nil
+}
+
+// Response is a response of api,Status is the http status code like 200,4xx or default,
+// Type is the type of response body
+type Response struct {
+	Status string `json:"status"`
+	Desc   string `json:"desc"`
+	Mime   string `json:"mime"`
+	Type   string `json:"type"`
 }
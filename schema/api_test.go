@@ -0,0 +1,19 @@
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParams_UnmarshalJSON(t *testing.T) {
+	var api API
+	if err := json.Unmarshal([]byte(`{"query":{"uid":"用户id","age":"年龄"},"header":[{"name":"token","type":"string","required":true}]}`), &api); err != nil {
+		t.Fatal(err)
+	}
+	if len(api.Query) != 2 || api.Query[0].Name != "age" || api.Query[1].Desc != "用户id" {
+		t.Errorf("query got %+v %+v", api.Query[0], api.Query[1])
+	}
+	if token := api.Header.Get("token"); token == nil || !token.Required {
+		t.Errorf("header got %+v", token)
+	}
+}
@@ -21,6 +21,7 @@ type ObjectType struct {
 	Name       string `json:"name"`
 	Ref        string `json:"ref"`
 	IsRepeated bool   `json:"is_repeated"`
+	MapKey     string `json:"map_key,omitempty"`
 }
 
 type Extension struct {